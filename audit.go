@@ -0,0 +1,198 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrAuditTampered is wrapped by errors returned by VerifyAudit for records which were modified, removed or reordered.
+var ErrAuditTampered = errors.New("audit log was tampered with")
+
+// AuditLog is an append-only trail of privileged operations, kept separately from normal logs. Records are JSON lines
+// chained using SHA-256 hashes: each record contains the hash of the previous one, so modifying, removing or
+// reordering records can be detected using VerifyAudit. See Logger.Audit.
+type AuditLog struct {
+	mu    sync.Mutex
+	w     io.Writer
+	seq   uint64
+	prev  string
+	clock Clock
+}
+
+// auditRecord is a single record of AuditLog. Hash is computed over the record encoded without it.
+type auditRecord struct {
+	Seq    uint64          `json:"seq"`
+	Time   string          `json:"time"`
+	Event  string          `json:"event"`
+	Tags   []string        `json:"tags"`
+	Fields json.RawMessage `json:"fields"`
+	RunID  string          `json:"run_id"`
+	Prev   string          `json:"prev"`
+	Hash   string          `json:"hash,omitempty"`
+}
+
+// NewAuditLog creates new AuditLog starting a new chain of records written to w.
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{w: w, clock: systemClock{}}
+}
+
+// OpenAuditFile opens the file for appending records, creating it if needed. The chain is continued from the last
+// record of the file, which is verified first. AuditLog should be closed when no longer needed.
+func OpenAuditFile(path string) (*AuditLog, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	a := NewAuditLog(f)
+	last, err := verifyAudit(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if last != nil {
+		a.seq, a.prev = last.Seq, last.Hash
+	}
+	return a, nil
+}
+
+// Close closes the output, if it implements io.Closer.
+func (a *AuditLog) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if c, ok := a.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// write appends the record to the chain.
+func (a *AuditLog) write(event string, tags []string, fields []Field) error {
+	encodedFields, err := marshalFields(fields)
+	if err != nil {
+		return err
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	r := auditRecord{
+		Seq:    a.seq + 1,
+		Time:   a.clock.Now().UTC().Format(time.RFC3339Nano),
+		Event:  event,
+		Tags:   tags,
+		Fields: encodedFields,
+		RunID:  runID,
+		Prev:   a.prev,
+	}
+	if r.Hash, err = auditHash(r); err != nil {
+		return err
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if _, err := a.w.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	a.seq, a.prev = r.Seq, r.Hash
+	return nil
+}
+
+// auditHash returns hash of the record encoded without its hash.
+func auditHash(r auditRecord) (string, error) {
+	r.Hash = ""
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// VerifyAudit reads records written by AuditLog and checks their chain. It returns an error wrapping ErrAuditTampered
+// describing the first invalid record.
+func VerifyAudit(r io.Reader) error {
+	_, err := verifyAudit(r)
+	return err
+}
+
+// verifyAudit checks the chain and returns the last record, nil if there are no records.
+func verifyAudit(r io.Reader) (*auditRecord, error) {
+	var last *auditRecord
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 16<<20)
+	for line := 1; s.Scan(); line++ {
+		data := bytes.TrimSpace(s.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		var rec auditRecord
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrAuditTampered, line, err)
+		}
+		prev, seq := "", uint64(0)
+		if last != nil {
+			prev, seq = last.Hash, last.Seq
+		}
+		hash, err := auditHash(rec)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case rec.Hash != hash:
+			return nil, fmt.Errorf("%w: line %d: hash mismatch", ErrAuditTampered, line)
+		case rec.Prev != prev || rec.Seq != seq+1:
+			return nil, fmt.Errorf("%w: line %d: broken chain", ErrAuditTampered, line)
+		}
+		last = &rec
+	}
+	return last, s.Err()
+}
+
+// WithAudit creates new logger instance writing records of Audit to the audit log.
+func (l *Logger) WithAudit(a *AuditLog) *Logger {
+	n := l.clone()
+	n.audit = a
+	return n
+}
+
+// Audit records a privileged operation in the audit log (see WithAudit) and writes it as a normal line, with the
+// event as a message. Scrubbing and redaction rules of the logger apply to the audit record as well. Unlike other
+// methods, it returns an error if the record couldn't be written to the audit log, so commands can refuse to continue
+// without an audit trail.
+func (l *Logger) Audit(event string, fields ...Field) error {
+	l.mu.RLock()
+	a, tags, redactions, scrubber := l.audit, l.renderedTags(), l.redactions, l.scrubber
+	all := append(l.fields[:len(l.fields):len(l.fields)], expandErrors(fields)...)
+	l.mu.RUnlock()
+
+	if a == nil {
+		return errors.New("audit log is not configured")
+	}
+	recorded := event
+	if scrubber != nil {
+		all = scrubber.scrub(all)
+	}
+	if len(redactions) > 0 {
+		// Notes are written by Print below
+		notes := map[string]bool{}
+		recorded = redactString(redactions, recorded, notes)
+		all = redactFields(redactions, all, notes)
+	}
+	if err := a.write(recorded, tags, all); err != nil {
+		return err
+	}
+	l.WithFields(fields...).Print(event)
+	return nil
+}
@@ -0,0 +1,80 @@
+package logger_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestAudit(t *testing.T) {
+	t.Run("write chained records alongside normal lines", func(t *testing.T) {
+		var b, audit bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.PlainMode).WithTags("deploy").WithField("user", "alice").WithAudit(log.NewAuditLog(&audit))
+
+		assert.NoError(t, l.Audit("secret rotated", log.Field{Key: "name", Value: "db"}))
+		assert.NoError(t, l.Audit("release deleted"))
+
+		assert.Equal(t, "[INFO][DEPLOY] secret rotated user=alice name=db\n[INFO][DEPLOY] release deleted user=alice\n", b.String())
+		lines := strings.Split(strings.TrimSuffix(audit.String(), "\n"), "\n")
+		if assert.Len(t, lines, 2) {
+			assert.Contains(t, lines[0], `"seq":1,`)
+			assert.Contains(t, lines[0], `"event":"secret rotated","tags":["deploy"],"fields":{"name":"db","user":"alice"}`)
+			assert.Contains(t, lines[1], `"seq":2,`)
+		}
+		assert.NoError(t, log.VerifyAudit(strings.NewReader(audit.String())))
+	})
+
+	t.Run("redact audit records", func(t *testing.T) {
+		var b, audit bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.PlainMode).WithRedaction(log.RedactionPreset("password-param")...).WithAudit(log.NewAuditLog(&audit))
+
+		assert.NoError(t, l.Audit("login password=hunter2", log.Field{Key: "url", Value: "/?token=abc"}))
+
+		assert.Equal(t, "[INFO] login password=[REDACTED] url=\"/?token=[REDACTED]\"\n", b.String())
+		assert.Contains(t, audit.String(), `"event":"login password=[REDACTED]","tags":[],"fields":{"url":"/?token=[REDACTED]"}`)
+		assert.NotContains(t, audit.String(), "hunter2")
+		assert.NoError(t, log.VerifyAudit(strings.NewReader(audit.String())))
+	})
+
+	t.Run("detect tampering", func(t *testing.T) {
+		var audit bytes.Buffer
+		l := log.New(&bytes.Buffer{}).WithAudit(log.NewAuditLog(&audit))
+		for _, event := range []string{"a", "b", "c"} {
+			assert.NoError(t, l.Audit(event))
+		}
+		lines := strings.SplitAfter(audit.String(), "\n")
+
+		modified := strings.Replace(audit.String(), `"event":"b"`, `"event":"x"`, 1)
+		assert.ErrorIs(t, log.VerifyAudit(strings.NewReader(modified)), log.ErrAuditTampered)
+		removed := lines[0] + lines[2]
+		assert.ErrorIs(t, log.VerifyAudit(strings.NewReader(removed)), log.ErrAuditTampered)
+	})
+
+	t.Run("continue chain of audit file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		for _, event := range []string{"a", "b"} {
+			a, err := log.OpenAuditFile(path)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.NoError(t, log.New(&bytes.Buffer{}).WithAudit(a).Audit(event))
+			assert.NoError(t, a.Close())
+		}
+
+		f, err := os.Open(path)
+		if assert.NoError(t, err) {
+			defer f.Close()
+			assert.NoError(t, log.VerifyAudit(f))
+		}
+	})
+
+	t.Run("fail without audit log", func(t *testing.T) {
+		assert.Error(t, log.New(&bytes.Buffer{}).Audit("foo"))
+	})
+}
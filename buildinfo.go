@@ -0,0 +1,41 @@
+package logger
+
+import "runtime/debug"
+
+// WithBuildInfo creates new logger instance with "version" and "revision" fields describing the binary, so logs always
+// identify the exact build producing them. Values are read from debug.ReadBuildInfo, fields which are not available
+// (e.g. binary was built outside a VCS checkout) are omitted. Revision is suffixed with "-dirty" if the binary was
+// built from a modified checkout.
+func (l *Logger) WithBuildInfo() *Logger {
+	return l.WithFields(buildInfoFields()...)
+}
+
+func buildInfoFields() []Field {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+
+	fields := []Field{}
+	if info.Main.Version != "" {
+		fields = append(fields, Field{Key: "version", Value: info.Main.Version})
+	}
+
+	var revision, modified string
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			modified = s.Value
+		}
+	}
+	if revision != "" {
+		if modified == "true" {
+			revision += "-dirty"
+		}
+		fields = append(fields, Field{Key: "revision", Value: revision})
+	}
+
+	return fields
+}
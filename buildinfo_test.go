@@ -0,0 +1,25 @@
+package logger_test
+
+import (
+	"bytes"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestWithBuildInfo(t *testing.T) {
+	var b bytes.Buffer
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		t.Skip("build info is not available")
+	}
+
+	fields := log.New(&b).WithField("foo", "bar").WithBuildInfo().Fields()
+
+	assert.Equal(t, log.Field{Key: "foo", Value: "bar"}, fields[0])
+	assert.Equal(t, log.Field{Key: "version", Value: info.Main.Version}, fields[1])
+}
@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"os"
+	"strings"
+)
+
+// Catalog maps message keys to format strings (see fmt.Printf), it is used by Logger.Msg to write localized messages.
+type Catalog map[string]string
+
+// WithCatalog creates new logger instance rendering messages written by Msg using the catalog.
+func (l *Logger) WithCatalog(catalog Catalog) *Logger {
+	n := l.clone()
+	n.catalog = catalog
+	return n
+}
+
+// Msg writes a message identified by key, rendered using format string found in the catalog of a logger (see
+// WithCatalog), e.g. l.Msg("deploy.start", release). If the key is missing, the key and arguments are written instead.
+func (l *Logger) Msg(key string, args ...interface{}) *Logger {
+	l.mu.RLock()
+	format, ok := l.catalog[key]
+	l.mu.RUnlock()
+
+	if !ok {
+		return l.Print(strings.TrimSuffix(safeSprintln(append([]interface{}{key}, args...)...), "\n"))
+	}
+	return l.Printf(format, args...)
+}
+
+// Locale returns locale selected by the LC_ALL, LC_MESSAGES or LANG environment variable (the first one which is set),
+// without encoding, e.g. "pl_PL". It returns empty string for the default "C" and "POSIX" locales.
+func Locale() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(name)
+		if v == "" {
+			continue
+		}
+		if i := strings.IndexAny(v, ".@"); i >= 0 {
+			v = v[:i]
+		}
+		if v == "C" || v == "POSIX" {
+			return ""
+		}
+		return v
+	}
+	return ""
+}
+
+// SelectCatalog returns catalog for the locale (e.g. "pl_PL"), falling back to the catalog for its language ("pl").
+// It returns nil if there is no matching catalog.
+func SelectCatalog(catalogs map[string]Catalog, locale string) Catalog {
+	if c, ok := catalogs[locale]; ok {
+		return c
+	}
+	if i := strings.IndexAny(locale, "_-"); i >= 0 {
+		return catalogs[locale[:i]]
+	}
+	return nil
+}
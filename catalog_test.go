@@ -0,0 +1,67 @@
+package logger_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestMsg(t *testing.T) {
+	catalogs := map[string]log.Catalog{
+		"en": {"deploy.start": "Deploying %s"},
+		"pl": {"deploy.start": "Wdrażanie %s"},
+	}
+
+	var b bytes.Buffer
+	l := log.New(&b).WithOutputMode(log.PlainMode)
+
+	l.WithCatalog(log.SelectCatalog(catalogs, "en")).Msg("deploy.start", "api")
+	l.WithCatalog(log.SelectCatalog(catalogs, "pl_PL")).Msg("deploy.start", "api")
+	l.WithCatalog(log.SelectCatalog(catalogs, "pl_PL")).Msg("deploy.done", "api", 3)
+	l.Msg("deploy.start")
+
+	assert.Equal(t, ""+
+		"[INFO] Deploying api\n"+
+		"[INFO] Wdrażanie api\n"+
+		"[INFO] deploy.done api 3\n"+
+		"[INFO] deploy.start\n", b.String())
+}
+
+func TestSelectCatalog(t *testing.T) {
+	catalogs := map[string]log.Catalog{
+		"pt":    {"hello": "Olá"},
+		"pt_BR": {"hello": "Oi"},
+	}
+
+	assert.Equal(t, catalogs["pt_BR"], log.SelectCatalog(catalogs, "pt_BR"))
+	assert.Equal(t, catalogs["pt"], log.SelectCatalog(catalogs, "pt_PT"))
+	assert.Equal(t, catalogs["pt"], log.SelectCatalog(catalogs, "pt"))
+	assert.Nil(t, log.SelectCatalog(catalogs, "de_DE"))
+	assert.Nil(t, log.SelectCatalog(catalogs, ""))
+}
+
+func TestLocale(t *testing.T) {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v, ok := os.LookupEnv(name); ok {
+			defer os.Setenv(name, v)
+		} else {
+			defer os.Unsetenv(name)
+		}
+		os.Unsetenv(name)
+	}
+
+	assert.Equal(t, "", log.Locale())
+
+	os.Setenv("LANG", "pl_PL.UTF-8")
+	assert.Equal(t, "pl_PL", log.Locale())
+
+	os.Setenv("LC_MESSAGES", "de_DE@euro")
+	assert.Equal(t, "de_DE", log.Locale())
+
+	os.Setenv("LC_ALL", "C.UTF-8")
+	assert.Equal(t, "", log.Locale())
+}
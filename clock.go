@@ -0,0 +1,75 @@
+package logger
+
+import "time"
+
+// TimeField is the key of the field holding timestamps of lines, see Logger.WithTimestamp.
+const TimeField = "time"
+
+// Clock provides current time to timestamps, heartbeats and timers (e.g. Step, TimeTrack). It can be replaced in tests
+// to make output deterministic.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Clock returns clock used by a logger.
+func (l *Logger) Clock() Clock {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.clock
+}
+
+// WithClock creates new logger instance using specified clock. Nil restores the system clock.
+func (l *Logger) WithClock(clock Clock) *Logger {
+	if clock == nil {
+		clock = systemClock{}
+	}
+	n := l.clone()
+	n.clock = clock
+	return n
+}
+
+// TimestampLayout returns layout of timestamps added to lines, empty if timestamps are disabled.
+func (l *Logger) TimestampLayout() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.timeLayout
+}
+
+// WithTimestamp creates new logger instance adding timestamps formatted using layout (see time.Layout) to the "time"
+// field of each line, e.g. WithTimestamp(time.RFC3339Nano). Timestamps use time zone of the logger (see WithTimeZone).
+// Empty layout disables timestamps.
+func (l *Logger) WithTimestamp(layout string) *Logger {
+	n := l.clone()
+	n.timeLayout = layout
+	return n
+}
+
+// TimeZone returns time zone of timestamps written by a logger.
+func (l *Logger) TimeZone() *time.Location {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.location
+}
+
+// WithTimeZone creates new logger instance writing timestamps in specified time zone, e.g. time.Local or a fixed zone
+// created using time.FixedZone. By default timestamps are written in UTC, so logs from machines in different zones are
+// easy to correlate. Nil restores UTC.
+func (l *Logger) WithTimeZone(location *time.Location) *Logger {
+	if location == nil {
+		location = time.UTC
+	}
+	n := l.clone()
+	n.location = location
+	return n
+}
+
+// since returns time elapsed since start according to the clock of a logger.
+func (l *Logger) since(start time.Time) time.Duration {
+	return l.Clock().Now().Sub(start)
+}
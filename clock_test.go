@@ -0,0 +1,84 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+// fakeClock returns time which is moved forward manually.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Add(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestWithTimestamp(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2021, 3, 4, 5, 6, 7, 890000000, time.UTC)}
+
+	var b bytes.Buffer
+	l := log.New(&b).WithOutputMode(log.PlainMode).WithClock(clock).WithTimestamp(time.RFC3339Nano)
+
+	l.WithField("key", "value").Print("foo")
+	clock.Add(time.Second)
+	l.WithTimestamp(time.Kitchen).Print("bar")
+	l.WithTimestamp("").Print("baz")
+
+	assert.Equal(t, time.RFC3339Nano, l.TimestampLayout())
+	assert.Equal(t, ""+
+		"[INFO] foo key=value time=2021-03-04T05:06:07.89Z\n"+
+		"[INFO] bar time=5:06AM\n"+
+		"[INFO] baz\n", b.String())
+}
+
+func TestWithClock(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)}
+
+	var b bytes.Buffer
+	l := log.New(&b).WithOutputMode(log.PlainMode).WithClock(clock)
+	assert.Equal(t, clock, l.Clock())
+
+	step := l.Step("Pushing image")
+	clock.Add(1200 * time.Millisecond)
+	step.Done()
+
+	done := log.TimeTrack(l, log.InfoLevel, "Build")
+	clock.Add(3 * time.Minute)
+	done()
+
+	assert.Equal(t, ""+
+		"[INFO] Pushing image...\n"+
+		"[INFO] ✓ Pushing image (1.2s)\n"+
+		"[INFO] Build took 3m0s\n", b.String())
+
+	assert.NotEqual(t, clock, l.WithClock(nil).Clock())
+}
+
+func TestWithTimeZone(t *testing.T) {
+	warsaw := time.FixedZone("CET", 3600)
+	clock := &fakeClock{now: time.Date(2021, 3, 4, 5, 6, 7, 0, warsaw)}
+
+	var b bytes.Buffer
+	l := log.New(&b).WithOutputMode(log.PlainMode).WithClock(clock).WithTimestamp(time.RFC3339)
+
+	l.Print("utc")
+	l.WithTimeZone(warsaw).Print("fixed")
+	l.WithTimeZone(warsaw).WithTimeZone(nil).Print("restored")
+
+	assert.Equal(t, time.UTC, l.TimeZone())
+	assert.Equal(t, time.Local, l.WithTimeZone(time.Local).TimeZone())
+	assert.Equal(t, ""+
+		"[INFO] utc time=2021-03-04T04:06:07Z\n"+
+		"[INFO] fixed time=2021-03-04T05:06:07+01:00\n"+
+		"[INFO] restored time=2021-03-04T04:06:07Z\n", b.String())
+}
@@ -0,0 +1,309 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Limits of PutLogEvents, see https://docs.aws.amazon.com/AmazonCloudWatchLogs/latest/APIReference/API_PutLogEvents.html.
+const (
+	// CloudWatchMaxBatchEvents is the maximum number of events sent in one batch.
+	CloudWatchMaxBatchEvents = 10000
+	// CloudWatchMaxBatchBytes is the maximum size of a batch, counted as the sum of messages plus
+	// CloudWatchEventOverhead for each event.
+	CloudWatchMaxBatchBytes = 1048576
+	// CloudWatchMaxEventBytes is the maximum size of a single event, including CloudWatchEventOverhead.
+	CloudWatchMaxEventBytes = 262144
+	// CloudWatchEventOverhead is the number of bytes added to the size of each event.
+	CloudWatchEventOverhead = 26
+	// CloudWatchMaxBatchSpan is the maximum time span between events in one batch.
+	CloudWatchMaxBatchSpan = 24 * time.Hour
+)
+
+// CloudWatchMaxQueuedBatches limits the number of full batches kept by CloudWatchSink, e.g. when sending fails. The
+// oldest batches are dropped first.
+const CloudWatchMaxQueuedBatches = 16
+
+// CloudWatchEvent is an event sent to CloudWatch Logs.
+type CloudWatchEvent struct {
+	Timestamp time.Time
+	Message   string
+}
+
+// CloudWatchClient sends events to CloudWatch Logs. It is usually a thin adapter over PutLogEvents of the AWS SDK,
+// which keeps the SDK out of dependencies of this module. It returns the next sequence token, or
+// CloudWatchSequenceTokenError if the sequence token is not the expected one.
+type CloudWatchClient interface {
+	PutLogEvents(ctx context.Context, group, stream string, events []CloudWatchEvent, sequenceToken string) (string, error)
+}
+
+// CloudWatchSequenceTokenError should be returned by CloudWatchClient in case of InvalidSequenceTokenException or
+// DataAlreadyAcceptedException, so CloudWatchSink can recover the sequence token.
+type CloudWatchSequenceTokenError struct {
+	// ExpectedSequenceToken is the sequence token reported by CloudWatch Logs.
+	ExpectedSequenceToken string
+	// AlreadyAccepted reports whether the batch was already accepted (DataAlreadyAcceptedException).
+	AlreadyAccepted bool
+}
+
+func (e *CloudWatchSequenceTokenError) Error() string {
+	if e.AlreadyAccepted {
+		return "batch of log events already accepted"
+	}
+	return fmt.Sprintf("invalid sequence token, expected %q", e.ExpectedSequenceToken)
+}
+
+// CloudWatchSink sends records to a CloudWatch Logs stream as JSON objects, e.g.:
+//
+//	sink := log.NewCloudWatchSink(client, "/klio/deploy", log.RunID(), 5*time.Second)
+//	defer sink.Close()
+//	l = l.WithSink(sink)
+//
+// Records are sent in batches respecting limits of PutLogEvents, at least every flushInterval, when a batch is full
+// and when Flush or Close is called. Batches are sent one at a time, in the order they were queued. Messages exceeding
+// CloudWatchMaxEventBytes are truncated. Batches which failed to be sent are queued again and retried by the next
+// flush, errors are returned by the next call to WriteRecord. Events dropped because of CloudWatchMaxQueuedBatches or
+// left unsent by Close are counted by Dropped.
+type CloudWatchSink struct {
+	mu         sync.Mutex
+	flushMu    sync.Mutex
+	client     CloudWatchClient
+	group      string
+	stream     string
+	token      string
+	pending    []CloudWatchEvent
+	size       int
+	full       [][]CloudWatchEvent
+	dropped    int64
+	err        error
+	wake       chan struct{}
+	done       chan struct{}
+	closed     bool
+	unregister func()
+}
+
+// NewCloudWatchSink creates new CloudWatchSink sending records to the stream of the log group. Both have to exist.
+// The sink should be closed when no longer needed.
+func NewCloudWatchSink(client CloudWatchClient, group, stream string, flushInterval time.Duration) *CloudWatchSink {
+	s := &CloudWatchSink{
+		client: client,
+		group:  group,
+		stream: stream,
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	s.unregister = registerFlusher(func() { s.Flush() })
+	go s.run(flushInterval)
+	return s
+}
+
+// WriteRecord queues the record, see Sink.
+func (s *CloudWatchSink) WriteRecord(t time.Time, r Record) error {
+	msg, err := encodeCloudWatchMessage(r)
+	if err != nil {
+		return err
+	}
+	size := len(msg) + CloudWatchEventOverhead
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return io.ErrClosedPipe
+	}
+	if n := len(s.pending); n > 0 && (n == CloudWatchMaxBatchEvents || s.size+size > CloudWatchMaxBatchBytes ||
+		absDuration(t.Sub(s.pending[0].Timestamp)) >= CloudWatchMaxBatchSpan) {
+		s.queue(s.pending)
+		s.pending, s.size = nil, 0
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+	s.pending = append(s.pending, CloudWatchEvent{Timestamp: t, Message: msg})
+	s.size += size
+
+	err, s.err = s.err, nil
+	return err
+}
+
+// Flush sends all queued records. If a batch fails to be sent, it is queued again together with the following ones.
+func (s *CloudWatchSink) Flush() error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	s.mu.Lock()
+	batches := s.full
+	if len(s.pending) > 0 {
+		batches = append(batches, s.pending)
+	}
+	s.full, s.pending, s.size = nil, nil, 0
+	s.mu.Unlock()
+
+	for n, batch := range batches {
+		if err := s.send(batch); err != nil {
+			s.mu.Lock()
+			full := s.full
+			s.full = nil
+			for _, b := range append(batches[n:], full...) {
+				s.queue(b)
+			}
+			s.mu.Unlock()
+			return err
+		}
+	}
+	return nil
+}
+
+// Dropped returns the number of events which were dropped, because too many batches were queued or they weren't sent
+// before the sink was closed.
+func (s *CloudWatchSink) Dropped() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// queue adds full batch to the queue, dropping the oldest batch if there are too many. Caller must hold s.mu.
+func (s *CloudWatchSink) queue(batch []CloudWatchEvent) {
+	if len(s.full) == CloudWatchMaxQueuedBatches {
+		s.dropped += int64(len(s.full[0]))
+		s.full = s.full[1:]
+	}
+	s.full = append(s.full, batch)
+}
+
+// Close sends all queued records and stops the sink.
+func (s *CloudWatchSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	close(s.done)
+	s.unregister()
+	err := s.Flush()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, batch := range s.full {
+		s.dropped += int64(len(batch))
+	}
+	s.full = nil
+	return err
+}
+
+func (s *CloudWatchSink) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+		if err := s.Flush(); err != nil {
+			s.mu.Lock()
+			s.err = err
+			s.mu.Unlock()
+		}
+	}
+}
+
+// send sends the batch, retrying once with the expected sequence token if it was invalid. Caller must hold s.flushMu.
+func (s *CloudWatchSink) send(batch []CloudWatchEvent) error {
+	sort.SliceStable(batch, func(i, j int) bool { return batch[i].Timestamp.Before(batch[j].Timestamp) })
+
+	for attempt := 0; ; attempt++ {
+		token, err := s.client.PutLogEvents(context.Background(), s.group, s.stream, batch, s.token)
+		var tokenErr *CloudWatchSequenceTokenError
+		if !errors.As(err, &tokenErr) {
+			if err == nil {
+				s.token = token
+			}
+			return err
+		}
+		s.token = tokenErr.ExpectedSequenceToken
+		if tokenErr.AlreadyAccepted {
+			return nil
+		}
+		if attempt > 0 {
+			return err
+		}
+	}
+}
+
+// encodeCloudWatchMessage returns the record encoded as JSON, truncating the message (and dropping fields if it is not
+// enough) to fit in CloudWatchMaxEventBytes.
+func encodeCloudWatchMessage(r Record) (string, error) {
+	tags := r.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	fields, err := marshalFields(r.Fields)
+	if err != nil {
+		return "", err
+	}
+	event := struct {
+		Level   Level           `json:"level"`
+		Tags    []string        `json:"tags"`
+		Message string          `json:"message"`
+		Fields  json.RawMessage `json:"fields"`
+		RunID   string          `json:"run_id"`
+	}{r.Level, tags, r.Message, fields, runID}
+
+	limit := CloudWatchMaxEventBytes - CloudWatchEventOverhead
+	data, err := json.Marshal(event)
+	if err != nil || len(data) <= limit {
+		return string(data), err
+	}
+	for len(data) > limit && event.Message != "" {
+		msg := strings.TrimSuffix(event.Message, truncatedSuffix)
+		event.Message = truncateUTF8(msg, len(msg)-(len(data)-limit)-len(truncatedSuffix)) + truncatedSuffix
+		if event.Message == truncatedSuffix {
+			event.Message = ""
+		}
+		if data, err = json.Marshal(event); err != nil {
+			return "", err
+		}
+	}
+	if len(data) <= limit {
+		return string(data), nil
+	}
+	event.Fields = json.RawMessage("{}")
+	data, err = json.Marshal(event)
+	return string(data), err
+}
+
+const truncatedSuffix = "…"
+
+// truncateUTF8 returns at most n first bytes of s, without splitting runes.
+func truncateUTF8(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if n >= len(s) {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
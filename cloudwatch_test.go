@@ -0,0 +1,187 @@
+package logger_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+type cloudWatchCall struct {
+	group, stream, token string
+	events               []log.CloudWatchEvent
+}
+
+type fakeCloudWatch struct {
+	mu    sync.Mutex
+	calls []cloudWatchCall
+	errs  []error
+}
+
+func (c *fakeCloudWatch) PutLogEvents(ctx context.Context, group, stream string, events []log.CloudWatchEvent, token string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, cloudWatchCall{group, stream, token, events})
+	if len(c.errs) > 0 {
+		err := c.errs[0]
+		c.errs = c.errs[1:]
+		if err != nil {
+			return "", err
+		}
+	}
+	return "token" + string(rune('0'+len(c.calls))), nil
+}
+
+func (c *fakeCloudWatch) Calls() []cloudWatchCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]cloudWatchCall(nil), c.calls...)
+}
+
+func TestCloudWatchSink(t *testing.T) {
+	start := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	t.Run("send records in batches", func(t *testing.T) {
+		client := &fakeCloudWatch{}
+		sink := log.NewCloudWatchSink(client, "/klio", "run", time.Hour)
+		defer sink.Close()
+
+		clock := &fakeClock{now: start}
+		l := log.New(io.Discard).WithClock(clock).WithSink(sink)
+		l.WithTags("deploy").WithField("release", "api").Print("foo")
+		clock.Add(-time.Second)
+		l.WithLevel(log.ErrorLevel).Print("bar")
+		assert.NoError(t, sink.Flush())
+		l.Print("baz")
+		assert.NoError(t, sink.Flush())
+
+		calls := client.Calls()
+		if assert.Len(t, calls, 2) {
+			assert.Equal(t, "/klio", calls[0].group)
+			assert.Equal(t, "run", calls[0].stream)
+			assert.Equal(t, "", calls[0].token)
+			assert.Equal(t, []log.CloudWatchEvent{
+				{Timestamp: start.Add(-time.Second), Message: `{"level":"error","tags":[],"message":"bar","fields":{},"run_id":"` + log.RunID() + `"}`},
+				{Timestamp: start, Message: `{"level":"info","tags":["deploy"],"message":"foo","fields":{"release":"api"},"run_id":"` + log.RunID() + `"}`},
+			}, calls[0].events)
+			assert.Equal(t, "token1", calls[1].token)
+			assert.Len(t, calls[1].events, 1)
+		}
+	})
+
+	t.Run("split batches exceeding limits", func(t *testing.T) {
+		client := &fakeCloudWatch{}
+		sink := log.NewCloudWatchSink(client, "/klio", "run", time.Hour)
+
+		for i := 0; i < log.CloudWatchMaxBatchEvents+1; i++ {
+			assert.NoError(t, sink.WriteRecord(start, log.Record{Level: log.InfoLevel, Message: "foo"}))
+		}
+		assert.NoError(t, sink.WriteRecord(start.Add(log.CloudWatchMaxBatchSpan), log.Record{Level: log.InfoLevel}))
+		assert.NoError(t, sink.Close())
+
+		var sizes []int
+		for _, c := range client.Calls() {
+			sizes = append(sizes, len(c.events))
+		}
+		assert.Equal(t, []int{log.CloudWatchMaxBatchEvents, 1, 1}, sizes)
+	})
+
+	t.Run("truncate large messages", func(t *testing.T) {
+		client := &fakeCloudWatch{}
+		sink := log.NewCloudWatchSink(client, "/klio", "run", time.Hour)
+
+		assert.NoError(t, sink.WriteRecord(start, log.Record{Level: log.InfoLevel, Message: strings.Repeat("ą", log.CloudWatchMaxEventBytes)}))
+		assert.NoError(t, sink.Close())
+
+		msg := client.Calls()[0].events[0].Message
+		assert.LessOrEqual(t, len(msg)+log.CloudWatchEventOverhead, log.CloudWatchMaxEventBytes)
+		var event map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(msg), &event))
+		assert.True(t, strings.HasSuffix(event["message"].(string), "ą…"))
+	})
+
+	t.Run("recover sequence token", func(t *testing.T) {
+		client := &fakeCloudWatch{errs: []error{
+			&log.CloudWatchSequenceTokenError{ExpectedSequenceToken: "expected"},
+			nil,
+			&log.CloudWatchSequenceTokenError{ExpectedSequenceToken: "accepted", AlreadyAccepted: true},
+			errors.New("throttled"),
+		}}
+		sink := log.NewCloudWatchSink(client, "/klio", "run", time.Hour)
+		defer sink.Close()
+
+		record := log.Record{Level: log.InfoLevel, Message: "foo"}
+		assert.NoError(t, sink.WriteRecord(start, record))
+		assert.NoError(t, sink.Flush())
+		assert.NoError(t, sink.WriteRecord(start, record))
+		assert.NoError(t, sink.Flush())
+		assert.NoError(t, sink.WriteRecord(start, record))
+		assert.EqualError(t, sink.Flush(), "throttled")
+
+		var tokens []string
+		for _, c := range client.Calls() {
+			tokens = append(tokens, c.token)
+		}
+		assert.Equal(t, []string{"", "expected", "token2", "accepted"}, tokens)
+	})
+
+	t.Run("retry failed batches in order", func(t *testing.T) {
+		client := &fakeCloudWatch{errs: []error{errors.New("throttled")}}
+		sink := log.NewCloudWatchSink(client, "/klio", "run", time.Hour)
+		defer sink.Close()
+
+		assert.NoError(t, sink.WriteRecord(start, log.Record{Level: log.InfoLevel, Message: "foo"}))
+		assert.EqualError(t, sink.Flush(), "throttled")
+		assert.NoError(t, sink.WriteRecord(start, log.Record{Level: log.InfoLevel, Message: "bar"}))
+		assert.NoError(t, sink.Flush())
+
+		var messages []string
+		for _, c := range client.Calls() {
+			for _, e := range c.events {
+				var event map[string]interface{}
+				json.Unmarshal([]byte(e.Message), &event)
+				messages = append(messages, event["message"].(string))
+			}
+		}
+		assert.Equal(t, []string{"foo", "foo", "bar"}, messages)
+		assert.Equal(t, int64(0), sink.Dropped())
+	})
+
+	t.Run("drop the oldest batches when too many are queued", func(t *testing.T) {
+		client := &fakeCloudWatch{}
+		for n := 0; n <= log.CloudWatchMaxQueuedBatches; n++ {
+			client.errs = append(client.errs, errors.New("throttled"))
+		}
+		sink := log.NewCloudWatchSink(client, "/klio", "run", time.Hour)
+
+		for n := 0; n <= log.CloudWatchMaxQueuedBatches; n++ {
+			assert.NoError(t, sink.WriteRecord(start, log.Record{Level: log.InfoLevel, Message: "foo"}))
+			assert.Error(t, sink.Flush())
+		}
+		assert.Equal(t, int64(1), sink.Dropped())
+		assert.NoError(t, sink.Close())
+		assert.Equal(t, int64(1), sink.Dropped())
+		assert.Len(t, client.Calls(), 2*log.CloudWatchMaxQueuedBatches+1)
+	})
+
+	t.Run("count events left unsent by close", func(t *testing.T) {
+		sink := log.NewCloudWatchSink(&fakeCloudWatch{errs: []error{errors.New("throttled")}}, "/klio", "run", time.Hour)
+		assert.NoError(t, sink.WriteRecord(start, log.Record{Level: log.InfoLevel, Message: "foo"}))
+		assert.EqualError(t, sink.Close(), "throttled")
+		assert.Equal(t, int64(1), sink.Dropped())
+	})
+
+	t.Run("reject records after close", func(t *testing.T) {
+		sink := log.NewCloudWatchSink(&fakeCloudWatch{}, "/klio", "run", time.Hour)
+		assert.NoError(t, sink.Close())
+		assert.Equal(t, io.ErrClosedPipe, sink.WriteRecord(start, log.Record{}))
+	})
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+// runCat renders captured output decorated with control sequences interpreted by Klio:
+//
+//	kliolog cat -level info -tag deploy build.log
+func runCat(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("kliolog cat", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "auto", "output format: auto, plain, color, json")
+	level := fs.String("level", "", "least severe level of written lines")
+	tags := fs.String("tag", "", "comma-separated tags, only lines with at least one of them are written")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: kliolog cat [flags] [file...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	var threshold log.Level
+	if *level != "" {
+		var ok bool
+		if threshold, ok = log.ParseLevel(*level); !ok {
+			fmt.Fprintf(stderr, "kliolog: unknown level %q\n", *level)
+			return 2
+		}
+	}
+	wanted := splitList(*tags)
+	accept := func(r log.Record) bool {
+		if !r.Level.Enabled(threshold) {
+			return false
+		}
+		if len(wanted) == 0 {
+			return true
+		}
+		for _, t := range r.Tags {
+			for _, w := range wanted {
+				if t == w {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	var write func(r log.Record) error
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(stdout)
+		enc.SetEscapeHTML(false)
+		write = func(r log.Record) error {
+			return enc.Encode(struct {
+				Level   log.Level `json:"level"`
+				Tags    []string  `json:"tags"`
+				Message string    `json:"message"`
+			}{r.Level, r.Tags, r.Message})
+		}
+	case "auto", "plain", "color":
+		l := log.New(stdout).WithOutputMode(log.OutputMode(*format))
+		if l.OutputMode() == log.KlioMode {
+			// Lines are rendered for humans even when run by Klio
+			l = l.WithOutputMode(log.PlainMode)
+		}
+		write = func(r log.Record) error {
+			l.WithLevel(r.Level).WithTags(r.Tags...).Print(r.Message)
+			return nil
+		}
+	default:
+		fmt.Fprintf(stderr, "kliolog: unknown format %q\n", *format)
+		return 2
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	code := 0
+	for _, name := range files {
+		if err := catFile(name, stdin, accept, write); err != nil {
+			fmt.Fprintf(stderr, "kliolog: %v\n", err)
+			code = 1
+		}
+	}
+	return code
+}
+
+// catFile decodes records from the file ("-" stands for stdin) and writes accepted ones.
+func catFile(name string, stdin io.Reader, accept func(log.Record) bool, write func(log.Record) error) error {
+	r := stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	d := log.NewDecoder(r)
+	for {
+		rec, err := d.Decode()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if accept(rec) {
+			if err := write(rec); err != nil {
+				return err
+			}
+		}
+	}
+}
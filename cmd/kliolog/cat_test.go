@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const captured = "\033_klio_log_level \"info\"\033\\\033_klio_tags [\"build\"]\033\\foo\033_klio_reset\033\\\n" +
+	"\033_klio_log_level \"debug\"\033\\\033_klio_tags [\"build\"]\033\\bar\033_klio_reset\033\\\n" +
+	"\033_klio_log_level \"warn\"\033\\\033_klio_tags [\"test\"]\033\\baz\033_klio_reset\033\\\n" +
+	"plain\n"
+
+func TestCat(t *testing.T) {
+	t.Run("render plain lines", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		code := run([]string{"cat", "-format", "plain"}, strings.NewReader(captured), &stdout, &stderr)
+
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "[INFO][BUILD] foo\n[DEBUG][BUILD] bar\n[WARN][TEST] baz\n[INFO] plain\n", stdout.String())
+	})
+
+	t.Run("render json lines", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		code := run([]string{"cat", "-format", "json", "-level", "info"}, strings.NewReader(captured), &stdout, &stderr)
+
+		assert.Equal(t, 0, code)
+		assert.Equal(t, `{"level":"info","tags":["build"],"message":"foo"}`+"\n"+
+			`{"level":"warn","tags":["test"],"message":"baz"}`+"\n"+
+			`{"level":"info","tags":[],"message":"plain"}`+"\n", stdout.String())
+	})
+
+	t.Run("filter by tags", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		code := run([]string{"cat", "-format", "plain", "-tag", "test,lint"}, strings.NewReader(captured), &stdout, &stderr)
+
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "[WARN][TEST] baz\n", stdout.String())
+	})
+
+	t.Run("read files", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		path := filepath.Join(t.TempDir(), "build.log")
+		assert.NoError(t, os.WriteFile(path, []byte(captured), 0o644))
+
+		code := run([]string{"cat", "-format", "plain", "-level", "warn", path, filepath.Join(path, "missing")}, nil, &stdout, &stderr)
+
+		assert.Equal(t, 1, code)
+		assert.Equal(t, "[WARN][TEST] baz\n", stdout.String())
+		assert.Contains(t, stderr.String(), "missing")
+	})
+}
@@ -0,0 +1,181 @@
+// Command kliolog lets tools not written in Go participate in Klio output handling. It runs a command and turns lines
+// it writes to the stdout and stderr into lines decorated with control sequences interpreted by Klio, classifying
+// them by their format (JSON, logfmt, klog) and by rules:
+//
+//	kliolog -tags build -rule 'warn=^WARNING' -- make all
+//
+// Exit code of the command is passed through. Captured output can be rendered as human readable or JSON lines using
+// the cat subcommand:
+//
+//	kliolog cat -level warn -tag build build.log
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"regexp"
+	"strings"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "cat" {
+		return runCat(args[1:], stdin, stdout, stderr)
+	}
+	return runCommand(args, stdin, stdout, stderr)
+}
+
+var (
+	parsers = map[string]log.Parser{
+		"json":   log.ParseJSON,
+		"logfmt": log.ParseLogfmt,
+		"klog":   log.ParseKlog,
+	}
+	strippers = map[string]*regexp.Regexp{
+		"timestamp": log.TimestampPrefix,
+		"pid":       log.PIDPrefix,
+		"bracket":   log.BracketPrefix,
+	}
+	groupings = map[string]log.Grouping{
+		"go":     log.GoPanicGrouping,
+		"java":   log.JavaStackTraceGrouping,
+		"python": log.PythonTracebackGrouping,
+	}
+)
+
+// ruleFlags collects -rule flags.
+type ruleFlags []log.Rule
+
+func (r *ruleFlags) String() string {
+	return ""
+}
+
+func (r *ruleFlags) Set(s string) error {
+	i := strings.IndexByte(s, '=')
+	if i < 0 {
+		return errors.New("rule should be in LEVEL=REGEXP format")
+	}
+	level, ok := log.ParseLevel(s[:i])
+	if !ok {
+		return fmt.Errorf("unknown level %q", s[:i])
+	}
+	pattern, err := regexp.Compile(s[i+1:])
+	if err != nil {
+		return err
+	}
+	*r = append(*r, log.Rule{Pattern: pattern, Level: level})
+	return nil
+}
+
+func runCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("kliolog", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	level := fs.String("level", "info", "level of lines which are not classified otherwise")
+	stderrLevel := fs.String("stderr-level", "", "level of stderr lines which are not classified otherwise (defaults to -level)")
+	tags := fs.String("tags", "", "comma-separated tags added to all lines")
+	parse := fs.String("parse", "json,logfmt,klog", "comma-separated structured formats: json, logfmt, klog")
+	strip := fs.String("strip", "", "comma-separated prefixes removed from lines: timestamp, pid, bracket")
+	group := fs.String("group", "go,java,python", "comma-separated multiline messages grouped into single lines: go, java, python")
+	var rules ruleFlags
+	fs.Var(&rules, "rule", "classification rule in LEVEL=REGEXP format, may be repeated")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: kliolog [flags] [--] command [args...]\n       kliolog cat [flags] [file...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	opts := []log.IngestOption{log.WithRules(rules...)}
+	for _, name := range splitList(*parse) {
+		p, ok := parsers[name]
+		if !ok {
+			fmt.Fprintf(stderr, "kliolog: unknown format %q\n", name)
+			return 2
+		}
+		opts = append(opts, log.WithParsers(p))
+	}
+	for _, name := range splitList(*strip) {
+		p, ok := strippers[name]
+		if !ok {
+			fmt.Fprintf(stderr, "kliolog: unknown prefix %q\n", name)
+			return 2
+		}
+		opts = append(opts, log.WithStrippers(p))
+	}
+	for _, name := range splitList(*group) {
+		g, ok := groupings[name]
+		if !ok {
+			fmt.Fprintf(stderr, "kliolog: unknown group %q\n", name)
+			return 2
+		}
+		opts = append(opts, log.WithGrouping(g))
+	}
+	lvl, ok := log.ParseLevel(*level)
+	if !ok {
+		fmt.Fprintf(stderr, "kliolog: unknown level %q\n", *level)
+		return 2
+	}
+	errLvl := lvl
+	if *stderrLevel != "" {
+		if errLvl, ok = log.ParseLevel(*stderrLevel); !ok {
+			fmt.Fprintf(stderr, "kliolog: unknown level %q\n", *stderrLevel)
+			return 2
+		}
+	}
+
+	l := log.New(stdout).WithLevel(lvl).WithTags(splitList(*tags)...)
+	cmd := exec.Command(fs.Arg(0), fs.Args()[1:]...)
+	cmd.Stdin = stdin
+	errIngester := log.NewIngester(l.WithLevel(errLvl), opts...)
+	cmd.Stderr = errIngester
+	cmd.Env = append(os.Environ(), log.ChildEnv(l)...)
+
+	// Let the command decide how to handle interrupts. The terminal sends SIGINT to the whole process group, so the
+	// command already receives it and kliolog only has to survive until the command exits (signals which don't fit in
+	// the channel are discarded).
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	err := log.Exec(l, cmd, opts...)
+	errIngester.Close()
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitErr):
+		if code := exitErr.ExitCode(); code > 0 {
+			return code
+		}
+		return 1
+	default:
+		l.WithLevel(log.ErrorLevel).Print(err)
+		return 127
+	}
+}
+
+func splitList(s string) []string {
+	r := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			r = append(r, v)
+		}
+	}
+	return r
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	t.Run("classify output", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		code := run([]string{"-tags", "build", "-stderr-level", "warn", "-rule", "error=^FAIL", "sh", "-c",
+			`echo foo; echo bar >&2; echo FAIL baz; echo '{"level":"debug","msg":"qux"}'`}, nil, &stdout, &stderr)
+
+		assert.Equal(t, 0, code)
+		assert.Empty(t, stderr.String())
+		out := stdout.String()
+		assert.Contains(t, out, "\033_klio_log_level \"info\"\033\\\033_klio_tags [\"build\"]\033\\foo\033_klio_reset")
+		assert.Contains(t, out, "\033_klio_log_level \"warn\"\033\\\033_klio_tags [\"build\"]\033\\bar\033_klio_reset")
+		assert.Contains(t, out, "\033_klio_log_level \"error\"\033\\\033_klio_tags [\"build\"]\033\\FAIL baz\033_klio_reset")
+		assert.Contains(t, out, "\033_klio_log_level \"debug\"\033\\\033_klio_tags [\"build\"]\033\\qux\033_klio_reset")
+	})
+
+	t.Run("pass exit code through", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		assert.Equal(t, 3, run([]string{"sh", "-c", "exit 3"}, nil, &stdout, &stderr))
+	})
+
+	t.Run("report missing command", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		assert.Equal(t, 127, run([]string{"klio-missing-command"}, nil, &stdout, &stderr))
+		assert.Contains(t, stdout.String(), "\033_klio_log_level \"error\"\033\\")
+	})
+
+	t.Run("reject invalid flags", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		assert.Equal(t, 2, run([]string{"-rule", "foo", "sh"}, nil, &stdout, &stderr))
+		assert.Equal(t, 2, run([]string{"-parse", "xml", "sh"}, nil, &stdout, &stderr))
+		assert.Equal(t, 2, run([]string{}, nil, &stdout, &stderr))
+		assert.Contains(t, stderr.String(), "Usage: kliolog")
+	})
+}
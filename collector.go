@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Collector records warnings and errors written during a run, so they can be recapped at its end, e.g.:
+//
+//	c := l.Collect()
+//	run(c.Logger())
+//	c.Summary() // [ERROR] 2 warnings, 1 error: ...
+type Collector struct {
+	mu      sync.Mutex
+	l       *Logger
+	records []Record
+}
+
+// Collect returns Collector recording lines at warn level or more severe written by its logger (and loggers derived
+// from it). Summary is written using l.
+func (l *Logger) Collect() *Collector {
+	return &Collector{l: l}
+}
+
+// Logger returns logger which lines are recorded by the collector.
+func (c *Collector) Logger() *Logger {
+	n := c.l.clone()
+	n.collector = c
+	return n
+}
+
+// Records returns recorded lines.
+func (c *Collector) Records() []Record {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r := make([]Record, len(c.records))
+	copy(r, c.records)
+	return r
+}
+
+// Summary writes recap of recorded lines as a single, multi-line record, e.g. "2 warnings, 1 error:" followed by
+// recorded lines. It is written at error level if any errors were recorded, warn level otherwise. If nothing was
+// recorded, Summary doesn't write anything.
+func (c *Collector) Summary() {
+	records := c.Records()
+	if len(records) == 0 {
+		return
+	}
+
+	warnings, errors := 0, 0
+	for _, r := range records {
+		if r.Level == WarnLevel {
+			warnings++
+		} else {
+			errors++
+		}
+	}
+
+	var counts []string
+	if warnings > 0 {
+		counts = append(counts, plural(warnings, "warning"))
+	}
+	if errors > 0 {
+		counts = append(counts, plural(errors, "error"))
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Join(counts, ", ") + ":")
+	for _, r := range records {
+		b.WriteString("\n" + humanLinePrefix(r.Level, r.Tags, false, nil) + r.Message)
+		for _, f := range r.Fields {
+			b.WriteString(" " + f.String())
+		}
+	}
+
+	level := WarnLevel
+	if errors > 0 {
+		level = ErrorLevel
+	}
+	c.l.WithLevel(level).Print(b.String())
+}
+
+// record stores the line if it is at warn level or more severe.
+func (c *Collector) record(r Record) {
+	if s, ok := levelSeverity[r.Level]; !ok || s > levelSeverity[WarnLevel] {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.records = append(c.records, r)
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
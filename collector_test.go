@@ -0,0 +1,56 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestCollector(t *testing.T) {
+	t.Run("summarize warnings and errors", func(t *testing.T) {
+		var b bytes.Buffer
+		c := log.New(&b).WithOutputMode(log.PlainMode).Collect()
+		l := c.Logger()
+
+		l.WithLevel(log.WarnLevel).Print("disk almost full")
+		l.Print("deploying")
+		l.WithTags("helm").WithLevel(log.ErrorLevel).WithField("release", "api").Print("upgrade failed")
+		l.WithLevel(log.WarnLevel).Print("retrying")
+		b.Reset()
+
+		c.Summary()
+
+		assert.Len(t, c.Records(), 3)
+		assert.Equal(t, ""+
+			"[ERROR] 2 warnings, 1 error:\n"+
+			"[WARN] disk almost full\n"+
+			"[ERROR][HELM] upgrade failed release=api\n"+
+			"[WARN] retrying\n", b.String())
+	})
+
+	t.Run("write warn summary", func(t *testing.T) {
+		var b bytes.Buffer
+		c := log.New(&b).WithOutputMode(log.PlainMode).Collect()
+
+		c.Logger().WithLevel(log.WarnLevel).Print("disk almost full")
+		b.Reset()
+		c.Summary()
+
+		assert.Equal(t, "[WARN] 1 warning:\n[WARN] disk almost full\n", b.String())
+	})
+
+	t.Run("skip empty summary", func(t *testing.T) {
+		var b bytes.Buffer
+		c := log.New(&b).WithOutputMode(log.PlainMode).Collect()
+
+		c.Logger().Print("deploying")
+		b.Reset()
+		c.Summary()
+
+		assert.Empty(t, c.Records())
+		assert.Empty(t, b.String())
+	})
+}
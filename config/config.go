@@ -0,0 +1,415 @@
+// Package config builds loggers from configuration files and environment variables, so all commands
+// within an organization can share the same logging setup.
+//
+// Configuration may be written in YAML or JSON:
+//
+//	level: verbose
+//	tags: [deploy]
+//	output: stderr
+//	sampling:
+//	  - {level: debug, tick: 1s, first: 10, thereafter: 100}
+//	redaction:
+//	  presets: [bearer, secrets]
+//	  rules:
+//	    - {name: session, pattern: "session=\\w+", replacement: "session=***"}
+//	sinks:
+//	  - {type: journald, identifier: deploy}
+//
+// Values from the environment (KLIO_LOG_LEVEL, KLIO_LOG_TAGS, KLIO_LOG_OUTPUT, KLIO_LOG_THRESHOLD,
+// KLIO_LOG_VMODULE) take precedence over the file.
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	logger "github.com/g2a-com/klio-logger-go"
+)
+
+const (
+	// EnvLevel is the name of the environment variable overriding Config.Level.
+	EnvLevel = logger.EnvLevel
+	// EnvTags is the name of the environment variable overriding Config.Tags. Tags are separated by commas.
+	EnvTags = logger.EnvTags
+	// EnvOutput is the name of the environment variable overriding Config.Output.
+	EnvOutput = "KLIO_LOG_OUTPUT"
+	// EnvThreshold is the name of the environment variable overriding Config.Threshold.
+	EnvThreshold = logger.EnvThreshold
+	// EnvVModule is the name of the environment variable overriding Config.VModule.
+	EnvVModule = logger.EnvVModule
+)
+
+// Config describes a logger.
+type Config struct {
+	// Level used by the logger, "info" if empty.
+	Level string `json:"level,omitempty" yaml:"level,omitempty"`
+	// Tags used by the logger.
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Name of the logger (see logger.Logger.WithName).
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Output is "stdout", "stderr" or path to a file (logs are appended to it). Defaults to "stdout".
+	Output string `json:"output,omitempty" yaml:"output,omitempty"`
+	// Rotation of the output file: "hourly", "daily" or empty to disable rotation (see logger.WithFileRotation).
+	Rotation string `json:"rotation,omitempty" yaml:"rotation,omitempty"`
+	// Compress enables compression of rotated files (see logger.WithFileCompression).
+	Compress bool `json:"compress,omitempty" yaml:"compress,omitempty"`
+	// MaxAge of rotated files, e.g. "720h" (see logger.WithFileRetention).
+	MaxAge string `json:"max_age,omitempty" yaml:"max_age,omitempty"`
+	// Lock enables advisory locking of the output file, so it can be shared by several processes (see
+	// logger.WithFileLocking).
+	Lock bool `json:"lock,omitempty" yaml:"lock,omitempty"`
+	// MaxFiles is the number of rotated files which are kept (see logger.WithFileRetention).
+	MaxFiles int `json:"max_files,omitempty" yaml:"max_files,omitempty"`
+	// Mode is the output mode: "auto" (default), "klio", "plain" or "color" (see logger.OutputMode).
+	Mode string `json:"mode,omitempty" yaml:"mode,omitempty"`
+	// Threshold is the least severe level written by the logger, empty to leave filtering to Klio.
+	Threshold string `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+	// VModule overrides threshold for selected loggers, e.g. "helm=debug,git=spam" (see logger.ParseVModule).
+	VModule string `json:"vmodule,omitempty" yaml:"vmodule,omitempty"`
+	// RunID enables "run:<id>" tag (see logger.RunID).
+	RunID bool `json:"run_id,omitempty" yaml:"run_id,omitempty"`
+	// ProcessInfo enables "host", "pid" and "user" fields (see logger.Logger.WithProcessInfo).
+	ProcessInfo bool `json:"process_info,omitempty" yaml:"process_info,omitempty"`
+	// BuildInfo enables "version" and "revision" fields (see logger.Logger.WithBuildInfo).
+	BuildInfo bool `json:"build_info,omitempty" yaml:"build_info,omitempty"`
+	// Sampling limits the number of repeated lines (see logger.Logger.WithSampling).
+	Sampling []Sampling `json:"sampling,omitempty" yaml:"sampling,omitempty"`
+	// Redaction masks sensitive data in messages and fields (see logger.Logger.WithRedaction).
+	Redaction Redaction `json:"redaction,omitempty" yaml:"redaction,omitempty"`
+	// Sinks receive records in addition to the output (see logger.Logger.WithSink).
+	Sinks []Sink `json:"sinks,omitempty" yaml:"sinks,omitempty"`
+}
+
+// Sampling describes a logger.SamplingRule.
+type Sampling struct {
+	// Level of lines the rule applies to.
+	Level string `json:"level" yaml:"level"`
+	// Tick is the interval after which counters are reset, e.g. "1s".
+	Tick string `json:"tick" yaml:"tick"`
+	// First is the number of lines with the same message written during each tick.
+	First int `json:"first,omitempty" yaml:"first,omitempty"`
+	// Thereafter makes the logger write every Thereafter-th line after the first ones, 0 drops them all.
+	Thereafter int `json:"thereafter,omitempty" yaml:"thereafter,omitempty"`
+}
+
+// Redaction describes rules masking sensitive data.
+type Redaction struct {
+	// Presets are names of predefined rules (see logger.RedactionPreset).
+	Presets []string `json:"presets,omitempty" yaml:"presets,omitempty"`
+	// Rules are custom rules.
+	Rules []RedactionRule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// RedactionRule describes a logger.RedactionRule.
+type RedactionRule struct {
+	// Name describes the rule.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Pattern is a regular expression matching text which should be masked.
+	Pattern string `json:"pattern" yaml:"pattern"`
+	// Replacement replaces matches, logger.RedactedValue if empty.
+	Replacement string `json:"replacement,omitempty" yaml:"replacement,omitempty"`
+	// Notify makes the logger write a debug line naming the rule whenever it masks something.
+	Notify bool `json:"notify,omitempty" yaml:"notify,omitempty"`
+}
+
+// Sink describes a sink receiving records.
+type Sink struct {
+	// Type is "journald" (see logger.NewJournaldSink) or "websocket" (see logger.DialWebSocketSink).
+	Type string `json:"type" yaml:"type"`
+	// Socket of journald, logger.JournaldSocket if empty.
+	Socket string `json:"socket,omitempty" yaml:"socket,omitempty"`
+	// Identifier used by journald, name of the executable if empty.
+	Identifier string `json:"identifier,omitempty" yaml:"identifier,omitempty"`
+	// URL of the WebSocket server, e.g. "ws://localhost:8080/logs".
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+}
+
+// Parse reads configuration in YAML or JSON format. Unknown keys are reported as errors.
+func Parse(data []byte) (*Config, error) {
+	c := &Config{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("invalid logger config: %w", err)
+	}
+	return c, nil
+}
+
+// ReadFile reads configuration from the file.
+func ReadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Load reads configuration from the file and applies overrides from the environment. If path is empty,
+// only the environment is used.
+func Load(path string) (*Config, error) {
+	c := &Config{}
+	if path != "" {
+		var err error
+		if c, err = ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	c.ApplyEnv(os.LookupEnv)
+	return c, nil
+}
+
+// ApplyEnv overrides configuration with values of environment variables returned by lookup.
+func (c *Config) ApplyEnv(lookup func(key string) (string, bool)) {
+	if v, ok := lookup(EnvLevel); ok {
+		c.Level = v
+	}
+	if v, ok := lookup(EnvTags); ok {
+		c.Tags = splitList(v)
+	}
+	if v, ok := lookup(EnvOutput); ok {
+		c.Output = v
+	}
+	if v, ok := lookup(EnvThreshold); ok {
+		c.Threshold = v
+	}
+	if v, ok := lookup(EnvVModule); ok {
+		c.VModule = v
+	}
+}
+
+// Build creates new logger described by the configuration. If output is a file, it is left open and can be
+// closed using l.Output().(io.Closer). Sinks are left open as well.
+func (c *Config) Build() (*logger.Logger, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+
+	sinks, err := c.openSinks()
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := c.openOutput()
+	if err != nil {
+		closeSinks(sinks)
+		return nil, err
+	}
+
+	return c.build(output).WithSink(sinks...), nil
+}
+
+func (c *Config) validate() error {
+	if _, ok := logger.ParseLevel(c.Level); c.Level != "" && !ok {
+		return fmt.Errorf("invalid logger config: unknown level %q", c.Level)
+	}
+	switch logger.OutputMode(c.Mode) {
+	case "", logger.AutoMode, logger.KlioMode, logger.PlainMode, logger.ColorMode:
+	default:
+		return fmt.Errorf("invalid logger config: unknown mode %q", c.Mode)
+	}
+	if _, ok := logger.ParseLevel(c.Threshold); c.Threshold != "" && !ok {
+		return fmt.Errorf("invalid logger config: unknown threshold %q", c.Threshold)
+	}
+	if _, err := logger.ParseVModule(c.VModule); err != nil {
+		return fmt.Errorf("invalid logger config: %w", err)
+	}
+	switch logger.Rotation(c.Rotation) {
+	case logger.NoRotation, logger.RotateHourly, logger.RotateDaily:
+	default:
+		return fmt.Errorf("invalid logger config: unknown rotation %q", c.Rotation)
+	}
+	if _, err := time.ParseDuration(c.MaxAge); c.MaxAge != "" && err != nil {
+		return fmt.Errorf("invalid logger config: invalid max_age %q", c.MaxAge)
+	}
+	for _, s := range c.Sampling {
+		if _, ok := logger.ParseLevel(s.Level); !ok {
+			return fmt.Errorf("invalid logger config: unknown sampling level %q", s.Level)
+		}
+		if d, err := time.ParseDuration(s.Tick); err != nil || d <= 0 {
+			return fmt.Errorf("invalid logger config: invalid sampling tick %q", s.Tick)
+		}
+		if s.First < 0 || s.Thereafter < 0 {
+			return fmt.Errorf("invalid logger config: negative sampling counts for level %q", s.Level)
+		}
+	}
+	for _, name := range c.Redaction.Presets {
+		if logger.RedactionPreset(name) == nil {
+			return fmt.Errorf("invalid logger config: unknown redaction preset %q", name)
+		}
+	}
+	for _, r := range c.Redaction.Rules {
+		if r.Pattern == "" {
+			return fmt.Errorf("invalid logger config: empty redaction pattern")
+		}
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			return fmt.Errorf("invalid logger config: invalid redaction pattern %q: %w", r.Pattern, err)
+		}
+	}
+	for _, s := range c.Sinks {
+		switch s.Type {
+		case "journald":
+		case "websocket":
+			if u, err := url.Parse(s.URL); err != nil || (u.Scheme != "ws" && u.Scheme != "wss") {
+				return fmt.Errorf("invalid logger config: invalid websocket url %q", s.URL)
+			}
+		default:
+			return fmt.Errorf("invalid logger config: unknown sink type %q", s.Type)
+		}
+	}
+	return nil
+}
+
+func (c *Config) build(output io.Writer) *logger.Logger {
+	return c.apply(logger.New(output), nil)
+}
+
+// apply returns copy of the logger with settings present in the configuration, others are left unchanged. If output is
+// not nil, it replaces output of the logger.
+func (c *Config) apply(l *logger.Logger, output io.Writer) *logger.Logger {
+	n := logger.New(nil)
+	n.Assign(l)
+	if output != nil {
+		n.SetOutput(output)
+	}
+	l = n
+	if c.Level != "" {
+		level, _ := logger.ParseLevel(c.Level)
+		l = l.WithLevel(level)
+	}
+	if c.Tags != nil {
+		l = l.WithTags(c.Tags...)
+	}
+	if c.Name != "" {
+		l = l.WithName(c.Name)
+	}
+	if c.VModule != "" {
+		vmodule, _ := logger.ParseVModule(c.VModule)
+		l = l.WithVModule(vmodule)
+	}
+	if c.Mode != "" {
+		l = l.WithOutputMode(logger.OutputMode(c.Mode))
+	}
+	if c.Threshold != "" {
+		threshold, _ := logger.ParseLevel(c.Threshold)
+		l = l.WithThreshold(threshold)
+	}
+	if c.RunID {
+		l = l.WithRunID()
+	}
+	if c.ProcessInfo {
+		l = l.WithProcessInfo()
+	}
+	if c.BuildInfo {
+		l = l.WithBuildInfo()
+	}
+	if len(c.Sampling) > 0 {
+		rules := make([]logger.SamplingRule, len(c.Sampling))
+		for i, s := range c.Sampling {
+			level, _ := logger.ParseLevel(s.Level)
+			tick, _ := time.ParseDuration(s.Tick)
+			rules[i] = logger.SamplingRule{Level: level, Tick: tick, First: s.First, Thereafter: s.Thereafter}
+		}
+		l = l.WithSampling(rules...)
+	}
+	var redactions []logger.RedactionRule
+	for _, name := range c.Redaction.Presets {
+		redactions = append(redactions, logger.RedactionPreset(name)...)
+	}
+	for _, r := range c.Redaction.Rules {
+		redactions = append(redactions, logger.RedactionRule{
+			Name:        r.Name,
+			Pattern:     regexp.MustCompile(r.Pattern),
+			Replacement: r.Replacement,
+			Notify:      r.Notify,
+		})
+	}
+	if len(redactions) > 0 {
+		l = l.WithRedaction(redactions...)
+	}
+	return l
+}
+
+func (c *Config) openSinks() ([]logger.Sink, error) {
+	var sinks []logger.Sink
+	for _, s := range c.Sinks {
+		var (
+			sink logger.Sink
+			err  error
+		)
+		switch s.Type {
+		case "journald":
+			socket := s.Socket
+			if socket == "" {
+				socket = logger.JournaldSocket
+			}
+			sink, err = logger.NewJournaldSink(socket, s.Identifier)
+		case "websocket":
+			sink, err = logger.DialWebSocketSink(s.URL, nil)
+		}
+		if err != nil {
+			closeSinks(sinks)
+			return nil, fmt.Errorf("cannot open %s sink: %w", s.Type, err)
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+// sinksKey identifies settings of the sinks, so they are reopened only when they change.
+func (c *Config) sinksKey() string {
+	return fmt.Sprint(c.Sinks)
+}
+
+func closeSinks(sinks []logger.Sink) {
+	for _, s := range sinks {
+		if c, ok := s.(io.Closer); ok {
+			c.Close()
+		}
+	}
+}
+
+func (c *Config) openOutput() (io.Writer, error) {
+	switch c.Output {
+	case "", "stdout":
+		return os.Stdout, nil
+	case "stderr":
+		return os.Stderr, nil
+	default:
+		maxAge, _ := time.ParseDuration(c.MaxAge)
+		opts := []logger.FileOption{
+			logger.WithFileRotation(logger.Rotation(c.Rotation)),
+			logger.WithFileRetention(maxAge, c.MaxFiles),
+		}
+		if c.Compress {
+			opts = append(opts, logger.WithFileCompression())
+		}
+		if c.Lock {
+			opts = append(opts, logger.WithFileLocking())
+		}
+		return logger.OpenFile(c.Output, opts...)
+	}
+}
+
+// outputKey identifies settings of the output, so it is reopened only when they change.
+func (c *Config) outputKey() string {
+	return fmt.Sprintf("%s|%s|%t|%t|%s|%d", c.Output, c.Rotation, c.Compress, c.Lock, c.MaxAge, c.MaxFiles)
+}
+
+func splitList(s string) []string {
+	r := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			r = append(r, v)
+		}
+	}
+	return r
+}
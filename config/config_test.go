@@ -0,0 +1,204 @@
+package config_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+	"github.com/g2a-com/klio-logger-go/config"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("parse YAML", func(t *testing.T) {
+		c, err := config.Parse([]byte("level: debug\ntags: [foo, bar]\noutput: stderr\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, &config.Config{Level: "debug", Tags: []string{"foo", "bar"}, Output: "stderr"}, c)
+	})
+
+	t.Run("parse JSON", func(t *testing.T) {
+		c, err := config.Parse([]byte(`{"level": "warn", "tags": ["foo"]}`))
+		assert.NoError(t, err)
+		assert.Equal(t, &config.Config{Level: "warn", Tags: []string{"foo"}}, c)
+	})
+
+	t.Run("parse empty document", func(t *testing.T) {
+		c, err := config.Parse([]byte(""))
+		assert.NoError(t, err)
+		assert.Equal(t, &config.Config{}, c)
+	})
+
+	t.Run("reject unknown keys", func(t *testing.T) {
+		_, err := config.Parse([]byte("levle: debug\n"))
+		assert.Error(t, err)
+	})
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("level: debug\ntags: [foo]\n"), 0o644))
+
+	t.Run("load file", func(t *testing.T) {
+		c, err := config.Load(path)
+		assert.NoError(t, err)
+		assert.Equal(t, &config.Config{Level: "debug", Tags: []string{"foo"}}, c)
+	})
+
+	t.Run("override file with environment", func(t *testing.T) {
+		os.Setenv(config.EnvLevel, "spam")
+		os.Setenv(config.EnvTags, "a, b,,c")
+		defer os.Unsetenv(config.EnvLevel)
+		defer os.Unsetenv(config.EnvTags)
+
+		c, err := config.Load(path)
+		assert.NoError(t, err)
+		assert.Equal(t, &config.Config{Level: "spam", Tags: []string{"a", "b", "c"}}, c)
+	})
+
+	t.Run("return error for missing file", func(t *testing.T) {
+		_, err := config.Load(filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.Error(t, err)
+	})
+}
+
+func TestBuild(t *testing.T) {
+	t.Run("build default logger", func(t *testing.T) {
+		l, err := (&config.Config{}).Build()
+		assert.NoError(t, err)
+		assert.Equal(t, log.DefaultLevel, l.Level())
+		assert.Equal(t, []string{}, l.Tags())
+		assert.Equal(t, os.Stdout, l.Output())
+	})
+
+	t.Run("build logger writing to a file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "out.log")
+		l, err := (&config.Config{Level: "warn", Tags: []string{"foo"}, Output: path}).Build()
+		assert.NoError(t, err)
+		l.Print("bar")
+		assert.NoError(t, l.Output().(io.Closer).Close())
+
+		data, _ := os.ReadFile(path)
+		assert.Equal(t, "\033_klio_log_level \"warn\"\033\\\033_klio_tags [\"foo\"]\033\\bar\033_klio_reset\033\\\n", string(data))
+	})
+
+	t.Run("build logger with run ID", func(t *testing.T) {
+		l, err := (&config.Config{Tags: []string{"foo"}, RunID: true}).Build()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"foo", "run:" + log.RunID()}, l.Tags())
+	})
+
+	t.Run("build logger with process info", func(t *testing.T) {
+		l, err := (&config.Config{ProcessInfo: true}).Build()
+		assert.NoError(t, err)
+		assert.Len(t, l.Fields(), 3)
+	})
+
+	t.Run("build logger with threshold and vmodule", func(t *testing.T) {
+		l, err := (&config.Config{Threshold: "warn", VModule: "helm=debug"}).Build()
+		assert.NoError(t, err)
+		assert.Equal(t, log.WarnLevel, l.Threshold())
+		assert.Equal(t, log.VModule{{Pattern: "helm", Threshold: log.DebugLevel}}, l.VModule())
+	})
+
+	t.Run("return error for unknown level", func(t *testing.T) {
+		_, err := (&config.Config{Level: "loud"}).Build()
+		assert.Error(t, err)
+		_, err = (&config.Config{Threshold: "loud"}).Build()
+		assert.Error(t, err)
+	})
+
+	t.Run("build logger with output mode", func(t *testing.T) {
+		l, err := (&config.Config{Mode: "plain"}).Build()
+		assert.NoError(t, err)
+		assert.Equal(t, log.PlainMode, l.OutputMode())
+
+		_, err = (&config.Config{Mode: "fancy"}).Build()
+		assert.Error(t, err)
+	})
+
+	t.Run("return error for invalid vmodule", func(t *testing.T) {
+		_, err := (&config.Config{VModule: "helm"}).Build()
+		assert.Error(t, err)
+	})
+
+	t.Run("build logger writing to a rotated file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "out.log")
+		l, err := (&config.Config{Output: path, Rotation: "daily", Compress: true, Lock: true, MaxAge: "720h", MaxFiles: 7}).Build()
+		assert.NoError(t, err)
+		assert.IsType(t, &log.FileWriter{}, l.Output())
+		assert.NoError(t, l.Output().(io.Closer).Close())
+
+		_, err = (&config.Config{Output: path, Rotation: "weekly"}).Build()
+		assert.EqualError(t, err, `invalid logger config: unknown rotation "weekly"`)
+		_, err = (&config.Config{Output: path, MaxAge: "month"}).Build()
+		assert.EqualError(t, err, `invalid logger config: invalid max_age "month"`)
+	})
+}
+
+func TestBuildSections(t *testing.T) {
+	t.Run("parse sampling, redaction and sinks", func(t *testing.T) {
+		c, err := config.Parse([]byte(`
+sampling:
+  - {level: debug, tick: 1s, first: 10, thereafter: 100}
+redaction:
+  presets: [bearer]
+  rules:
+    - {name: session, pattern: "session=\\w+", replacement: "session=***", notify: true}
+sinks:
+  - {type: websocket, url: "ws://localhost:8080/logs"}
+`))
+		assert.NoError(t, err)
+		assert.Equal(t, &config.Config{
+			Sampling: []config.Sampling{{Level: "debug", Tick: "1s", First: 10, Thereafter: 100}},
+			Redaction: config.Redaction{
+				Presets: []string{"bearer"},
+				Rules:   []config.RedactionRule{{Name: "session", Pattern: `session=\w+`, Replacement: "session=***", Notify: true}},
+			},
+			Sinks: []config.Sink{{Type: "websocket", URL: "ws://localhost:8080/logs"}},
+		}, c)
+	})
+
+	t.Run("build logger with sampling and redaction", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "out.log")
+		l, err := (&config.Config{
+			Output:   path,
+			Mode:     "plain",
+			Sampling: []config.Sampling{{Level: "info", Tick: "1h", First: 1}},
+			Redaction: config.Redaction{
+				Presets: []string{"bearer"},
+				Rules:   []config.RedactionRule{{Pattern: `session=\w+`, Replacement: "session=***"}},
+			},
+		}).Build()
+		assert.NoError(t, err)
+		l.Print("Bearer abc session=xyz")
+		l.Print("Bearer abc session=xyz")
+		assert.NoError(t, l.Output().(io.Closer).Close())
+
+		data, _ := os.ReadFile(path)
+		assert.Equal(t, "[INFO] Bearer "+log.RedactedValue+" session=***\n", string(data))
+	})
+
+	t.Run("return error for invalid sections", func(t *testing.T) {
+		for _, c := range []config.Config{
+			{Sampling: []config.Sampling{{Level: "loud", Tick: "1s"}}},
+			{Sampling: []config.Sampling{{Level: "info", Tick: "soon"}}},
+			{Sampling: []config.Sampling{{Level: "info", Tick: "1s", First: -1}}},
+			{Redaction: config.Redaction{Presets: []string{"everything"}}},
+			{Redaction: config.Redaction{Rules: []config.RedactionRule{{Pattern: "("}}}},
+			{Redaction: config.Redaction{Rules: []config.RedactionRule{{}}}},
+			{Sinks: []config.Sink{{Type: "syslog"}}},
+			{Sinks: []config.Sink{{Type: "websocket", URL: "http://localhost"}}},
+		} {
+			_, err := c.Build()
+			assert.Error(t, err, "%+v", c)
+		}
+	})
+
+	t.Run("return error if sink cannot be opened", func(t *testing.T) {
+		_, err := (&config.Config{Sinks: []config.Sink{{Type: "journald", Socket: filepath.Join(t.TempDir(), "missing")}}}).Build()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	logger "github.com/g2a-com/klio-logger-go"
+)
+
+// Reloader keeps registered loggers in sync with a configuration file. New configuration is loaded on demand
+// (Reload), on signal (WatchSignal) or when the file changes (WatchFile). If the file is invalid, loggers are left
+// untouched.
+type Reloader struct {
+	path     string
+	mu       sync.Mutex
+	loggers  []registered
+	current  *logger.Logger
+	config   *Config
+	output   string
+	sinks    []logger.Sink
+	sinksKey string
+	modTime  time.Time
+}
+
+// registered is a logger kept in sync with the configuration, base holds its settings from before registration.
+type registered struct {
+	logger *logger.Logger
+	base   *logger.Logger
+}
+
+// NewReloader loads configuration from the file (see Load) and returns Reloader for it.
+func NewReloader(path string) (*Reloader, error) {
+	r := &Reloader{path: path}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Logger returns new logger using the most recently loaded configuration.
+func (r *Reloader) Logger() *logger.Logger {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	l := logger.New(nil)
+	l.Assign(r.current)
+	return l
+}
+
+// Register applies current configuration to the logger and keeps it updated on every reload. Only settings present
+// in the configuration are changed, others (e.g. filters, sinks or output if the configuration doesn't specify one)
+// are kept.
+func (r *Reloader) Register(l *logger.Logger) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	base := logger.New(nil)
+	base.Assign(l)
+	reg := registered{logger: l, base: base}
+	reg.logger.Assign(r.config.apply(base, r.configuredOutput()).WithSink(r.sinks...))
+	r.loggers = append(r.loggers, reg)
+}
+
+// Reload loads configuration again and applies it to all registered loggers.
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if info, err := os.Stat(r.path); err == nil {
+		r.modTime = info.ModTime()
+	}
+
+	c, err := Load(r.path)
+	if err != nil {
+		return err
+	}
+
+	if err := c.validate(); err != nil {
+		return err
+	}
+
+	var output io.Writer
+	if r.current != nil && c.outputKey() == r.output {
+		// Keep already opened output instead of opening the same file again.
+		output = r.current.Output()
+	} else if output, err = c.openOutput(); err != nil {
+		return err
+	}
+
+	sinks := r.sinks
+	if r.current == nil || c.sinksKey() != r.sinksKey {
+		if sinks, err = c.openSinks(); err != nil {
+			if r.current == nil || output != r.current.Output() {
+				closeOutput(output)
+			}
+			return err
+		}
+	}
+
+	l := c.build(output).WithSink(sinks...)
+
+	var configured io.Writer
+	if c.Output != "" {
+		configured = output
+	}
+	for _, reg := range r.loggers {
+		reg.logger.Assign(c.apply(reg.base, configured).WithSink(sinks...))
+	}
+
+	if r.current != nil && c.outputKey() != r.output {
+		closeOutput(r.current.Output())
+	}
+	if r.current != nil && c.sinksKey() != r.sinksKey {
+		closeSinks(r.sinks)
+	}
+	r.current = l
+	r.config = c
+	r.output = c.outputKey()
+	r.sinks = sinks
+	r.sinksKey = c.sinksKey()
+
+	return nil
+}
+
+// WatchSignal reloads configuration whenever the process receives one of specified signals (SIGHUP if none are
+// specified, nothing on platforms without it), until the context is cancelled. Errors are passed to onError, which
+// may be nil.
+func (r *Reloader) WatchSignal(ctx context.Context, onError func(error), signals ...os.Signal) {
+	if len(signals) == 0 {
+		signals = reloadSignals
+	}
+	if len(signals) == 0 {
+		return
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+
+	go func() {
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ch:
+				r.reportError(r.Reload(), onError)
+			}
+		}
+	}()
+}
+
+// WatchFile checks the file every interval and reloads configuration when its modification time changes, until the
+// context is cancelled. Errors are passed to onError, which may be nil.
+func (r *Reloader) WatchFile(ctx context.Context, interval time.Duration, onError func(error)) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				info, err := os.Stat(r.path)
+				if err != nil {
+					r.reportError(err, onError)
+					continue
+				}
+				r.mu.Lock()
+				changed := !info.ModTime().Equal(r.modTime)
+				r.mu.Unlock()
+				if changed {
+					r.reportError(r.Reload(), onError)
+				}
+			}
+		}
+	}()
+}
+
+// configuredOutput returns output opened for the current configuration, or nil if it doesn't specify one.
+func (r *Reloader) configuredOutput() io.Writer {
+	if r.config.Output == "" {
+		return nil
+	}
+	return r.current.Output()
+}
+
+func (r *Reloader) reportError(err error, onError func(error)) {
+	if err != nil && onError != nil {
+		onError(err)
+	}
+}
+
+func closeOutput(w io.Writer) {
+	if w == os.Stdout || w == os.Stderr {
+		return
+	}
+	if c, ok := w.(io.Closer); ok {
+		c.Close()
+	}
+}
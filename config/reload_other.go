@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+
+package config
+
+import "os"
+
+// reloadSignals are signals used by WatchSignal when none are specified. There is no conventional reload signal on
+// this platform.
+var reloadSignals []os.Signal
@@ -0,0 +1,91 @@
+package config_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+	"github.com/g2a-com/klio-logger-go/config"
+)
+
+func TestReloader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("level: debug\ntags: [foo]\n"), 0o644))
+
+	r, err := config.NewReloader(path)
+	assert.NoError(t, err)
+
+	var b bytes.Buffer
+	l := log.New(&b)
+	r.Register(l)
+
+	t.Run("apply configuration on register", func(t *testing.T) {
+		assert.Equal(t, log.DebugLevel, l.Level())
+		assert.Equal(t, []string{"foo"}, l.Tags())
+		assert.Equal(t, log.DebugLevel, r.Logger().Level())
+	})
+
+	t.Run("apply new configuration on reload", func(t *testing.T) {
+		assert.NoError(t, os.WriteFile(path, []byte("level: spam\ntags: [bar]\n"), 0o644))
+		assert.NoError(t, r.Reload())
+		assert.Equal(t, log.SpamLevel, l.Level())
+		assert.Equal(t, []string{"bar"}, l.Tags())
+	})
+
+	t.Run("keep configuration if file is invalid", func(t *testing.T) {
+		assert.NoError(t, os.WriteFile(path, []byte("level: loud\n"), 0o644))
+		assert.Error(t, r.Reload())
+		assert.Equal(t, log.SpamLevel, l.Level())
+		assert.Equal(t, []string{"bar"}, l.Tags())
+	})
+}
+
+func TestReloaderKeepsUnsetSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("tags: [foo]\n"), 0o644))
+
+	r, err := config.NewReloader(path)
+	assert.NoError(t, err)
+
+	var b bytes.Buffer
+	l := log.New(&b).WithOutputMode(log.PlainMode).WithLevel(log.WarnLevel).WithFilter(func(r log.Record) bool {
+		return r.Message != "skip"
+	})
+	r.Register(l)
+	l.Print("skip")
+	l.Print("keep")
+	assert.Equal(t, "[WARN][FOO] keep\n", b.String())
+
+	b.Reset()
+	assert.NoError(t, os.WriteFile(path, []byte("tags: [bar]\n"), 0o644))
+	assert.NoError(t, r.Reload())
+	l.Print("skip")
+	l.Print("keep")
+	assert.Equal(t, "[WARN][BAR] keep\n", b.String())
+}
+
+func TestReloaderWatchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("level: debug\n"), 0o644))
+
+	r, err := config.NewReloader(path)
+	assert.NoError(t, err)
+
+	l := log.New(nil)
+	r.Register(l)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r.WatchFile(ctx, 10*time.Millisecond, nil)
+
+	assert.NoError(t, os.WriteFile(path, []byte("level: warn\n"), 0o644))
+	assert.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Hour)))
+
+	assert.Eventually(t, func() bool { return l.Level() == log.WarnLevel }, time.Second, 10*time.Millisecond)
+}
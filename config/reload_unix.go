@@ -0,0 +1,11 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package config
+
+import (
+	"os"
+	"syscall"
+)
+
+// reloadSignals are signals used by WatchSignal when none are specified.
+var reloadSignals = []os.Signal{syscall.SIGHUP}
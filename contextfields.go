@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"context"
+	"sync"
+)
+
+// ContextField maps a value carried by a context to a field added by FromContext, see RegisterContextFields.
+type ContextField struct {
+	// Key of the field.
+	Key string
+	// Value returns value of the field, ok is false if the context doesn't carry it.
+	Value func(ctx context.Context) (value interface{}, ok bool)
+}
+
+var (
+	contextFieldsMu sync.RWMutex
+	contextFields   []ContextField
+	baggageFunc     func(ctx context.Context) map[string]string
+)
+
+// RegisterContextFields registers fields added to loggers returned by FromContext, so cross-cutting attributes (e.g.
+// tenant or environment) appear in every line without manual plumbing:
+//
+//	log.RegisterContextFields(log.ContextValue("tenant", tenantKey{}), log.BaggageField("deployment.environment", "env"))
+func RegisterContextFields(fields ...ContextField) {
+	contextFieldsMu.Lock()
+	defer contextFieldsMu.Unlock()
+	contextFields = append(contextFields, fields...)
+}
+
+// ContextValue returns ContextField holding value stored in a context under the key (see context.WithValue).
+func ContextValue(field string, key interface{}) ContextField {
+	return ContextField{Key: field, Value: func(ctx context.Context) (interface{}, bool) {
+		v := ctx.Value(key)
+		return v, v != nil
+	}}
+}
+
+// SetBaggageExtractor sets function returning members of the baggage carried by a context, used by BaggageField.
+// Like SetSpanExtractor, it keeps tracing libraries out of dependencies, e.g. for OpenTelemetry:
+//
+//	log.SetBaggageExtractor(func(ctx context.Context) map[string]string {
+//		m := map[string]string{}
+//		for _, member := range baggage.FromContext(ctx).Members() {
+//			m[member.Key()] = member.Value()
+//		}
+//		return m
+//	})
+func SetBaggageExtractor(extractor func(ctx context.Context) map[string]string) {
+	contextFieldsMu.Lock()
+	defer contextFieldsMu.Unlock()
+	baggageFunc = extractor
+}
+
+// BaggageField returns ContextField holding value of the baggage member, see SetBaggageExtractor.
+func BaggageField(member, field string) ContextField {
+	return ContextField{Key: field, Value: func(ctx context.Context) (interface{}, bool) {
+		contextFieldsMu.RLock()
+		extractor := baggageFunc
+		contextFieldsMu.RUnlock()
+		if extractor == nil {
+			return nil, false
+		}
+		v, ok := extractor(ctx)[member]
+		return v, ok
+	}}
+}
+
+// fieldsFromContext returns values of registered context fields carried by the context.
+func fieldsFromContext(ctx context.Context) []Field {
+	contextFieldsMu.RLock()
+	registered := contextFields
+	contextFieldsMu.RUnlock()
+
+	var fields []Field
+	for _, cf := range registered {
+		if v, ok := cf.Value(ctx); ok {
+			fields = append(fields, Field{Key: cf.Key, Value: v})
+		}
+	}
+	return fields
+}
@@ -0,0 +1,31 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+type tenantKey struct{}
+
+func TestRegisterContextFields(t *testing.T) {
+	log.RegisterContextFields(log.ContextValue("tenant", tenantKey{}), log.BaggageField("deployment.environment", "env"))
+	log.SetBaggageExtractor(func(ctx context.Context) map[string]string {
+		return map[string]string{"deployment.environment": "staging"}
+	})
+	defer log.SetBaggageExtractor(nil)
+
+	var b bytes.Buffer
+	ctx := log.NewContext(context.Background(), log.New(&b).WithOutputMode(log.PlainMode))
+
+	log.FromContext(ctx).Print("foo")
+	log.FromContext(context.WithValue(ctx, tenantKey{}, "acme")).Print("bar")
+	log.SetBaggageExtractor(nil)
+	log.FromContext(ctx).Print("baz")
+
+	assert.Equal(t, "[INFO] foo env=staging\n[INFO] bar tenant=acme env=staging\n[INFO] baz\n", b.String())
+}
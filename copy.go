@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"context"
+	"io"
+)
+
+// Copy streams the reader into the logger line by line (see Ingester), until EOF or cancellation of the context.
+// Memory usage is bounded: the reader is not read faster than lines are written, and lines longer than MaxLineLength
+// are split. Incomplete last line is written too. It returns nil on EOF, ctx.Err() if the context was cancelled and
+// read error otherwise. Since reads can't be interrupted, a read pending during cancellation finishes in the
+// background, but its result is discarded.
+func Copy(ctx context.Context, l *Logger, r io.Reader, opts ...IngestOption) error {
+	ing := NewIngester(l, opts...)
+	defer ing.Close()
+
+	chunks := make(chan []byte)
+	errs := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := r.Read(buf)
+			if n > 0 {
+				select {
+				case chunks <- append([]byte(nil), buf[:n]...):
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case chunk := <-chunks:
+			ing.Write(chunk)
+		case err := <-errs:
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestCopy(t *testing.T) {
+	t.Run("copy lines", func(t *testing.T) {
+		var b bytes.Buffer
+		err := log.Copy(context.Background(), log.New(&b), iotest.OneByteReader(strings.NewReader("foo\nbar")))
+
+		assert.NoError(t, err)
+		assert.Equal(
+			t,
+			"\033_klio_log_level \"info\"\033\\\033_klio_tags []\033\\foo\033_klio_reset\033\\\n"+
+				"\033_klio_log_level \"info\"\033\\\033_klio_tags []\033\\bar\033_klio_reset\033\\\n",
+			b.String(),
+		)
+	})
+
+	t.Run("split long lines", func(t *testing.T) {
+		var b bytes.Buffer
+		err := log.Copy(context.Background(), log.New(&b), strings.NewReader(strings.Repeat("x", log.MaxLineLength+1)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, strings.Count(b.String(), "\n"))
+	})
+
+	t.Run("return read error", func(t *testing.T) {
+		var b bytes.Buffer
+		err := log.Copy(context.Background(), log.New(&b), iotest.ErrReader(errors.New("boom")))
+
+		assert.EqualError(t, err, "boom")
+	})
+
+	t.Run("stop on cancellation", func(t *testing.T) {
+		var b bytes.Buffer
+		r, w := io.Pipe()
+		defer w.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := log.Copy(ctx, log.New(&b), r)
+
+		assert.Equal(t, context.Canceled, err)
+	})
+}
@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// CorrelationField is the key of the field holding correlation IDs, see Logger.WithCorrelationID.
+const CorrelationField = "correlation_id"
+
+// EnvCorrelationID is the name of the environment variable used to pass correlation ID to subprocesses. The standard
+// and error loggers use correlation ID from this variable.
+const EnvCorrelationID = "KLIO_CORRELATION_ID"
+
+type contextKey int
+
+const (
+	loggerKey contextKey = iota
+	correlationKey
+)
+
+// NewCorrelationID returns new random correlation ID.
+func NewCorrelationID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return runID
+	}
+	return hex.EncodeToString(b)
+}
+
+// WithCorrelationID creates new logger instance adding correlation ID to each line (in the "correlation_id" field)
+// and to the environment returned by ChildEnv, so multi-command pipelines can be traced end-to-end. Empty ID removes
+// correlation ID.
+func (l *Logger) WithCorrelationID(id string) *Logger {
+	n := l.clone()
+	fields := make([]Field, 0, len(n.fields)+1)
+	for _, f := range n.fields {
+		if f.Key != CorrelationField {
+			fields = append(fields, f)
+		}
+	}
+	if id != "" {
+		fields = append(fields, Field{Key: CorrelationField, Value: id})
+	}
+	n.fields, n.correlationID = fields, id
+	n.updateLineSuffix()
+	return n
+}
+
+// CorrelationID returns correlation ID of a logger, empty if it has none.
+func (l *Logger) CorrelationID() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.correlationID
+}
+
+// NewContext returns a copy of the context carrying the logger, see FromContext.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, l)
+}
+
+// ContextWithCorrelationID returns a copy of the context carrying correlation ID, which is added to loggers returned
+// by FromContext.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey, id)
+}
+
+// CorrelationIDFromContext returns correlation ID carried by the context, empty if there is none.
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey).(string)
+	return id
+}
+
+// FromContext returns logger carried by the context (the standard logger if there is none), with correlation ID
+// carried by the context, IDs of the active span (see SetSpanExtractor) and registered context fields (see
+// RegisterContextFields), if any:
+//
+//	ctx = log.ContextWithCorrelationID(log.NewContext(ctx, l), log.NewCorrelationID())
+//	log.FromContext(ctx).Print("deploying") // [INFO] deploying correlation_id=...
+func FromContext(ctx context.Context) *Logger {
+	l, ok := ctx.Value(loggerKey).(*Logger)
+	if !ok || l == nil {
+		l = standardLogger
+	}
+	if id := CorrelationIDFromContext(ctx); id != "" && id != l.CorrelationID() {
+		l = l.WithCorrelationID(id)
+	}
+	if fields := append(spanFields(ctx), fieldsFromContext(ctx)...); len(fields) > 0 {
+		l = l.WithFields(fields...)
+	}
+	return l
+}
@@ -0,0 +1,69 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestWithCorrelationID(t *testing.T) {
+	t.Run("add correlation ID to lines and child environment", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.PlainMode).WithField("a", 1).WithCorrelationID("foo")
+
+		l.Print("bar")
+		l.WithCorrelationID("baz").Print("qux")
+		l.WithCorrelationID("").Print("quux")
+
+		assert.Equal(t, "[INFO] bar a=1 correlation_id=foo\n[INFO] qux a=1 correlation_id=baz\n[INFO] quux a=1\n", b.String())
+		assert.Equal(t, "foo", l.CorrelationID())
+		assert.Contains(t, log.ChildEnv(l), "KLIO_CORRELATION_ID=foo")
+	})
+
+	t.Run("generate random IDs", func(t *testing.T) {
+		id := log.NewCorrelationID()
+
+		assert.Len(t, id, 32)
+		assert.NotEqual(t, id, log.NewCorrelationID())
+	})
+
+	t.Run("inherit correlation ID from environment", func(t *testing.T) {
+		if os.Getenv("KLIO_TEST_CORRELATION") == "1" {
+			log.Info("foo")
+			return
+		}
+
+		cmd := exec.Command(os.Args[0], "-test.run=^TestWithCorrelationID$/^inherit")
+		cmd.Env = append(os.Environ(), "KLIO_TEST_CORRELATION=1", log.EnvMode+"=plain", log.EnvCorrelationID+"=abc")
+		out, err := cmd.Output()
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(out), "[INFO] foo correlation_id=abc\n")
+	})
+}
+
+func TestFromContext(t *testing.T) {
+	t.Run("return logger carried by context", func(t *testing.T) {
+		l := log.New(&bytes.Buffer{})
+
+		assert.Same(t, l, log.FromContext(log.NewContext(context.Background(), l)))
+		assert.Same(t, log.StandardLogger(), log.FromContext(context.Background()))
+	})
+
+	t.Run("add correlation ID carried by context", func(t *testing.T) {
+		var b bytes.Buffer
+		ctx := log.NewContext(context.Background(), log.New(&b).WithOutputMode(log.PlainMode))
+		ctx = log.ContextWithCorrelationID(ctx, "foo")
+
+		log.FromContext(ctx).Print("bar")
+
+		assert.Equal(t, "foo", log.CorrelationIDFromContext(ctx))
+		assert.Equal(t, "[INFO] bar correlation_id=foo\n", b.String())
+	})
+}
@@ -0,0 +1,194 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrWriteTimeout is returned by DeadlineWriter when data was dropped, because the output didn't accept it in time.
+var ErrWriteTimeout = errors.New("write timeout")
+
+// TimeoutPolicy decides what DeadlineWriter does with data which couldn't be written in time.
+type TimeoutPolicy int
+
+const (
+	// DropOnTimeout drops data which couldn't be written in time, as well as data written while the output is stuck.
+	DropOnTimeout TimeoutPolicy = iota
+	// BufferOnTimeout keeps data which couldn't be written in time in memory (up to MaxBufferedBytes) and writes it
+	// once the output accepts writes again.
+	BufferOnTimeout
+)
+
+// MaxBufferedBytes limits amount of data kept in memory by DeadlineWriter using BufferOnTimeout policy. Data exceeding
+// the limit is dropped.
+const MaxBufferedBytes = 1 << 20
+
+// DeadlineWriter protects against slow or blocked outputs (e.g. network connections or full pipes), so a stuck
+// consumer can't hang a command inside Print. Writes are performed in the background and each call to Write waits for
+// them at most timeout.
+type DeadlineWriter struct {
+	mu         sync.Mutex
+	w          io.Writer
+	timeout    time.Duration
+	policy     TimeoutPolicy
+	queue      []deadlineChunk
+	queued     int
+	next       uint64
+	written    uint64
+	writing    uint64
+	stuck      bool
+	dropped    int64
+	progress   chan struct{}
+	wake       chan struct{}
+	closed     bool
+	unregister func()
+}
+
+type deadlineChunk struct {
+	seq  uint64
+	data []byte
+}
+
+// NewDeadlineWriter creates new DeadlineWriter writing to w. It should be closed when no longer needed.
+func NewDeadlineWriter(w io.Writer, timeout time.Duration, policy TimeoutPolicy) *DeadlineWriter {
+	d := &DeadlineWriter{
+		w:        w,
+		timeout:  timeout,
+		policy:   policy,
+		progress: make(chan struct{}),
+		wake:     make(chan struct{}, 1),
+	}
+	d.unregister = registerFlusher(func() { d.Flush() })
+	go d.run()
+	return d
+}
+
+// Write queues data and waits until it is written or timeout passes. If the output is stuck, it doesn't wait at all.
+// Dropped data is reported using ErrWriteTimeout.
+func (d *DeadlineWriter) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return 0, io.ErrClosedPipe
+	}
+	if d.stuck && (d.policy == DropOnTimeout || d.queued+len(p) > MaxBufferedBytes) {
+		d.dropped++
+		return 0, ErrWriteTimeout
+	}
+
+	d.next++
+	seq := d.next
+	d.queue = append(d.queue, deadlineChunk{seq: seq, data: append([]byte(nil), p...)})
+	d.queued += len(p)
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	if d.stuck {
+		return len(p), nil
+	}
+
+	if !d.wait(seq, d.timeout) {
+		d.stuck = true
+		if d.policy == DropOnTimeout && d.remove(seq) {
+			d.dropped++
+			return 0, ErrWriteTimeout
+		}
+	}
+	return len(p), nil
+}
+
+// Flush waits at most timeout until all queued data is written.
+func (d *DeadlineWriter) Flush() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	last := d.writing
+	if len(d.queue) > 0 {
+		last = d.queue[len(d.queue)-1].seq
+	}
+	if !d.wait(last, d.timeout) {
+		return ErrWriteTimeout
+	}
+	return nil
+}
+
+// Dropped returns number of writes dropped so far.
+func (d *DeadlineWriter) Dropped() int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.dropped
+}
+
+// Close flushes the writer (see Flush) and stops it. Data which wasn't written in time is dropped. The underlying writer
+// is not closed.
+func (d *DeadlineWriter) Close() error {
+	err := d.Flush()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.closed {
+		d.closed = true
+		d.queue, d.queued = nil, 0
+		d.unregister()
+		close(d.wake)
+	}
+	return err
+}
+
+// wait waits until chunk seq is written or timeout passes. Caller must hold d.mu.
+func (d *DeadlineWriter) wait(seq uint64, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for d.written < seq {
+		progress := d.progress
+		d.mu.Unlock()
+		select {
+		case <-progress:
+			d.mu.Lock()
+		case <-timer.C:
+			d.mu.Lock()
+			return d.written >= seq
+		}
+	}
+	return true
+}
+
+// remove removes chunk seq from the queue, if it isn't being written yet. Caller must hold d.mu.
+func (d *DeadlineWriter) remove(seq uint64) bool {
+	for i, c := range d.queue {
+		if c.seq == seq {
+			d.queue = append(d.queue[:i], d.queue[i+1:]...)
+			d.queued -= len(c.data)
+			return true
+		}
+	}
+	return false
+}
+
+func (d *DeadlineWriter) run() {
+	for range d.wake {
+		for {
+			d.mu.Lock()
+			if len(d.queue) == 0 {
+				d.mu.Unlock()
+				break
+			}
+			c := d.queue[0]
+			d.queue = d.queue[1:]
+			d.writing = c.seq
+			d.mu.Unlock()
+
+			d.w.Write(c.data)
+
+			d.mu.Lock()
+			d.queued -= len(c.data)
+			d.written, d.writing = c.seq, 0
+			d.stuck = false
+			close(d.progress)
+			d.progress = make(chan struct{})
+			d.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package logger_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+// blockingWriter blocks writes until it is unblocked.
+type blockingWriter struct {
+	syncBuffer
+	once    sync.Once
+	blocked chan struct{}
+}
+
+func newBlockingWriter() *blockingWriter {
+	return &blockingWriter{blocked: make(chan struct{})}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.blocked
+	return w.syncBuffer.Write(p)
+}
+
+func (w *blockingWriter) unblock() {
+	w.once.Do(func() { close(w.blocked) })
+}
+
+func TestDeadlineWriter(t *testing.T) {
+	t.Run("write in time", func(t *testing.T) {
+		var b syncBuffer
+		w := log.NewDeadlineWriter(&b, time.Second, log.DropOnTimeout)
+		defer w.Close()
+
+		n, err := w.Write([]byte("foo\n"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, n)
+		assert.Equal(t, "foo\n", b.String())
+	})
+
+	t.Run("drop on timeout", func(t *testing.T) {
+		b := newBlockingWriter()
+		w := log.NewDeadlineWriter(b, 20*time.Millisecond, log.DropOnTimeout)
+		defer w.Close()
+		defer b.unblock()
+
+		_, err := w.Write([]byte("first\n")) // Blocked in the output
+		assert.NoError(t, err)
+		start := time.Now()
+		_, err = w.Write([]byte("second\n"))
+		assert.ErrorIs(t, err, log.ErrWriteTimeout)
+		assert.Less(t, time.Since(start), 10*time.Millisecond)
+
+		b.unblock()
+		assert.NoError(t, w.Flush())
+		_, err = w.Write([]byte("third\n"))
+		assert.NoError(t, err)
+
+		assert.Equal(t, "first\nthird\n", b.String())
+		assert.Equal(t, int64(1), w.Dropped())
+	})
+
+	t.Run("buffer on timeout", func(t *testing.T) {
+		b := newBlockingWriter()
+		w := log.NewDeadlineWriter(b, 20*time.Millisecond, log.BufferOnTimeout)
+		defer w.Close()
+		defer b.unblock()
+
+		_, err := w.Write([]byte("first\n"))
+		assert.NoError(t, err)
+		_, err = w.Write([]byte("second\n"))
+		assert.NoError(t, err)
+		_, err = w.Write(make([]byte, log.MaxBufferedBytes))
+		assert.ErrorIs(t, err, log.ErrWriteTimeout)
+
+		b.unblock()
+		assert.NoError(t, w.Flush())
+
+		assert.Equal(t, "first\nsecond\n", b.String())
+		assert.Equal(t, int64(1), w.Dropped())
+	})
+
+	t.Run("use with logger", func(t *testing.T) {
+		b := newBlockingWriter()
+		w := log.NewDeadlineWriter(b, 20*time.Millisecond, log.DropOnTimeout)
+		defer w.Close()
+		defer b.unblock()
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			l := log.New(w).WithOutputMode(log.PlainMode)
+			for i := 0; i < 100; i++ {
+				l.Print("line")
+			}
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("logger is blocked")
+		}
+	})
+}
@@ -0,0 +1,27 @@
+//go:build !kliolog_nodebug
+
+package logger
+
+// DebugEnabled is false if the package was built with the kliolog_nodebug build tag. In such case Spam, Debug, Spamf
+// and Debugf functions do nothing, and code guarded with "if logger.DebugEnabled" is removed by the compiler.
+const DebugEnabled = true
+
+// Spam writes a message at level Spam on the standard logger. Arguments are handled in the manner of fmt.Print.
+func Spam(v ...interface{}) {
+	standardLevels.get(SpamLevel).Print(v...)
+}
+
+// Debug writes a message at level Debug on the standard logger. Arguments are handled in the manner of fmt.Print.
+func Debug(v ...interface{}) {
+	standardLevels.get(DebugLevel).Print(v...)
+}
+
+// Spamf writes a message at level Spam on the standard logger. Arguments are handled in the manner of fmt.Printf.
+func Spamf(format string, v ...interface{}) {
+	standardLevels.get(SpamLevel).Printf(format, v...)
+}
+
+// Debugf writes a message at level Debug on the standard logger. Arguments are handled in the manner of fmt.Printf.
+func Debugf(format string, v ...interface{}) {
+	standardLevels.get(DebugLevel).Printf(format, v...)
+}
@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// debugState is reported by DebugVar and DebugHandler.
+type debugState struct {
+	diagnosticsConfig
+	Histograms WriteHistograms `json:"histograms"`
+}
+
+// DebugVar returns variable reporting live state of a logger as JSON: level, tags, threshold, vmodule, filters, tag
+// limits, sinks, statistics (including queue depth and dropped lines) and write histograms. It can be published using
+// expvar:
+//
+//	expvar.Publish("logger", l.DebugVar())
+func (l *Logger) DebugVar() DebugVar {
+	return DebugVar{l: l}
+}
+
+// DebugVar reports live state of a logger, it implements expvar.Var.
+type DebugVar struct {
+	l *Logger
+}
+
+// String returns current state as JSON. If the state cannot be encoded, it returns the error as a JSON string, so
+// documents embedding the variable (e.g. /debug/vars) stay valid.
+func (v DebugVar) String() string {
+	data, err := json.Marshal(v.l.debugState())
+	if err != nil {
+		data, _ = json.Marshal(err.Error())
+	}
+	return string(data)
+}
+
+// DebugHandler returns handler serving live state of a logger (see DebugVar) as JSON, which can be mounted on debug
+// endpoints embedded in commands:
+//
+//	mux.Handle("/debug/logger", l.DebugHandler())
+func (l *Logger) DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(l.debugState())
+	})
+}
+
+func (l *Logger) debugState() debugState {
+	return debugState{diagnosticsConfig: l.describe(), Histograms: l.WriteHistograms()}
+}
@@ -0,0 +1,60 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"expvar"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestDebugVar(t *testing.T) {
+	var b bytes.Buffer
+	l := log.New(&b).WithOutputMode(log.PlainMode).WithTags("deploy").WithThreshold(log.VerboseLevel).
+		WithFilter(func(log.Record) bool { return true })
+	l.Print("foo")
+
+	expvar.Publish("klio_logger_debug_test", l.DebugVar())
+	var state map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(expvar.Get("klio_logger_debug_test").String()), &state))
+
+	assert.Equal(t, "info", state["level"])
+	assert.Equal(t, "verbose", state["threshold"])
+	assert.Equal(t, []interface{}{"deploy"}, state["tags"])
+	assert.Equal(t, float64(1), state["filters"])
+	assert.Equal(t, map[string]interface{}{"queue_depth": float64(0), "dropped": float64(0), "write_errors": float64(0),
+		"bytes_written": float64(len("[INFO][DEPLOY] foo\n"))}, state["stats"])
+	assert.Contains(t, state, "histograms")
+}
+
+func TestDebugVarFields(t *testing.T) {
+	l := log.New(&bytes.Buffer{}).WithRedaction(log.RedactionPreset("password-param")...).WithScrubbing(log.ScrubRemove, "url", "ch").
+		WithField("url", "/?token=abc").WithField("email", "jane@example.com").WithField("ch", make(chan int))
+
+	var state map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(l.DebugVar().String()), &state))
+
+	if assert.IsType(t, map[string]interface{}{}, state["fields"]) {
+		fields := state["fields"].(map[string]interface{})
+		assert.Equal(t, "/?token=[REDACTED]", fields["url"])
+		assert.NotContains(t, fields, "email")
+		assert.IsType(t, "", fields["ch"])
+	}
+}
+
+func TestDebugHandler(t *testing.T) {
+	l := log.New(&bytes.Buffer{}).WithLevel(log.WarnLevel).WithSink(&recordingSink{})
+	rec := httptest.NewRecorder()
+
+	l.DebugHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/debug/logger", nil))
+
+	var state map[string]interface{}
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &state))
+	assert.Equal(t, "warn", state["level"])
+	assert.Equal(t, []interface{}{"*logger_test.recordingSink"}, state["sinks"])
+}
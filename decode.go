@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// Record is a single log line. Records passed to filters, hooks, sinks and collectors share Tags and Fields with the
+// logger and with each other, so they must not be modified. The logger never reuses them, so they can be retained.
+type Record struct {
+	Level   Level
+	Tags    []string
+	Message string
+	Fields  []Field
+}
+
+const (
+	sequencePrefix = "\033_klio_"
+	sequenceEnd    = "\033\\"
+	resetSequence  = "\033_klio_reset\033\\"
+)
+
+// IsDecorated reports whether the line starts with control sequences interpreted by Klio.
+func IsDecorated(line string) bool {
+	return strings.HasPrefix(line, sequencePrefix)
+}
+
+// ParseLine decodes single line (without trailing newline) decorated with control sequences interpreted by Klio. If
+// the line is not decorated, it returns a record with DefaultLevel, no tags and the line as a message, and ok is false.
+func ParseLine(line string) (r Record, ok bool) {
+	r = Record{Level: DefaultLevel, Tags: []string{}, Message: line}
+	if !IsDecorated(line) {
+		return r, false
+	}
+
+	s := line
+	for strings.HasPrefix(s, sequencePrefix) {
+		end := strings.Index(s, sequenceEnd)
+		if end < 0 {
+			break
+		}
+		name, arg := s[len(sequencePrefix):end], ""
+		if i := strings.IndexByte(name, ' '); i >= 0 {
+			name, arg = name[:i], name[i+1:]
+		}
+		s = s[end+len(sequenceEnd):]
+
+		switch name {
+		case "log_level":
+			var level Level
+			if json.Unmarshal([]byte(arg), &level) == nil {
+				r.Level = level
+			}
+		case "tags":
+			if tags, ok := decodeTags(arg); ok {
+				r.Tags = tags
+			}
+		}
+	}
+	r.Message = strings.TrimSuffix(s, resetSequence)
+
+	return r, true
+}
+
+// writeDecorated writes line decorated by another command. The line is decoded and printed using its level and tags, so
+// it goes through thresholds, redaction, filters and sinks like any other line.
+func (l *Logger) writeDecorated(line string) {
+	r, _ := ParseLine(line)
+	l.WithLevel(r.Level).WithTags(r.Tags...).Print(r.Message)
+}
+
+// Decoder reads records from output decorated with control sequences interpreted by Klio. Lines are not limited in
+// length.
+type Decoder struct {
+	reader *bufio.Reader
+}
+
+// NewDecoder creates new decoder reading from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{reader: bufio.NewReader(r)}
+}
+
+// Decode reads next record. Lines which are not decorated are returned as records with DefaultLevel and no tags. It
+// returns io.EOF when there are no more records.
+func (d *Decoder) Decode() (Record, error) {
+	line, err := d.reader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return Record{}, err
+	}
+	line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+	r, _ := ParseLine(line)
+	return r, nil
+}
@@ -0,0 +1,78 @@
+package logger_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestParseLine(t *testing.T) {
+	t.Run("parse decorated line", func(t *testing.T) {
+		var b bytes.Buffer
+		log.New(&b).WithTags("a", "\033\\").WithLevel(log.WarnLevel).Print("foo")
+
+		r, ok := log.ParseLine(strings.TrimSuffix(b.String(), "\n"))
+		assert.True(t, ok)
+		assert.Equal(t, log.Record{Level: log.WarnLevel, Tags: []string{"a", "\033\\"}, Message: "foo"}, r)
+	})
+
+	t.Run("parse line with level only", func(t *testing.T) {
+		r, ok := log.ParseLine("\033_klio_log_level \"debug\"\033\\foo")
+		assert.True(t, ok)
+		assert.Equal(t, log.Record{Level: log.DebugLevel, Tags: []string{}, Message: "foo"}, r)
+	})
+
+	t.Run("parse plain line", func(t *testing.T) {
+		r, ok := log.ParseLine("foo")
+		assert.False(t, ok)
+		assert.Equal(t, log.Record{Level: log.DefaultLevel, Tags: []string{}, Message: "foo"}, r)
+	})
+}
+
+func TestDecoder(t *testing.T) {
+	var b bytes.Buffer
+	log.New(&b).WithTags("a").Print("foo")
+	b.WriteString("bar\n")
+
+	d := log.NewDecoder(&b)
+
+	r, err := d.Decode()
+	assert.NoError(t, err)
+	assert.Equal(t, log.Record{Level: log.InfoLevel, Tags: []string{"a"}, Message: "foo"}, r)
+
+	r, err = d.Decode()
+	assert.NoError(t, err)
+	assert.Equal(t, log.Record{Level: log.InfoLevel, Tags: []string{}, Message: "bar"}, r)
+
+	_, err = d.Decode()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestDecoderLongLines(t *testing.T) {
+	long := strings.Repeat("x", 1<<20)
+	var b bytes.Buffer
+	log.New(&b).Print(long)
+	b.WriteString("last\r\nunterminated")
+
+	d := log.NewDecoder(&b)
+
+	r, err := d.Decode()
+	assert.NoError(t, err)
+	assert.Equal(t, long, r.Message)
+
+	r, err = d.Decode()
+	assert.NoError(t, err)
+	assert.Equal(t, "last", r.Message)
+
+	r, err = d.Decode()
+	assert.NoError(t, err)
+	assert.Equal(t, "unterminated", r.Message)
+
+	_, err = d.Decode()
+	assert.Equal(t, io.EOF, err)
+}
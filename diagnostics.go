@@ -0,0 +1,207 @@
+package logger
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+)
+
+// diagnosticsConfig describes settings of a logger in a diagnostics bundle.
+type diagnosticsConfig struct {
+	Level      Level           `json:"level"`
+	Tags       []string        `json:"tags"`
+	Name       string          `json:"name,omitempty"`
+	Mode       string          `json:"mode"`
+	Output     string          `json:"output"`
+	Threshold  Level           `json:"threshold,omitempty"`
+	VModule    string          `json:"vmodule,omitempty"`
+	Fields     json.RawMessage `json:"fields,omitempty"`
+	Timestamp  string          `json:"timestamp,omitempty"`
+	TimeZone   string          `json:"time_zone"`
+	Trusted    bool            `json:"trusted,omitempty"`
+	Sequence   bool            `json:"sequence,omitempty"`
+	RingBuffer bool            `json:"ring_buffer"`
+	Filters    int             `json:"filters,omitempty"`
+	TagPrefix  string          `json:"tag_prefix,omitempty"`
+	TagLimits  TagLimits       `json:"tag_limits"`
+	Sinks      []string        `json:"sinks,omitempty"`
+	Stats      Stats           `json:"stats"`
+}
+
+// diagnosticsBuild describes the binary in a diagnostics bundle.
+type diagnosticsBuild struct {
+	GoVersion string            `json:"go_version"`
+	OS        string            `json:"os"`
+	Arch      string            `json:"arch"`
+	Path      string            `json:"path,omitempty"`
+	Version   string            `json:"version,omitempty"`
+	Settings  map[string]string `json:"settings,omitempty"`
+	Deps      map[string]string `json:"deps,omitempty"`
+}
+
+// WriteDiagnosticsBundle writes a zip archive to attach to bug reports. It contains lines stored in the ring buffer of
+// the logger (logs.txt, see WithRingBuffer), settings of the logger (logger.json), information about the binary
+// (build.json) and the environment (env.txt). Secrets and credentials in environment variables are redacted, and
+// redaction and scrubbing rules of the logger apply to fields and environment variables.
+func (l *Logger) WriteDiagnosticsBundle(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(f)
+	err = l.writeDiagnostics(zw)
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// describe returns current configuration and statistics of the logger. Fields are scrubbed and redacted like fields of
+// written lines, values which cannot be encoded as JSON are stringified.
+func (l *Logger) describe() diagnosticsConfig {
+	l.mu.RLock()
+	o := l.options
+	l.mu.RUnlock()
+
+	config := diagnosticsConfig{
+		Level:      o.level,
+		Tags:       o.tags,
+		Name:       o.name,
+		Mode:       string(o.format),
+		Output:     describeOutput(o.output),
+		Threshold:  o.threshold,
+		VModule:    o.vmodule.String(),
+		Timestamp:  o.timeLayout,
+		TimeZone:   o.location.String(),
+		Trusted:    o.trusted,
+		Sequence:   o.sequence != nil,
+		RingBuffer: o.ring != nil,
+		Filters:    len(o.filters),
+		TagPrefix:  o.tagPrefix,
+		TagLimits:  o.tagLimits,
+		Stats:      l.Stats(),
+	}
+	fields := o.fields
+	if o.scrubber != nil {
+		fields = o.scrubber.scrub(fields)
+	}
+	if len(o.redactions) > 0 {
+		fields = redactFields(o.redactions, fields, map[string]bool{})
+	}
+	if len(fields) > 0 {
+		config.Fields, _ = marshalFields(fields)
+	}
+	for _, sink := range o.sinks {
+		config.Sinks = append(config.Sinks, describeOutput(sink))
+	}
+	return config
+}
+
+func (l *Logger) writeDiagnostics(zw *zip.Writer) error {
+	l.mu.RLock()
+	o := l.options
+	l.mu.RUnlock()
+
+	var logs strings.Builder
+	if o.ring != nil {
+		for _, r := range o.ring.Records() {
+			logs.WriteString(humanLinePrefix(r.Level, r.Tags, false, nil) + r.Message + renderFields(r.Fields) + "\n")
+		}
+	}
+
+	config := l.describe()
+
+	build := diagnosticsBuild{GoVersion: runtime.Version(), OS: runtime.GOOS, Arch: runtime.GOARCH}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		build.Path, build.Version = info.Main.Path, info.Main.Version
+		build.Settings = map[string]string{}
+		for _, s := range info.Settings {
+			build.Settings[s.Key] = s.Value
+		}
+		build.Deps = map[string]string{}
+		for _, d := range info.Deps {
+			build.Deps[d.Path] = d.Version
+		}
+	}
+
+	files := []struct {
+		name string
+		data func() ([]byte, error)
+	}{
+		{"logs.txt", func() ([]byte, error) { return []byte(logs.String()), nil }},
+		{"logger.json", func() ([]byte, error) { return json.MarshalIndent(config, "", "  ") }},
+		{"build.json", func() ([]byte, error) { return json.MarshalIndent(build, "", "  ") }},
+		{"env.txt", func() ([]byte, error) { return []byte(environmentSnapshot(o.redactions, o.scrubber)), nil }},
+	}
+	for _, file := range files {
+		data, err := file.data()
+		if err != nil {
+			return err
+		}
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: time.Now()})
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// describeOutput returns name of the output, e.g. "stdout" or path of a file.
+func describeOutput(w interface{}) string {
+	switch w {
+	case os.Stdout:
+		return "stdout"
+	case os.Stderr:
+		return "stderr"
+	}
+	if f, ok := w.(interface{ Name() string }); ok {
+		return f.Name()
+	}
+	return fmt.Sprintf("%T", w)
+}
+
+// environmentSnapshot returns sorted environment variables. Values of variables which look like secrets are redacted,
+// credentials in other values are masked using "url-credentials", "bearer" and secret detection presets followed by
+// redaction rules of the logger. Variables are scrubbed like fields of the logger.
+func environmentSnapshot(redactions []RedactionRule, s *scrubber) string {
+	rules := append(append([]RedactionRule(nil), redactionPresets["url-credentials"]...), redactionPresets["bearer"]...)
+	rules = append(append(rules, secretDetectors...), redactions...)
+
+	env := os.Environ()
+	sort.Strings(env)
+	vars := make([]Field, 0, len(env))
+	for _, kv := range env {
+		key, value := kv, ""
+		if i := strings.IndexByte(kv, '='); i >= 0 {
+			key, value = kv[:i], kv[i+1:]
+		}
+		if isSecretKey(key) {
+			value = RedactedValue
+		} else {
+			value = redactString(rules, value, map[string]bool{})
+		}
+		vars = append(vars, Field{Key: key, Value: value})
+	}
+	if s != nil {
+		vars = s.scrub(vars)
+	}
+
+	var b strings.Builder
+	for _, v := range vars {
+		b.WriteString(v.Key + "=" + safeSprint(v.Value) + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,213 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffContext is the number of unchanged lines surrounding changes in output of Diff.
+const DiffContext = 3
+
+type diffLine struct {
+	op   byte // ' ', '-' or '+'
+	text string
+}
+
+// Diff writes unified diff of two texts in a single, multi-line record prefixed by label. In ColorMode added and
+// removed lines are colored. Very large changes are shown as removal of all changed lines followed by addition of new
+// ones, instead of finding the shortest diff.
+func (l *Logger) Diff(level Level, label string, old, new string) *Logger {
+	l = l.WithLevel(level)
+
+	hunks := diffHunks(diffLines(splitLines(old), splitLines(new)), DiffContext)
+	if len(hunks) == 0 {
+		return l.Print(label + ": no changes")
+	}
+
+	l.mu.RLock()
+	color := l.format == ColorMode
+	l.mu.RUnlock()
+
+	var b strings.Builder
+	b.WriteString(label + ":")
+	for _, h := range hunks {
+		b.WriteString("\n" + colorize(h.header(), "\033[36m", color))
+		for _, line := range h.lines {
+			text := string(line.op) + line.text
+			switch line.op {
+			case '-':
+				text = colorize(text, "\033[31m", color)
+			case '+':
+				text = colorize(text, "\033[32m", color)
+			}
+			b.WriteString("\n" + text)
+		}
+	}
+	return l.Print(b.String())
+}
+
+func colorize(s, color string, enabled bool) string {
+	if !enabled {
+		return s
+	}
+	return color + s + "\033[0m"
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// maxDiffCells limits size of the table used to find the longest common subsequence of changed lines. Larger changes
+// are shown as removal of all changed lines followed by addition of new ones, so memory used by Diff stays bounded.
+const maxDiffCells = 1 << 20
+
+// diffLines returns edit script transforming a into b, based on the longest common subsequence. Common prefix and
+// suffix are skipped before comparing lines, so small changes of large texts are cheap.
+func diffLines(a, b []string) []diffLine {
+	var r []diffLine
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		r = append(r, diffLine{' ', a[0]})
+		a, b = a[1:], b[1:]
+	}
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+	suffix := a[len(a)-n:]
+	a, b = a[:len(a)-n], b[:len(b)-n]
+
+	if (len(a)+1)*(len(b)+1) > maxDiffCells {
+		for _, line := range a {
+			r = append(r, diffLine{'-', line})
+		}
+		for _, line := range b {
+			r = append(r, diffLine{'+', line})
+		}
+	} else {
+		r = append(r, diffLCS(a, b)...)
+	}
+	for _, line := range suffix {
+		r = append(r, diffLine{' ', line})
+	}
+	return r
+}
+
+// diffLCS returns edit script transforming a into b, based on the longest common subsequence.
+func diffLCS(a, b []string) []diffLine {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var r []diffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			r = append(r, diffLine{' ', a[i]})
+			i, j = i+1, j+1
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			r = append(r, diffLine{'-', a[i]})
+			i++
+		default:
+			r = append(r, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		r = append(r, diffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		r = append(r, diffLine{'+', b[j]})
+	}
+	return r
+}
+
+type diffHunk struct {
+	oldStart, oldLen int
+	newStart, newLen int
+	lines            []diffLine
+}
+
+func (h diffHunk) header() string {
+	oldStart, newStart := h.oldStart, h.newStart
+	if h.oldLen > 0 {
+		oldStart++
+	}
+	if h.newLen > 0 {
+		newStart++
+	}
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", oldStart, h.oldLen, newStart, h.newLen)
+}
+
+// diffHunks groups changes from the edit script into hunks with specified number of context lines.
+func diffHunks(lines []diffLine, context int) []diffHunk {
+	var hunks []diffHunk
+	oldPos, newPos := make([]int, len(lines)), make([]int, len(lines))
+	o, n := 0, 0
+	for i, line := range lines {
+		oldPos[i], newPos[i] = o, n
+		if line.op != '+' {
+			o++
+		}
+		if line.op != '-' {
+			n++
+		}
+	}
+
+	for i := 0; i < len(lines); {
+		if lines[i].op == ' ' {
+			i++
+			continue
+		}
+
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for end < len(lines) {
+			// Find the next change within reach of the context, otherwise close the hunk.
+			next := end + 1
+			for next < len(lines) && lines[next].op == ' ' && next-end <= 2*context {
+				next++
+			}
+			if next < len(lines) && lines[next].op != ' ' && next-end <= 2*context+1 {
+				end = next
+				continue
+			}
+			break
+		}
+		end += context + 1
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		h := diffHunk{oldStart: oldPos[start], newStart: newPos[start], lines: lines[start:end]}
+		for _, line := range h.lines {
+			if line.op != '+' {
+				h.oldLen++
+			}
+			if line.op != '-' {
+				h.newLen++
+			}
+		}
+		hunks = append(hunks, h)
+		i = end
+	}
+	return hunks
+}
@@ -0,0 +1,101 @@
+package logger_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestDiff(t *testing.T) {
+	old := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n"
+	new := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n"
+
+	tests := []struct {
+		name     string
+		mode     log.OutputMode
+		old, new string
+		expected string
+	}{
+		{
+			name: "write unified diff",
+			mode: log.PlainMode,
+			old:  old,
+			new:  new,
+			expected: "[INFO] Changes:\n" +
+				"@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n" +
+				"@@ -10,3 +10,4 @@\n j\n k\n l\n+m\n",
+		},
+		{
+			name: "merge close changes",
+			mode: log.PlainMode,
+			old:  "a\nb\nc\nd\ne\nf\ng\n",
+			new:  "A\nb\nc\nd\ne\nf\nG\n",
+			expected: "[INFO] Changes:\n" +
+				"@@ -1,7 +1,7 @@\n-a\n+A\n b\n c\n d\n e\n f\n-g\n+G\n",
+		},
+		{
+			name:     "diff against empty text",
+			mode:     log.PlainMode,
+			old:      "",
+			new:      "a\n",
+			expected: "[INFO] Changes:\n@@ -0,0 +1,1 @@\n+a\n",
+		},
+		{
+			name:     "write no changes",
+			mode:     log.PlainMode,
+			old:      old,
+			new:      old,
+			expected: "[INFO] Changes: no changes\n",
+		},
+		{
+			name: "color changes",
+			mode: log.ColorMode,
+			old:  "a\n",
+			new:  "b\n",
+			expected: "[INFO] Changes:\n" +
+				"\033[36m@@ -1,1 +1,1 @@\033[0m\n\033[31m-a\033[0m\n\033[32m+b\033[0m\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var b bytes.Buffer
+			log.New(&b).WithOutputMode(test.mode).Diff(log.InfoLevel, "Changes", test.old, test.new)
+			assert.Equal(t, test.expected, b.String())
+		})
+	}
+
+	t.Run("keep markers in klio mode", func(t *testing.T) {
+		var b bytes.Buffer
+		log.New(&b).WithOutputMode(log.KlioMode).Diff(log.InfoLevel, "Changes", "a\n", "b\n")
+		assert.Contains(t, b.String(), "\n-a\n+b")
+	})
+
+	t.Run("diff large texts", func(t *testing.T) {
+		var old, new, changed strings.Builder
+		for i := 0; i < 10000; i++ {
+			fmt.Fprintf(&old, "line %d\n", i)
+			fmt.Fprintf(&changed, "changed %d\n", i)
+			if i == 5000 {
+				new.WriteString("inserted\n")
+			}
+			fmt.Fprintf(&new, "line %d\n", i)
+		}
+
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.PlainMode)
+		l.Diff(log.InfoLevel, "Changes", old.String(), new.String())
+		assert.Equal(t, "[INFO] Changes:\n@@ -4998,6 +4998,7 @@\n"+
+			" line 4997\n line 4998\n line 4999\n+inserted\n line 5000\n line 5001\n line 5002\n", b.String())
+
+		b.Reset()
+		l.Diff(log.InfoLevel, "Changes", old.String(), changed.String())
+		assert.True(t, strings.HasPrefix(b.String(), "[INFO] Changes:\n@@ -1,10000 +1,10000 @@\n-line 0\n-line 1\n"))
+		assert.Equal(t, 20002, strings.Count(b.String(), "\n"))
+	})
+}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// RedactedValue replaces values of secret fields in output of Dump.
+const RedactedValue = "[REDACTED]"
+
+var secretKeys = []string{"password", "passwd", "secret", "token", "apikey", "api_key", "private_key", "credential"}
+
+// Dump writes a value as indented JSON in a single, multi-line record prefixed by label. Values of keys which look like
+// secrets (e.g. "password", "api_key", "token") are replaced with RedactedValue.
+func (l *Logger) Dump(level Level, label string, v interface{}) *Logger {
+	l = l.WithLevel(level)
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return l.Printf("%s: cannot dump value: %s", label, err)
+	}
+	// Numbers are decoded as json.Number, so large integers aren't rounded to float64
+	var value interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return l.Printf("%s: cannot dump value: %s", label, err)
+	}
+	if data, err = json.MarshalIndent(redact(value), "", "  "); err != nil {
+		return l.Printf("%s: cannot dump value: %s", label, err)
+	}
+
+	return l.Print(label + ":\n" + string(data))
+}
+
+func redact(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if isSecretKey(key) {
+				v[key] = RedactedValue
+			} else {
+				v[key] = redact(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redact(value)
+		}
+	}
+	return v
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range secretKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
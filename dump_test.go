@@ -0,0 +1,69 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestDump(t *testing.T) {
+	t.Run("dump value with redacted secrets", func(t *testing.T) {
+		type database struct {
+			Host     string `json:"host"`
+			Password string `json:"password"`
+		}
+		config := struct {
+			Name      string            `json:"name"`
+			Databases []database        `json:"databases"`
+			Env       map[string]string `json:"env"`
+		}{
+			Name:      "app",
+			Databases: []database{{Host: "db", Password: "hunter2"}},
+			Env:       map[string]string{"GITHUB_TOKEN": "abc", "HOME": "/root"},
+		}
+
+		var b bytes.Buffer
+		log.New(&b).WithOutputMode(log.PlainMode).Dump(log.DebugLevel, "Config", config)
+
+		assert.Equal(t, "[DEBUG] Config:\n"+
+			"{\n"+
+			"  \"databases\": [\n"+
+			"    {\n"+
+			"      \"host\": \"db\",\n"+
+			"      \"password\": \"[REDACTED]\"\n"+
+			"    }\n"+
+			"  ],\n"+
+			"  \"env\": {\n"+
+			"    \"GITHUB_TOKEN\": \"[REDACTED]\",\n"+
+			"    \"HOME\": \"/root\"\n"+
+			"  },\n"+
+			"  \"name\": \"app\"\n"+
+			"}\n", b.String())
+	})
+
+	t.Run("keep precision of large integers", func(t *testing.T) {
+		var b bytes.Buffer
+		log.New(&b).WithOutputMode(log.PlainMode).Dump(log.InfoLevel, "Order", map[string]interface{}{
+			"id":     int64(1234567890123456789),
+			"amount": uint64(1<<63 + 1),
+			"ratio":  0.5,
+		})
+
+		assert.Equal(t, "[INFO] Order:\n"+
+			"{\n"+
+			"  \"amount\": 9223372036854775809,\n"+
+			"  \"id\": 1234567890123456789,\n"+
+			"  \"ratio\": 0.5\n"+
+			"}\n", b.String())
+	})
+
+	t.Run("report values which cannot be dumped", func(t *testing.T) {
+		var b bytes.Buffer
+		log.New(&b).WithOutputMode(log.PlainMode).Dump(log.InfoLevel, "Channel", make(chan int))
+
+		assert.Equal(t, "[INFO] Channel: cannot dump value: json: unsupported type: chan int\n", b.String())
+	})
+}
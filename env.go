@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ProtocolVersion is the version of Klio output protocol used by loggers by default, see WithProtocolVersion.
+const ProtocolVersion = 1
+
+const (
+	// EnvLevel is the name of the environment variable with the default level of a command.
+	EnvLevel = "KLIO_LOG_LEVEL"
+	// EnvTags is the name of the environment variable with comma-separated tags of a command. The standard and error
+	// loggers are created with these tags, so wrapper scripts can label a whole command run, e.g.
+	// KLIO_LOG_TAGS=release,eu-west.
+	EnvTags = "KLIO_LOG_TAGS"
+	// EnvThreshold is the name of the environment variable with the least severe level written by a command.
+	EnvThreshold = "KLIO_LOG_THRESHOLD"
+	// EnvVModule is the name of the environment variable with VModule spec used by the standard and error loggers.
+	EnvVModule = "KLIO_LOG_VMODULE"
+	// EnvProtocol is the name of the environment variable with the version of Klio output protocol.
+	EnvProtocol = "KLIO_LOG_PROTOCOL"
+	// EnvLogFD is the name of the environment variable with the file descriptor logs should be written to.
+	EnvLogFD = "KLIO_LOG_FD"
+)
+
+// ChildEnv returns KLIO_* environment variables describing the logger (tags, threshold, vmodule, correlation ID, file
+// descriptor of the output and run ID), so nested Klio-aware commands inherit logging configuration of the parent:
+//
+//	cmd.Env = append(os.Environ(), logger.ChildEnv(l)...)
+//
+// Level and protocol version are included only if the logger writes output interpreted by Klio, since their presence
+// makes children write it too (see IsKlio). File descriptor is included only if the logger writes to the stdout or
+// stderr. The standard and error loggers of the child are configured using these variables.
+func ChildEnv(l *Logger) []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var env []string
+	if l.format == KlioMode {
+		env = append(env, EnvLevel+"="+string(l.level))
+	}
+	env = append(env, EnvTags+"="+strings.Join(l.renderedTags(), ","))
+	if l.format == KlioMode {
+		env = append(env, EnvProtocol+"="+strconv.Itoa(l.protocol))
+	}
+	env = append(env, RunIDEnv())
+	if l.threshold != "" {
+		env = append(env, EnvThreshold+"="+string(l.threshold))
+	}
+	if len(l.vmodule) > 0 {
+		env = append(env, EnvVModule+"="+l.vmodule.String())
+	}
+	if l.correlationID != "" {
+		env = append(env, EnvCorrelationID+"="+l.correlationID)
+	}
+	switch l.output {
+	case os.Stdout:
+		env = append(env, EnvLogFD+"=1")
+	case os.Stderr:
+		env = append(env, EnvLogFD+"=2")
+	}
+	return env
+}
+
+// newStandardLogger creates the standard logger, writing to the file descriptor and using the level passed by the
+// parent process, if any.
+func newStandardLogger() *Logger {
+	output := os.Stdout
+	if os.Getenv(EnvLogFD) == "2" {
+		output = os.Stderr
+	}
+	l := New(output)
+	if level, ok := ParseLevel(os.Getenv(EnvLevel)); ok {
+		l = l.WithLevel(level)
+	}
+	return configureFromEnv(l)
+}
+
+// configureFromEnv applies tags, threshold, vmodule, protocol version and correlation ID passed by the parent process to
+// the logger.
+func configureFromEnv(l *Logger) *Logger {
+	l = l.WithTags(tagsFromEnv()...).WithVModule(vmoduleFromEnv()).WithCorrelationID(os.Getenv(EnvCorrelationID))
+	if threshold, ok := ParseLevel(os.Getenv(EnvThreshold)); ok {
+		l = l.WithThreshold(threshold)
+	}
+	if version, err := strconv.Atoi(os.Getenv(EnvProtocol)); err == nil {
+		l = l.WithProtocolVersion(version)
+	}
+	return l
+}
+
+// tagsFromEnv returns tags listed in EnvTags, skipping empty ones.
+func tagsFromEnv() []string {
+	var tags []string
+	for _, tag := range strings.Split(os.Getenv(EnvTags), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
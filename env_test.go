@@ -0,0 +1,86 @@
+package logger_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestChildEnv(t *testing.T) {
+	t.Run("describe logger", func(t *testing.T) {
+		v, _ := log.ParseVModule("helm=debug")
+		l := log.New(os.Stderr).WithName("cmd").WithTags("a", "b").WithLevel(log.VerboseLevel).WithThreshold(log.DebugLevel).WithVModule(v)
+
+		assert.Equal(t, []string{
+			"KLIO_LOG_LEVEL=verbose",
+			"KLIO_LOG_TAGS=cmd,a,b",
+			"KLIO_LOG_PROTOCOL=1",
+			"KLIO_RUN_ID=" + log.RunID(),
+			"KLIO_LOG_THRESHOLD=debug",
+			"KLIO_LOG_VMODULE=helm=debug",
+			"KLIO_LOG_FD=2",
+		}, log.ChildEnv(l))
+	})
+
+	t.Run("describe logger writing to other output", func(t *testing.T) {
+		l := log.New(nil)
+
+		assert.Equal(t, []string{
+			"KLIO_LOG_LEVEL=info",
+			"KLIO_LOG_TAGS=",
+			"KLIO_LOG_PROTOCOL=1",
+			"KLIO_RUN_ID=" + log.RunID(),
+		}, log.ChildEnv(l))
+	})
+}
+
+func TestChildEnvOutsideKlio(t *testing.T) {
+	l := log.New(nil).WithOutputMode(log.PlainMode).WithThreshold(log.WarnLevel)
+
+	assert.Equal(t, []string{
+		"KLIO_LOG_TAGS=",
+		"KLIO_RUN_ID=" + log.RunID(),
+		"KLIO_LOG_THRESHOLD=warn",
+	}, log.ChildEnv(l))
+}
+
+func TestChildEnvRoundTrip(t *testing.T) {
+	if os.Getenv("KLIO_TEST_CHILD_ENV") == "1" {
+		l := log.StandardLogger()
+		fmt.Println(l.Level(), l.Threshold(), l.Tags(), l.Output() == os.Stderr)
+		return
+	}
+
+	parent := log.New(os.Stderr).WithOutputMode(log.KlioMode).WithLevel(log.VerboseLevel).
+		WithThreshold(log.WarnLevel).WithTags("a")
+	cmd := exec.Command(os.Args[0], "-test.run=^TestChildEnvRoundTrip$")
+	cmd.Env = append(append(os.Environ(), "KLIO_TEST_CHILD_ENV=1"), log.ChildEnv(parent)...)
+	out, err := cmd.Output()
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(out), "verbose warn [a] true\n"), string(out))
+}
+
+func TestEnvTags(t *testing.T) {
+	if os.Getenv("KLIO_TEST_ENV_TAGS") == "1" {
+		log.Info("to stdout")
+		log.ErrorLogger().Print("to stderr")
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestEnvTags$")
+	cmd.Env = append(os.Environ(), "KLIO_TEST_ENV_TAGS=1", log.EnvMode+"=plain", log.EnvTags+"=release, eu-west,")
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), "[INFO][RELEASE][EU-WEST] to stdout\n")
+	assert.Equal(t, "[ERROR][RELEASE][EU-WEST] to stderr\n", stderr.String())
+}
@@ -0,0 +1,60 @@
+package logger
+
+import "strings"
+
+// WithErrorContext creates new logger instance which appends up to k most recent lines stored in the ring buffer (see
+// WithRingBuffer) to each line written at error or fatal level, as an indented block. Only less severe lines sharing
+// a tag with the error (or untagged lines for untagged errors) are included, e.g.:
+//
+//	[ERROR][GIT] clone failed
+//	  context:
+//	    [DEBUG][GIT] resolving github.com
+//	    [SPAM][GIT] connecting to 140.82.121.3:443
+func (l *Logger) WithErrorContext(k int) *Logger {
+	n := l.clone()
+	n.errorContext = k
+	return n
+}
+
+// context returns up to k most recent stored lines less severe than error level and sharing a tag with tags.
+func (rb *RingBuffer) context(tags []string, k int) []Record {
+	records := rb.Records()
+	var r []Record
+	for i := len(records) - 1; i >= 0 && len(r) < k; i-- {
+		if s, ok := levelSeverity[records[i].Level]; ok && s <= levelSeverity[ErrorLevel] {
+			continue
+		}
+		if sharesTag(records[i].Tags, tags) {
+			r = append([]Record{records[i]}, r...)
+		}
+	}
+	return r
+}
+
+func sharesTag(a, b []string) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	for _, x := range a {
+		for _, y := range b {
+			if strings.EqualFold(x, y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// renderContext returns lines formatted as an indented block appended to error messages.
+func renderContext(records []Record, trusted bool) string {
+	var b strings.Builder
+	b.WriteString("\n  context:")
+	for _, r := range records {
+		msg := r.Message
+		if !trusted {
+			msg = escapeMessage(msg)
+		}
+		b.WriteString("\n    " + humanLinePrefix(r.Level, r.Tags, false, nil) + msg + renderFields(r.Fields))
+	}
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrorField is the key of the field added by Logger.WithError and Err.
+const ErrorField = "error"
+
+// ErrorCause describes an error in a chain of wrapped errors.
+type ErrorCause struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+	// Details is the error formatted using "%+v", if it differs from Message.
+	Details string `json:"details,omitempty"`
+}
+
+// ErrorChain lists errors wrapped by a logged error, starting from the logged error itself. It is written as JSON.
+type ErrorChain []ErrorCause
+
+// String returns the chain as JSON.
+func (c ErrorChain) String() string {
+	data, _ := json.Marshal([]ErrorCause(c))
+	return string(data)
+}
+
+// Err returns field holding the error. Like all fields with error values, it is expanded by Logger.WithFields into the
+// error message ("error"), its type ("error_type"), for wrapped errors the whole chain ("error_chain") and the stack
+// trace if errors carry one ("error_stack", see StackTrace).
+func Err(err error) Field {
+	return Field{Key: ErrorField, Value: err}
+}
+
+// WithError creates new logger instance with the error attached as fields, see Err.
+func (l *Logger) WithError(err error) *Logger {
+	return l.WithFields(Err(err))
+}
+
+// expandErrors replaces fields holding errors with fields describing them.
+func expandErrors(fields []Field) []Field {
+	var r []Field
+	for i, f := range fields {
+		err, ok := f.Value.(error)
+		if !ok || err == nil {
+			if r != nil {
+				r = append(r, f)
+			}
+			continue
+		}
+		if r == nil {
+			r = append([]Field(nil), fields[:i]...)
+		}
+		chain, stack := errorChain(err)
+		r = append(r, Field{Key: f.Key, Value: safeError(err)}, Field{Key: f.Key + "_type", Value: chain[0].Type})
+		if len(chain) > 1 || chain[0].Details != "" {
+			r = append(r, Field{Key: f.Key + "_chain", Value: chain})
+		}
+		if stack != nil {
+			r = append(r, Field{Key: f.Key + "_stack", Value: stack})
+		}
+	}
+	if r == nil {
+		return fields
+	}
+	return r
+}
+
+// errorChain walks errors wrapped by err, including ones joined using errors.Join. It also returns the deepest stack
+// trace carried by the errors (see errorStack).
+func errorChain(err error) (chain ErrorChain, stack StackTrace) {
+	queue := []error{err}
+	for len(queue) > 0 {
+		e := queue[0]
+		queue = queue[1:]
+		if e == nil {
+			continue
+		}
+		cause := ErrorCause{Message: safeError(e), Type: fmt.Sprintf("%T", e)}
+		if s := errorStack(e); s != nil {
+			// Stack traces are written in a separate field instead of "%+v" output.
+			stack = s
+		} else if _, ok := e.(fmt.Formatter); ok {
+			if details := safeSprintf("%+v", e); details != cause.Message {
+				cause.Details = details
+			}
+		}
+		chain = append(chain, cause)
+
+		if u, ok := e.(interface{ Unwrap() []error }); ok {
+			queue = append(u.Unwrap(), queue...)
+		} else if u := errors.Unwrap(e); u != nil {
+			queue = append([]error{u}, queue...)
+		}
+	}
+	return chain, stack
+}
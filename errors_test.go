@@ -0,0 +1,64 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+// verboseError implements fmt.Formatter, printing details with "%+v".
+type verboseError struct{}
+
+func (verboseError) Error() string {
+	return "verbose"
+}
+
+func (e verboseError) Format(s fmt.State, verb rune) {
+	if s.Flag('+') {
+		fmt.Fprint(s, "verbose: details")
+		return
+	}
+	fmt.Fprint(s, e.Error())
+}
+
+func TestWithError(t *testing.T) {
+	t.Run("write simple error", func(t *testing.T) {
+		var b bytes.Buffer
+		log.New(&b).WithOutputMode(log.PlainMode).WithError(errors.New("boom")).Print("failed")
+
+		assert.Equal(t, "[INFO] failed error=boom error_type=*errors.errorString\n", b.String())
+	})
+
+	t.Run("write error chain", func(t *testing.T) {
+		err := fmt.Errorf("reading config: %w", &fs.PathError{Op: "open", Path: "a.yaml", Err: fs.ErrNotExist})
+
+		var b bytes.Buffer
+		log.New(&b).WithOutputMode(log.PlainMode).WithFields(log.Err(err)).Print("failed")
+
+		assert.Equal(t, "[INFO] failed "+
+			`error="reading config: open a.yaml: file does not exist" error_type=*fmt.wrapError `+
+			`error_chain="[`+
+			`{\"message\":\"reading config: open a.yaml: file does not exist\",\"type\":\"*fmt.wrapError\"},`+
+			`{\"message\":\"open a.yaml: file does not exist\",\"type\":\"*fs.PathError\"},`+
+			`{\"message\":\"file does not exist\",\"type\":\"*errors.errorString\"}`+
+			`]"`+"\n", b.String())
+	})
+
+	t.Run("expand errors in any field", func(t *testing.T) {
+		l := log.New(nil).WithField("cause", verboseError{})
+
+		assert.Equal(t, []log.Field{
+			{Key: "cause", Value: "verbose"},
+			{Key: "cause_type", Value: "logger_test.verboseError"},
+			{Key: "cause_chain", Value: log.ErrorChain{
+				{Message: "verbose", Type: "logger_test.verboseError", Details: "verbose: details"},
+			}},
+		}, l.Fields())
+	})
+}
@@ -0,0 +1,28 @@
+package logger
+
+import "strings"
+
+// escapeMessage neutralizes control sequences interpreted by Klio (they start with "ESC _"), so messages can't change
+// level or tags of lines. ESC is replaced with "^[", other escape sequences (e.g. colors) are left untouched.
+func escapeMessage(s string) string {
+	if !strings.Contains(s, "\033_") {
+		return s
+	}
+	return strings.ReplaceAll(s, "\033_", "^[_")
+}
+
+// TrustedMessages reports whether a logger writes messages without escaping (see WithTrustedMessages).
+func (l *Logger) TrustedMessages() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.trusted
+}
+
+// WithTrustedMessages creates new logger instance which writes messages as they are. By default, control sequences
+// interpreted by Klio are escaped, so messages can't change level or tags of lines. Use it only for commands which
+// deliberately emit their own Klio sequences.
+func (l *Logger) WithTrustedMessages() *Logger {
+	n := l.clone()
+	n.trusted = true
+	return n
+}
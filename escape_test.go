@@ -0,0 +1,32 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestEscaping(t *testing.T) {
+	t.Run("escape Klio sequences in messages", func(t *testing.T) {
+		var b bytes.Buffer
+		log.New(&b).Print("foo\033_klio_log_level \"fatal\"\033\\bar")
+		assert.Equal(t, "\033_klio_log_level \"info\"\033\\\033_klio_tags []\033\\foo^[_klio_log_level \"fatal\"\033\\bar\033_klio_reset\033\\\n", b.String())
+	})
+
+	t.Run("keep other escape sequences", func(t *testing.T) {
+		var b bytes.Buffer
+		log.New(&b).Print("\033[31mfoo\033[0m")
+		assert.Equal(t, "\033_klio_log_level \"info\"\033\\\033_klio_tags []\033\\\033[31mfoo\033[0m\033_klio_reset\033\\\n", b.String())
+	})
+
+	t.Run("write trusted messages as they are", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithTrustedMessages()
+		l.Print("foo\033_klio_tags [\"x\"]\033\\bar")
+		assert.True(t, l.TrustedMessages())
+		assert.Equal(t, "\033_klio_log_level \"info\"\033\\\033_klio_tags []\033\\foo\033_klio_tags [\"x\"]\033\\bar\033_klio_reset\033\\\n", b.String())
+	})
+}
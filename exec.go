@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Exec runs the command, streaming its stdout and stderr through the logger (see Ingester), unless they are already
+// set. Lifecycle of the process is logged too: start (command and working directory at debug level, environment
+// changes at spam level), completion (exit code, duration and peak memory usage where available, at debug level) and
+// termination by a signal (at warn level). It returns the error returned by cmd.Run.
+func Exec(l *Logger, cmd *exec.Cmd, opts ...IngestOption) error {
+	var ingesters []*Ingester
+	if cmd.Stdout == nil {
+		i := NewIngester(l, opts...)
+		ingesters = append(ingesters, i)
+		cmd.Stdout = i
+	}
+	if cmd.Stderr == nil {
+		i := NewIngester(l, opts...)
+		ingesters = append(ingesters, i)
+		cmd.Stderr = i
+	}
+
+	command := formatCommand(cmd.Args)
+	dir := cmd.Dir
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	l.WithLevel(DebugLevel).WithField("dir", dir).Print("Running: ", command)
+	if cmd.Env != nil {
+		if diff := envDiff(os.Environ(), cmd.Env); len(diff) > 0 {
+			l.WithLevel(SpamLevel).Print("Environment changes: ", strings.Join(diff, " "))
+		}
+	}
+
+	start := l.Clock().Now()
+	err := cmd.Run()
+	duration := l.since(start)
+
+	for _, i := range ingesters {
+		i.Close()
+	}
+
+	if state := cmd.ProcessState; state != nil {
+		fields := []Field{
+			{Key: "exit_code", Value: state.ExitCode()},
+			{Key: "duration", Value: duration.Round(time.Millisecond)},
+		}
+		signal, maxRSS := processDetails(state)
+		if maxRSS > 0 {
+			fields = append(fields, Field{Key: "max_rss", Value: formatBytes(maxRSS)})
+		}
+		if signal != "" {
+			l.WithLevel(WarnLevel).Print("Terminated by signal: ", signal)
+		}
+		l.WithLevel(DebugLevel).WithFields(fields...).Print("Finished: ", command)
+	}
+
+	return err
+}
+
+// formatCommand joins arguments of a command, quoting ones which contain spaces or quotes.
+func formatCommand(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'") {
+			arg = strconv.Quote(arg)
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
+
+// envDiff lists names of environment variables added (+), changed (~) and removed (-) in env compared to base. Values
+// are omitted, since they may contain secrets.
+func envDiff(base, env []string) []string {
+	parse := func(vars []string) map[string]string {
+		m := make(map[string]string, len(vars))
+		for _, v := range vars {
+			if i := strings.IndexByte(v, '='); i > 0 {
+				m[v[:i]] = v[i+1:]
+			}
+		}
+		return m
+	}
+	before, after := parse(base), parse(env)
+
+	diff := []string{}
+	for key, value := range after {
+		if old, ok := before[key]; !ok {
+			diff = append(diff, "+"+key)
+		} else if old != value {
+			diff = append(diff, "~"+key)
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			diff = append(diff, "-"+key)
+		}
+	}
+	sort.Slice(diff, func(i, j int) bool { return diff[i][1:] < diff[j][1:] })
+	return diff
+}
+
+// formatBytes formats size using binary units.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return strconv.FormatInt(n, 10) + "B"
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return strconv.FormatFloat(float64(n)/float64(div), 'f', 1, 64) + string("KMGTPE"[exp]) + "iB"
+}
@@ -0,0 +1,11 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+
+package logger
+
+import "os"
+
+// processDetails returns name of the signal which terminated the process and its peak memory usage in bytes. They are
+// not available on this platform.
+func processDetails(state *os.ProcessState) (signal string, maxRSS int64) {
+	return "", 0
+}
@@ -0,0 +1,43 @@
+package logger_test
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestExec(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	t.Run("log output and lifecycle", func(t *testing.T) {
+		var b bytes.Buffer
+		cmd := exec.Command("sh", "-c", "echo foo; echo bar >&2; exit 3")
+		cmd.Env = append(os.Environ(), "KLIO_TEST_VAR=1")
+
+		err := log.Exec(log.New(&b), cmd)
+
+		assert.Error(t, err)
+		out := b.String()
+		assert.Contains(t, out, "\033_klio_log_level \"debug\"\033\\\033_klio_tags []\033\\Running: sh -c \"echo foo; echo bar >&2; exit 3\" dir=")
+		assert.Contains(t, out, "\033_klio_log_level \"spam\"\033\\\033_klio_tags []\033\\Environment changes: +KLIO_TEST_VAR\033_klio_reset")
+		assert.Contains(t, out, "\033_klio_log_level \"info\"\033\\\033_klio_tags []\033\\foo\033_klio_reset")
+		assert.Contains(t, out, "\033_klio_log_level \"info\"\033\\\033_klio_tags []\033\\bar\033_klio_reset")
+		assert.Contains(t, out, "\033_klio_log_level \"debug\"\033\\\033_klio_tags []\033\\Finished: sh -c \"echo foo; echo bar >&2; exit 3\" exit_code=3 duration=")
+	})
+
+	t.Run("log termination by signal", func(t *testing.T) {
+		var b bytes.Buffer
+
+		err := log.Exec(log.New(&b), exec.Command("sh", "-c", "kill -9 $$"))
+
+		assert.Error(t, err)
+		assert.Contains(t, b.String(), "\033_klio_log_level \"warn\"\033\\\033_klio_tags []\033\\Terminated by signal: killed\033_klio_reset")
+	})
+}
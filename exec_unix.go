@@ -0,0 +1,23 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package logger
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+)
+
+// processDetails returns name of the signal which terminated the process and its peak memory usage in bytes.
+func processDetails(state *os.ProcessState) (signal string, maxRSS int64) {
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		signal = status.Signal().String()
+	}
+	if usage, ok := state.SysUsage().(*syscall.Rusage); ok && usage != nil {
+		maxRSS = int64(usage.Maxrss)
+		if runtime.GOOS != "darwin" {
+			maxRSS *= 1024 // Reported in kilobytes everywhere except macOS
+		}
+	}
+	return signal, maxRSS
+}
@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// mostSevere is severity of the most severe line written by any logger plus one, 0 if nothing was written.
+	mostSevere  int32
+	exitCodesMu sync.RWMutex
+	exitCodes   = map[Level]int{FatalLevel: 1, ErrorLevel: 1}
+	exitOnFatal int32
+)
+
+// ExitCode returns exit code based on the most severe line written by any logger so far. By default it is 1 if
+// anything was written at error or fatal level, 0 otherwise. Codes can be changed using SetExitCode.
+func ExitCode() int {
+	logged := atomic.LoadInt32(&mostSevere) - 1
+	if logged < 0 {
+		return 0
+	}
+
+	exitCodesMu.RLock()
+	defer exitCodesMu.RUnlock()
+	for _, level := range []Level{FatalLevel, ErrorLevel, WarnLevel, InfoLevel, VerboseLevel, DebugLevel, SpamLevel} {
+		if code, ok := exitCodes[level]; ok && int32(levelSeverity[level]) >= logged {
+			return code
+		}
+	}
+	return 0
+}
+
+// SetExitCode sets exit code returned by ExitCode if a line was written at the level, or at a more severe level which
+// doesn't have its own code. E.g. SetExitCode(WarnLevel, 2) fails a command which logged any warnings. Negative code
+// removes the level from consideration.
+func SetExitCode(level Level, code int) {
+	exitCodesMu.Lock()
+	defer exitCodesMu.Unlock()
+	if code < 0 {
+		delete(exitCodes, level)
+	} else {
+		exitCodes[level] = code
+	}
+}
+
+// ResetExitCode forgets lines written so far, so ExitCode returns 0 until something is logged again.
+func ResetExitCode() {
+	atomic.StoreInt32(&mostSevere, 0)
+}
+
+// Exit terminates the program with ExitCode. It is meant to be called at the end of main.
+func Exit() {
+	os.Exit(ExitCode())
+}
+
+// SetExitOnFatal makes Fatal and Fatalf terminate the program with ExitCode after writing a message.
+func SetExitOnFatal(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&exitOnFatal, 1)
+	} else {
+		atomic.StoreInt32(&exitOnFatal, 0)
+	}
+}
+
+// exitIfFatal runs fatal hooks and terminates the program if SetExitOnFatal was enabled.
+func exitIfFatal(r Record) {
+	if atomic.LoadInt32(&exitOnFatal) == 1 {
+		runFatalHooks(r)
+		Exit()
+	}
+}
+
+// noteSeverity remembers the level if it is the most severe level written so far. Lines which aren't more severe than
+// already written ones only load mostSevere, so goroutines writing concurrently don't contend for it.
+func noteSeverity(level Level) {
+	s, ok := levelSeverity[level]
+	if !ok {
+		return
+	}
+	for {
+		current := atomic.LoadInt32(&mostSevere)
+		if current != 0 && current <= int32(s)+1 {
+			return
+		}
+		if atomic.CompareAndSwapInt32(&mostSevere, current, int32(s)+1) {
+			return
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package logger_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestExitCode(t *testing.T) {
+	defer log.ResetExitCode()
+	defer log.SetExitCode(log.WarnLevel, -1)
+	defer log.SetExitCode(log.FatalLevel, 1)
+
+	l := log.New(io.Discard)
+
+	log.ResetExitCode()
+	assert.Equal(t, 0, log.ExitCode())
+
+	l.WithLevel(log.WarnLevel).Print("warning")
+	assert.Equal(t, 0, log.ExitCode())
+
+	log.SetExitCode(log.WarnLevel, 3)
+	assert.Equal(t, 3, log.ExitCode())
+
+	l.WithLevel(log.ErrorLevel).Print("error")
+	l.WithLevel(log.InfoLevel).Print("info")
+	assert.Equal(t, 1, log.ExitCode())
+
+	log.SetExitCode(log.FatalLevel, 2)
+	assert.Equal(t, 1, log.ExitCode())
+	l.WithLevel(log.FatalLevel).Print("fatal")
+	assert.Equal(t, 2, log.ExitCode())
+
+	log.ResetExitCode()
+	assert.Equal(t, 0, log.ExitCode())
+
+	l.WithLevel(log.ErrorLevel).WithThreshold(log.FatalLevel).Print("filtered error")
+	assert.Equal(t, 0, log.ExitCode())
+}
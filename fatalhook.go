@@ -0,0 +1,40 @@
+package logger
+
+import "sync"
+
+var (
+	fatalHooksMu sync.Mutex
+	fatalHooks   []func(Record)
+)
+
+// RegisterFatalHook registers a function called with the fatal record before the program is terminated by Fatal,
+// Fatalf (see SetExitOnFatal) or RecoverAndLog (see WithExit). Hooks may be used for cleanup, e.g. removing temporary
+// files. They are called in order of registration.
+func RegisterFatalHook(hook func(Record)) {
+	fatalHooksMu.Lock()
+	defer fatalHooksMu.Unlock()
+	fatalHooks = append(fatalHooks, hook)
+}
+
+// runFatalHooks calls registered fatal hooks.
+func runFatalHooks(r Record) {
+	fatalHooksMu.Lock()
+	hooks := make([]func(Record), len(fatalHooks))
+	copy(hooks, fatalHooks)
+	fatalHooksMu.Unlock()
+
+	for _, hook := range hooks {
+		hook(r)
+	}
+}
+
+// newRecord returns record describing the message written by a logger.
+func (l *Logger) newRecord(msg string) Record {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	tags := make([]string, 0, len(l.tags)+1)
+	tags = append(tags, l.renderedTags()...)
+	fields := make([]Field, len(l.fields))
+	copy(fields, l.fields)
+	return Record{Level: l.level, Tags: tags, Message: msg, Fields: fields}
+}
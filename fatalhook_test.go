@@ -0,0 +1,37 @@
+package logger_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestRegisterFatalHook(t *testing.T) {
+	if os.Getenv("KLIO_TEST_FATAL_HOOK") == "1" {
+		log.RegisterFatalHook(func(r log.Record) {
+			fmt.Printf("first hook: %s %s\n", r.Level, r.Message)
+		})
+		log.RegisterFatalHook(func(r log.Record) {
+			fmt.Println("second hook")
+		})
+		log.SetExitOnFatal(true)
+		log.Fatalf("failed to %s", "deploy")
+		fmt.Println("not terminated")
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestRegisterFatalHook$")
+	cmd.Env = append(os.Environ(), "KLIO_TEST_FATAL_HOOK=1", log.EnvMode+"=plain")
+	out, err := cmd.Output()
+
+	var exitErr *exec.ExitError
+	if assert.ErrorAs(t, err, &exitErr) {
+		assert.Equal(t, 1, exitErr.ExitCode())
+	}
+	assert.Equal(t, "[FATAL] failed to deploy\nfirst hook: fatal failed to deploy\nsecond hook\n", string(out))
+}
@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Field is a key-value pair attached to log lines. Since Klio doesn't interpret fields, they are appended to messages
+// in logfmt format (key=value).
+type Field struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// String returns field formatted as key=value. Values containing spaces, quotes or control characters are quoted.
+func (f Field) String() string {
+	return formatFieldValue(f.Key) + "=" + formatFieldValue(fieldValueString(f.Value))
+}
+
+// fieldValueString formats value in the manner of fmt.Sprint, avoiding fmt for the most common types.
+func fieldValueString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return safeSprint(v)
+	}
+}
+
+func formatFieldValue(s string) string {
+	if s == "" {
+		return `""`
+	}
+	for _, r := range s {
+		if r == '=' || r == '"' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
+
+// Fields returns fields used by a logger. Fields are appended to each line produced by a logger.
+func (l *Logger) Fields() []Field {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	r := make([]Field, len(l.fields))
+	copy(r, l.fields)
+	return r
+}
+
+// WithFields creates new logger instance with specified fields added. Fields replace existing fields with the same key.
+// Fields are appended to each line produced by a logger. Fields holding errors are expanded, see Err.
+func (l *Logger) WithFields(fields ...Field) *Logger {
+	fields = expandErrors(fields)
+	n := l.clone()
+	merged := make([]Field, 0, len(n.fields)+len(fields))
+	for _, f := range n.fields {
+		if !hasField(fields, f.Key) {
+			merged = append(merged, f)
+		}
+	}
+	for i, f := range fields {
+		if !hasField(fields[i+1:], f.Key) {
+			merged = append(merged, f)
+		}
+	}
+	n.fields = merged
+	n.updateLineSuffix()
+	return n
+}
+
+// WithField creates new logger instance with specified field added. See WithFields.
+func (l *Logger) WithField(key string, value interface{}) *Logger {
+	return l.WithFields(Field{Key: key, Value: value})
+}
+
+func (l *Logger) updateLineSuffix() {
+	l.lineSuffix = renderFields(l.fields)
+}
+
+// renderFields returns fields formatted as they are appended to messages, e.g. " foo=bar baz=1".
+func renderFields(fields []Field) string {
+	var b strings.Builder
+	for _, f := range fields {
+		b.WriteByte(' ')
+		b.WriteString(formatFieldValue(f.Key))
+		b.WriteByte('=')
+		b.WriteString(formatFieldValue(fieldValueString(f.Value)))
+	}
+	return b.String()
+}
+
+func hasField(fields []Field, key string) bool {
+	for _, f := range fields {
+		if f.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestField(t *testing.T) {
+	assert.Equal(t, "foo=bar", log.Field{Key: "foo", Value: "bar"}.String())
+	assert.Equal(t, "foo=42", log.Field{Key: "foo", Value: 42}.String())
+	assert.Equal(t, `foo=""`, log.Field{Key: "foo", Value: ""}.String())
+	assert.Equal(t, `foo="bar baz"`, log.Field{Key: "foo", Value: "bar baz"}.String())
+	assert.Equal(t, `foo="a=b"`, log.Field{Key: "foo", Value: "a=b"}.String())
+	assert.Equal(t, `foo="\x1b"`, log.Field{Key: "foo", Value: "\033"}.String())
+}
+
+func TestWithFields(t *testing.T) {
+	var b bytes.Buffer
+
+	l1 := log.New(&b)
+	l2 := l1.WithFields(log.Field{Key: "a", Value: 1}, log.Field{Key: "b", Value: 2})
+	l3 := l2.WithField("a", 3)
+
+	l2.Fields()[0].Value = "xyz" // shouldn't affect logger fields
+
+	assert.Equal(t, []log.Field{}, l1.Fields())
+	assert.Equal(t, []log.Field{{Key: "a", Value: 1}, {Key: "b", Value: 2}}, l2.Fields())
+	assert.Equal(t, []log.Field{{Key: "b", Value: 2}, {Key: "a", Value: 3}}, l3.Fields())
+}
+
+func TestPrintWithFields(t *testing.T) {
+	var b bytes.Buffer
+	log.New(&b).WithField("a", 1).WithField("b", "x y").Print("foo")
+	assert.Equal(t, "\033_klio_log_level \"info\"\033\\\033_klio_tags []\033\\foo a=1 b=\"x y\"\033_klio_reset\033\\\n", b.String())
+}
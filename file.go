@@ -0,0 +1,331 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rotation defines how often FileWriter starts a new file.
+type Rotation string
+
+const (
+	// NoRotation makes FileWriter append to the same file forever.
+	NoRotation Rotation = ""
+	// RotateHourly makes FileWriter start a new file every hour.
+	RotateHourly Rotation = "hourly"
+	// RotateDaily makes FileWriter start a new file every day, at midnight.
+	RotateDaily Rotation = "daily"
+)
+
+// rotationLayouts are layouts of suffixes added to names of rotated files.
+var rotationLayouts = map[Rotation]string{
+	RotateHourly: "2006-01-02T15",
+	RotateDaily:  "2006-01-02",
+}
+
+// FileOption configures FileWriter, see OpenFile.
+type FileOption func(*FileWriter)
+
+// WithFileRotation makes FileWriter rotate the file hourly or daily. Rotated files are renamed by adding the period
+// they cover to their names, e.g. "deploy.log.2021-03-04".
+func WithFileRotation(r Rotation) FileOption {
+	return func(f *FileWriter) {
+		f.rotation = r
+	}
+}
+
+// WithFileCompression makes FileWriter compress rotated files using gzip.
+func WithFileCompression() FileOption {
+	return func(f *FileWriter) {
+		f.compress = true
+	}
+}
+
+// WithFileRetention makes FileWriter remove rotated files covering periods older than maxAge and all but maxCount
+// newest rotated files. Zero disables the limit.
+func WithFileRetention(maxAge time.Duration, maxCount int) FileOption {
+	return func(f *FileWriter) {
+		f.maxAge, f.maxCount = maxAge, maxCount
+	}
+}
+
+// WithFileLocking makes FileWriter hold an advisory lock of the file while writing, so several processes can share
+// it: lines are never interleaved and only one process rotates the file, while others reopen it. Locking is not
+// supported on all platforms (e.g. Plan 9), there each line is still written using a single write to the file opened in
+// append mode.
+func WithFileLocking() FileOption {
+	return func(f *FileWriter) {
+		f.locking = true
+	}
+}
+
+// WithFileClock sets clock used to decide when files are rotated.
+func WithFileClock(c Clock) FileOption {
+	return func(f *FileWriter) {
+		f.clock = c
+	}
+}
+
+// FileWriter appends lines to a file, optionally rotating it (see WithFileRotation). It can be used as an output of
+// loggers:
+//
+//	f, err := log.OpenFile("/var/log/klio/deploy.log", log.WithFileRotation(log.RotateDaily), log.WithFileCompression())
+//	if err != nil {
+//		return err
+//	}
+//	defer f.Close()
+//	l := log.New(f)
+type FileWriter struct {
+	mu       sync.Mutex
+	path     string
+	file     *os.File
+	period   time.Time
+	rotation Rotation
+	compress bool
+	locking  bool
+	maxAge   time.Duration
+	maxCount int
+	clock    Clock
+	wg       sync.WaitGroup
+}
+
+// OpenFile opens the file for appending, creating it if needed. It should be closed when no longer needed.
+func OpenFile(path string, opts ...FileOption) (*FileWriter, error) {
+	f := &FileWriter{path: path, clock: systemClock{}}
+	for _, opt := range opts {
+		opt(f)
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	if info, err := f.file.Stat(); err == nil && info.Size() > 0 {
+		f.period = f.periodOf(info.ModTime().In(f.clock.Now().Location()))
+	} else {
+		f.period = f.periodOf(f.clock.Now())
+	}
+	return f, nil
+}
+
+// Write appends data to the file, rotating it first if its period has passed.
+func (f *FileWriter) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if f.locking {
+		if err := f.lock(); err != nil {
+			return 0, err
+		}
+		defer func() { unlockFile(f.file) }()
+	}
+	if f.rotation != NoRotation {
+		if period := f.periodOf(f.clock.Now()); period.After(f.period) {
+			if err := f.rotate(); err != nil {
+				return 0, err
+			}
+			f.period = period
+		}
+	}
+	return f.file.Write(p)
+}
+
+// Sync commits written data to stable storage.
+func (f *FileWriter) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	return f.file.Sync()
+}
+
+// Name returns path of the file.
+func (f *FileWriter) Name() string {
+	return f.path
+}
+
+// Close closes the file and waits until rotated files are compressed.
+func (f *FileWriter) Close() error {
+	f.mu.Lock()
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.mu.Unlock()
+	f.wg.Wait()
+	return err
+}
+
+// lock acquires lock of the file. If another process has rotated the file in the meantime, it is reopened first.
+func (f *FileWriter) lock() error {
+	for {
+		if err := lockFile(f.file); err != nil {
+			return err
+		}
+		current, err := os.Stat(f.path)
+		if err == nil {
+			if opened, err := f.file.Stat(); err == nil && os.SameFile(current, opened) {
+				return nil
+			}
+		}
+		unlockFile(f.file)
+		f.file.Close()
+		f.file = nil
+		if err := f.open(); err != nil {
+			return err
+		}
+		f.period = f.periodOf(f.clock.Now())
+	}
+}
+
+// reopen opens the file again after it was closed or renamed, locking it if needed.
+func (f *FileWriter) reopen() error {
+	f.file = nil
+	if err := f.open(); err != nil {
+		return err
+	}
+	if f.locking {
+		return lockFile(f.file)
+	}
+	return nil
+}
+
+func (f *FileWriter) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	f.file = file
+	return nil
+}
+
+// periodOf returns start of the rotation period containing t.
+func (f *FileWriter) periodOf(t time.Time) time.Time {
+	switch f.rotation {
+	case RotateHourly:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	case RotateDaily:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	default:
+		return time.Time{}
+	}
+}
+
+// rotate renames the current file and opens a new one. Rotated file is compressed and old files are removed in the
+// background. The file is renamed while it is still locked, so other processes waiting for the lock notice the rename
+// and reopen the file instead of writing to the rotated one.
+func (f *FileWriter) rotate() error {
+	rotated := f.path + "." + f.period.Format(rotationLayouts[f.rotation])
+	for i := 1; fileExists(rotated) || fileExists(rotated+".gz"); i++ {
+		rotated = f.path + "." + f.period.Format(rotationLayouts[f.rotation]) + "." + strconv.Itoa(i)
+	}
+
+	old := f.file
+	if !renameOpenFiles {
+		f.file = nil
+		if err := old.Close(); err != nil {
+			return err
+		}
+		old = nil
+	}
+	if err := os.Rename(f.path, rotated); err != nil {
+		if old != nil {
+			// Keep appending to the current file rather than losing lines
+			return nil
+		}
+		return f.reopen()
+	}
+	err := f.reopen()
+	if old != nil {
+		old.Close()
+	}
+	if err != nil {
+		return err
+	}
+
+	now := f.clock.Now()
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		if f.compress {
+			compressFile(rotated)
+		}
+		f.removeOld(now)
+	}()
+	return nil
+}
+
+// removeOld removes rotated files exceeding the retention limits.
+func (f *FileWriter) removeOld(now time.Time) {
+	if f.maxAge <= 0 && f.maxCount <= 0 {
+		return
+	}
+	matches, err := filepath.Glob(f.path + ".*")
+	if err != nil {
+		return
+	}
+
+	layout := rotationLayouts[f.rotation]
+	type rotatedFile struct {
+		path   string
+		period time.Time
+	}
+	var files []rotatedFile
+	for _, m := range matches {
+		suffix := strings.TrimSuffix(strings.TrimPrefix(m, f.path+"."), ".gz")
+		if i := strings.IndexByte(suffix, '.'); i >= 0 {
+			suffix = suffix[:i]
+		}
+		if period, err := time.ParseInLocation(layout, suffix, now.Location()); err == nil {
+			files = append(files, rotatedFile{m, period})
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool { return files[i].period.After(files[j].period) })
+
+	for i, rf := range files {
+		if (f.maxCount > 0 && i >= f.maxCount) || (f.maxAge > 0 && now.Sub(rf.period) > f.maxAge) {
+			os.Remove(rf.path)
+		}
+	}
+}
+
+// compressFile replaces the file with its gzip-compressed copy.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	dst, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		src.Close()
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	_, err = io.Copy(zw, src)
+	src.Close()
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+	return os.Remove(path)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
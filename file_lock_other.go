@@ -0,0 +1,18 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly && !windows
+
+package logger
+
+import "os"
+
+// renameOpenFiles reports whether files can be renamed while they are open (and locked).
+const renameOpenFiles = true
+
+// lockFile does nothing, files cannot be locked on this platform.
+func lockFile(f *os.File) error {
+	return nil
+}
+
+// unlockFile does nothing, files cannot be locked on this platform.
+func unlockFile(f *os.File) error {
+	return nil
+}
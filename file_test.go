@@ -0,0 +1,200 @@
+package logger_test
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func listDir(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readGzip(t *testing.T, path string) string {
+	f, err := os.Open(path)
+	if !assert.NoError(t, err) {
+		return ""
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if !assert.NoError(t, err) {
+		return ""
+	}
+	data, _ := io.ReadAll(zr)
+	return string(data)
+}
+
+func TestFileWriter(t *testing.T) {
+	t.Run("append to the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "out.log")
+		assert.NoError(t, os.WriteFile(path, []byte("foo\n"), 0o644))
+
+		f, err := log.OpenFile(path)
+		assert.NoError(t, err)
+		log.New(f).WithOutputMode(log.PlainMode).Print("bar")
+		assert.NoError(t, f.Close())
+
+		data, _ := os.ReadFile(path)
+		assert.Equal(t, "foo\n[INFO] bar\n", string(data))
+		_, err = f.Write([]byte("baz\n"))
+		assert.Equal(t, os.ErrClosed, err)
+	})
+
+	t.Run("rotate daily", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "out.log")
+		clock := &fakeClock{now: time.Date(2021, 3, 4, 23, 59, 0, 0, time.UTC)}
+
+		f, err := log.OpenFile(path, log.WithFileRotation(log.RotateDaily), log.WithFileClock(clock))
+		assert.NoError(t, err)
+		f.Write([]byte("foo\n"))
+		clock.Add(time.Minute)
+		f.Write([]byte("bar\n"))
+		clock.Add(23 * time.Hour)
+		f.Write([]byte("baz\n"))
+		clock.Add(time.Hour)
+		f.Write([]byte("qux\n"))
+		assert.NoError(t, f.Close())
+
+		assert.Equal(t, []string{"out.log", "out.log.2021-03-04", "out.log.2021-03-05"}, listDir(t, dir))
+		data, _ := os.ReadFile(path + ".2021-03-05")
+		assert.Equal(t, "bar\nbaz\n", string(data))
+		data, _ = os.ReadFile(path)
+		assert.Equal(t, "qux\n", string(data))
+	})
+
+	t.Run("compress rotated files and apply retention", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "out.log")
+		clock := &fakeClock{now: time.Date(2021, 3, 4, 5, 0, 0, 0, time.UTC)}
+
+		f, err := log.OpenFile(path, log.WithFileRotation(log.RotateHourly), log.WithFileCompression(),
+			log.WithFileRetention(3*time.Hour, 2), log.WithFileClock(clock))
+		assert.NoError(t, err)
+		for i := 0; i < 5; i++ {
+			f.Write([]byte(clock.now.Format(time.Kitchen) + "\n"))
+			clock.Add(time.Hour)
+		}
+		assert.NoError(t, f.Close())
+
+		assert.Equal(t, []string{"out.log", "out.log.2021-03-04T07.gz", "out.log.2021-03-04T08.gz"}, listDir(t, dir))
+		assert.Equal(t, "8:00AM\n", readGzip(t, path+".2021-03-04T08.gz"))
+	})
+
+	t.Run("rotate file left by previous run", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "out.log")
+		assert.NoError(t, os.WriteFile(path, []byte("foo\n"), 0o644))
+		modTime := time.Date(2021, 3, 3, 12, 0, 0, 0, time.UTC)
+		assert.NoError(t, os.Chtimes(path, modTime, modTime))
+		assert.NoError(t, os.WriteFile(path+".2021-03-03", []byte("bar\n"), 0o644))
+
+		clock := &fakeClock{now: time.Date(2021, 3, 4, 5, 0, 0, 0, time.UTC)}
+		f, err := log.OpenFile(path, log.WithFileRotation(log.RotateDaily), log.WithFileClock(clock))
+		assert.NoError(t, err)
+		f.Write([]byte("baz\n"))
+		assert.NoError(t, f.Close())
+
+		assert.Equal(t, []string{"out.log", "out.log.2021-03-03", "out.log.2021-03-03.1"}, listDir(t, dir))
+		data, _ := os.ReadFile(path + ".2021-03-03.1")
+		assert.Equal(t, "foo\n", string(data))
+	})
+
+	t.Run("share the file between processes", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "out.log")
+		clock := &fakeClock{now: time.Date(2021, 3, 4, 12, 0, 0, 0, time.UTC)}
+		opts := []log.FileOption{log.WithFileRotation(log.RotateDaily), log.WithFileLocking(), log.WithFileClock(clock)}
+
+		first, err := log.OpenFile(path, opts...)
+		assert.NoError(t, err)
+		second, err := log.OpenFile(path, opts...)
+		assert.NoError(t, err)
+
+		first.Write([]byte("foo\n"))
+		second.Write([]byte("bar\n"))
+		clock.Add(24 * time.Hour)
+		first.Write([]byte("baz\n"))
+		second.Write([]byte("qux\n"))
+		assert.NoError(t, first.Close())
+		assert.NoError(t, second.Close())
+
+		assert.Equal(t, []string{"out.log", "out.log.2021-03-04"}, listDir(t, dir))
+		data, _ := os.ReadFile(path + ".2021-03-04")
+		assert.Equal(t, "foo\nbar\n", string(data))
+		data, _ = os.ReadFile(path)
+		assert.Equal(t, "baz\nqux\n", string(data))
+	})
+
+	t.Run("don't write to rotated file while rotating concurrently", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "out.log")
+		assert.NoError(t, os.WriteFile(path, []byte("old\n"), 0o644))
+		yesterday := time.Date(2021, 3, 4, 12, 0, 0, 0, time.UTC)
+		assert.NoError(t, os.Chtimes(path, yesterday, yesterday))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			clock := &fakeClock{now: yesterday.Add(24 * time.Hour)}
+			f, err := log.OpenFile(path, log.WithFileRotation(log.RotateDaily), log.WithFileLocking(), log.WithFileCompression(), log.WithFileClock(clock))
+			assert.NoError(t, err)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer f.Close()
+				for j := 0; j < 50; j++ {
+					f.Write([]byte("new\n"))
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, []string{"out.log", "out.log.2021-03-04.gz"}, listDir(t, dir))
+		assert.Equal(t, "old\n", readGzip(t, path+".2021-03-04.gz"))
+		data, _ := os.ReadFile(path)
+		assert.Equal(t, strings.Repeat("new\n", 400), string(data))
+	})
+
+	t.Run("don't interleave lines", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "out.log")
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			f, err := log.OpenFile(path, log.WithFileLocking())
+			assert.NoError(t, err)
+			line := []byte(strings.Repeat(string(rune('a'+i)), 64<<10) + "\n")
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer f.Close()
+				for j := 0; j < 20; j++ {
+					f.Write(line)
+				}
+			}()
+		}
+		wg.Wait()
+
+		data, _ := os.ReadFile(path)
+		lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		assert.Len(t, lines, 80)
+		for _, l := range lines {
+			assert.Equal(t, strings.Repeat(l[:1], 64<<10), l)
+		}
+	})
+}
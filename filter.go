@@ -0,0 +1,19 @@
+package logger
+
+// WithFilter creates new logger instance writing only lines for which filter returns true. Filters are combined with
+// filters of the logger, so a line is written only if all of them accept it.
+func (l *Logger) WithFilter(filter func(Record) bool) *Logger {
+	n := l.clone()
+	n.filters = append(n.filters[:len(n.filters):len(n.filters)], filter)
+	return n
+}
+
+// accepts reports whether all filters accept the record.
+func accepts(filters []func(Record) bool, r Record) bool {
+	for _, f := range filters {
+		if !f(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestWithFilter(t *testing.T) {
+	var b bytes.Buffer
+	l := log.New(&b).WithOutputMode(log.PlainMode).
+		WithFilter(func(r log.Record) bool {
+			return !strings.HasPrefix(r.Message, "noise")
+		}).
+		WithFilter(func(r log.Record) bool {
+			for _, tag := range r.Tags {
+				if tag == "helm" {
+					return r.Level.Enabled(log.WarnLevel)
+				}
+			}
+			return true
+		})
+
+	l.Print("foo")
+	l.Print("noise bar")
+	l.WithTags("helm").Print("helm info")
+	l.WithTags("helm").WithLevel(log.ErrorLevel).Print("helm error")
+
+	unfiltered := log.New(&b).WithOutputMode(log.PlainMode)
+	unfiltered.Print("noise baz")
+
+	assert.Equal(t, ""+
+		"[INFO] foo\n"+
+		"[ERROR][HELM] helm error\n"+
+		"[INFO] noise baz\n", b.String())
+}
+
+func TestWithFilterFields(t *testing.T) {
+	var b bytes.Buffer
+	l := log.New(&b).WithOutputMode(log.PlainMode).WithSequence().WithFilter(func(r log.Record) bool {
+		for _, f := range r.Fields {
+			if f.Key == "user" && f.Value == "bot" {
+				return false
+			}
+		}
+		return true
+	})
+
+	l.WithField("user", "bot").Print("foo")
+	l.WithField("user", "alice").Print("bar")
+
+	assert.Equal(t, "[INFO] bar user=alice seq=1\n", b.String())
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Special fields of structured logs recognized by Cloud Logging agents, see
+// https://cloud.google.com/logging/docs/structured-logging.
+const (
+	gcpSeverityKey = "severity"
+	gcpMessageKey  = "message"
+	gcpTimeKey     = "time"
+	gcpLabelsKey   = "logging.googleapis.com/labels"
+)
+
+var gcpSeverities = map[Level]string{
+	FatalLevel:   "CRITICAL",
+	ErrorLevel:   "ERROR",
+	WarnLevel:    "WARNING",
+	InfoLevel:    "INFO",
+	VerboseLevel: "DEBUG",
+	DebugLevel:   "DEBUG",
+	SpamLevel:    "DEBUG",
+}
+
+// GCPSeverity returns Cloud Logging severity corresponding to the level, "DEFAULT" for unknown levels.
+func GCPSeverity(level Level) string {
+	if s, ok := gcpSeverities[level]; ok {
+		return s
+	}
+	return "DEFAULT"
+}
+
+// GCPSink writes records as structured logs understood by Cloud Logging agents running in Cloud Build, GKE, Cloud
+// Run and similar environments, usually to the stderr, e.g.:
+//
+//	l = l.WithSink(log.NewGCPSink(os.Stderr, map[string]string{"build_id": os.Getenv("BUILD_ID")}))
+//
+// Each record is written as a single JSON line, which becomes jsonPayload of a log entry. Its level is mapped to
+// severity (see GCPSeverity) and its fields are added to the payload, together with "tags" and "run_id". Fields
+// named like special fields (e.g. "severity") are prefixed with "field_".
+type GCPSink struct {
+	mu     sync.Mutex
+	w      io.Writer
+	labels map[string]string
+}
+
+// NewGCPSink creates new GCPSink writing to w. Labels are added to each log entry.
+func NewGCPSink(w io.Writer, labels map[string]string) *GCPSink {
+	return &GCPSink{w: w, labels: labels}
+}
+
+// WriteRecord writes the record, see Sink.
+func (s *GCPSink) WriteRecord(t time.Time, r Record) error {
+	data, err := safeMarshal(s.payload(t, r, false))
+	if err != nil {
+		// Values which can't be marshaled are written as strings
+		if data, err = json.Marshal(s.payload(t, r, true)); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(data, '\n'))
+	return err
+}
+
+func (s *GCPSink) payload(t time.Time, r Record, stringify bool) map[string]interface{} {
+	tags := r.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	payload := map[string]interface{}{
+		gcpSeverityKey: GCPSeverity(r.Level),
+		gcpMessageKey:  r.Message,
+		gcpTimeKey:     t.UTC().Format(time.RFC3339Nano),
+		"tags":         tags,
+		"run_id":       runID,
+	}
+	if len(s.labels) > 0 {
+		payload[gcpLabelsKey] = s.labels
+	}
+	for _, f := range r.Fields {
+		key, value := f.Key, f.Value
+		if _, ok := payload[key]; ok {
+			key = "field_" + key
+		}
+		if stringify {
+			value = safeSprint(value)
+		}
+		payload[key] = value
+	}
+	return payload
+}
@@ -0,0 +1,48 @@
+package logger_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestGCPSeverity(t *testing.T) {
+	assert.Equal(t, "CRITICAL", log.GCPSeverity(log.FatalLevel))
+	assert.Equal(t, "ERROR", log.GCPSeverity(log.ErrorLevel))
+	assert.Equal(t, "WARNING", log.GCPSeverity(log.WarnLevel))
+	assert.Equal(t, "INFO", log.GCPSeverity(log.InfoLevel))
+	assert.Equal(t, "DEBUG", log.GCPSeverity(log.VerboseLevel))
+	assert.Equal(t, "DEBUG", log.GCPSeverity(log.SpamLevel))
+	assert.Equal(t, "DEFAULT", log.GCPSeverity("custom"))
+}
+
+func TestGCPSink(t *testing.T) {
+	var b bytes.Buffer
+	clock := &fakeClock{now: time.Date(2021, 3, 4, 5, 6, 7, 890000000, time.FixedZone("CET", 3600))}
+	sink := log.NewGCPSink(&b, map[string]string{"build_id": "1234"})
+	l := log.New(io.Discard).WithClock(clock).WithSink(sink)
+
+	l.WithTags("deploy").WithFields(log.Field{Key: "release", Value: "api"}, log.Field{Key: "severity", Value: 3}).
+		WithLevel(log.WarnLevel).Print("foo")
+	l.WithField("ch", make(chan int)).Print("bar")
+
+	lines := bytes.Split(bytes.TrimSpace(b.Bytes()), []byte("\n"))
+	if assert.Len(t, lines, 2) {
+		assert.JSONEq(t, `{
+			"severity": "WARNING",
+			"message": "foo",
+			"time": "2021-03-04T04:06:07.89Z",
+			"logging.googleapis.com/labels": {"build_id": "1234"},
+			"tags": ["deploy"],
+			"run_id": "`+log.RunID()+`",
+			"release": "api",
+			"field_severity": 3
+		}`, string(lines[0]))
+		assert.Contains(t, string(lines[1]), `"ch":"0x`)
+	}
+}
@@ -0,0 +1,32 @@
+package logger
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// GoroutineField is the key of the field holding IDs of goroutines writing lines, see Logger.WithGoroutineID.
+const GoroutineField = "goroutine"
+
+// WithGoroutineID creates new logger instance stamping each written line with the ID of the goroutine writing it in
+// the "goroutine" field, which helps to untangle interleaved output of concurrent steps. IDs match ones in stack
+// traces of panics.
+func (l *Logger) WithGoroutineID() *Logger {
+	n := l.clone()
+	n.goroutineID = true
+	return n
+}
+
+// currentGoroutineID returns ID of the calling goroutine, parsed from the header of its stack trace (e.g.
+// "goroutine 18 [running]:"). It returns 0 if the header can't be parsed.
+func currentGoroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
@@ -0,0 +1,35 @@
+package logger_test
+
+import (
+	"bytes"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestWithGoroutineID(t *testing.T) {
+	var b bytes.Buffer
+	l := log.New(&b).WithOutputMode(log.PlainMode).WithGoroutineID()
+
+	l.Print("foo")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		l.Print("bar")
+	}()
+	<-done
+
+	stack := make([]byte, 64)
+	id := strings.Fields(string(stack[:runtime.Stack(stack, false)]))[1]
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if assert.Len(t, lines, 2) {
+		assert.Equal(t, "[INFO] foo goroutine="+id, lines[0])
+		assert.Regexp(t, regexp.MustCompile(`^\[INFO\] bar goroutine=\d+$`), lines[1])
+		assert.NotEqual(t, lines[0][len("[INFO] foo"):], lines[1][len("[INFO] bar"):])
+	}
+}
@@ -0,0 +1,198 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// GRPCCloseTimeout limits time GRPCSink.Close waits for queued records to be sent.
+const GRPCCloseTimeout = 5 * time.Second
+
+// GRPCRecord is a record sent by GRPCSink, matching Record message defined in proto/record.proto.
+type GRPCRecord struct {
+	Time    time.Time
+	Level   string
+	Tags    []string
+	Message string
+	Fields  map[string]string
+	RunID   string
+}
+
+// GRPCStream is a client stream of Collector.Stream method defined in proto/record.proto. It is usually a thin
+// adapter over a client generated from the schema, which keeps gRPC out of dependencies of this module.
+type GRPCStream interface {
+	// Send sends the record.
+	Send(r *GRPCRecord) error
+	// CloseAndRecv closes the stream and waits for the response of the collector, returning the status of the stream.
+	CloseAndRecv() error
+}
+
+// GRPCOption configures GRPCSink, see NewGRPCSink.
+type GRPCOption func(*GRPCSink)
+
+// WithGRPCBackoff sets delays between attempts to open a stream after failures. The delay starts at min and doubles
+// after each failure, up to max. By default it starts at 100ms and grows up to 30s.
+func WithGRPCBackoff(min, max time.Duration) GRPCOption {
+	return func(s *GRPCSink) {
+		s.minBackoff, s.maxBackoff = min, max
+	}
+}
+
+// GRPCSink streams records to a collector service over gRPC, e.g.:
+//
+//	sink := log.NewGRPCSink(func(ctx context.Context) (log.GRPCStream, error) {
+//		stream, err := logpb.NewCollectorClient(conn).Stream(ctx)
+//		return streamAdapter{stream}, err
+//	}, 1000)
+//	defer sink.Close()
+//	l = l.WithSink(sink)
+//
+// Records are queued and sent in the background. When the stream fails, it is reopened with exponential backoff and
+// the record which failed is sent again. Records which don't fit in the queue are dropped. Close returns the status of
+// the stream reported by the collector.
+type GRPCSink struct {
+	mu         sync.Mutex
+	open       func(ctx context.Context) (GRPCStream, error)
+	queue      chan *GRPCRecord
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	dropped    int64
+	err        error
+	closed     bool
+	ctx        context.Context
+	cancel     func()
+	done       chan struct{}
+}
+
+// NewGRPCSink creates new GRPCSink sending records to streams opened using open. At most queueSize records wait to
+// be sent. The sink should be closed when no longer needed.
+func NewGRPCSink(open func(ctx context.Context) (GRPCStream, error), queueSize int, opts ...GRPCOption) *GRPCSink {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &GRPCSink{
+		open:       open,
+		queue:      make(chan *GRPCRecord, queueSize),
+		minBackoff: 100 * time.Millisecond,
+		maxBackoff: 30 * time.Second,
+		ctx:        ctx,
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	go s.run()
+	return s
+}
+
+// WriteRecord queues the record, see Sink.
+func (s *GRPCSink) WriteRecord(t time.Time, r Record) error {
+	fields := make(map[string]string, len(r.Fields))
+	for _, f := range r.Fields {
+		fields[f.Key] = safeSprint(f.Value)
+	}
+	rec := &GRPCRecord{Time: t, Level: string(r.Level), Tags: r.Tags, Message: r.Message, Fields: fields, RunID: runID}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return io.ErrClosedPipe
+	}
+	select {
+	case s.queue <- rec:
+	default:
+		s.dropped++
+	}
+	return nil
+}
+
+// Dropped returns the number of records which were dropped, because the queue was full or they couldn't be sent
+// before the sink was closed.
+func (s *GRPCSink) Dropped() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// Close sends queued records, waiting at most GRPCCloseTimeout, and closes the stream. It returns the error reported by
+// the collector when the stream was closed.
+func (s *GRPCSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.queue)
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+	case <-time.After(GRPCCloseTimeout):
+		s.cancel()
+		<-s.done
+	}
+	s.cancel()
+	return s.err
+}
+
+func (s *GRPCSink) run() {
+	defer close(s.done)
+
+	var stream GRPCStream
+	backoff := time.Duration(0)
+	for r := range s.queue {
+		for {
+			var err error
+			if stream == nil {
+				stream, err = s.open(s.ctx)
+			}
+			if err == nil {
+				if err = stream.Send(r); err != nil {
+					// Send reports only io.EOF when the stream fails, the actual error is returned by CloseAndRecv
+					if e := stream.CloseAndRecv(); e != nil {
+						err = e
+					}
+				}
+			}
+			if err == nil {
+				backoff = 0
+				break
+			}
+
+			stream = nil
+			if backoff = backoff * 2; backoff == 0 {
+				backoff = s.minBackoff
+			}
+			if backoff > s.maxBackoff {
+				backoff = s.maxBackoff
+			}
+			if !s.sleep(backoff) {
+				s.drop(1 + len(s.queue))
+				return
+			}
+		}
+	}
+	if stream != nil {
+		s.err = stream.CloseAndRecv()
+	}
+}
+
+// sleep waits for d, it returns false if the sink was closed in the meantime.
+func (s *GRPCSink) sleep(d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
+func (s *GRPCSink) drop(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dropped += int64(n)
+}
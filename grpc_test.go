@@ -0,0 +1,128 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+type fakeCollector struct {
+	mu       sync.Mutex
+	opens    int
+	failOpen int
+	failSend int
+	received []*log.GRPCRecord
+	closed   int
+	closeErr error
+	block    chan struct{}
+}
+
+func (c *fakeCollector) open(ctx context.Context) (log.GRPCStream, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.opens++
+	if c.failOpen > 0 {
+		c.failOpen--
+		return nil, errors.New("unavailable")
+	}
+	return fakeStream{c}, nil
+}
+
+func (c *fakeCollector) Received() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var r []string
+	for _, rec := range c.received {
+		r = append(r, rec.Message)
+	}
+	return r
+}
+
+type fakeStream struct{ c *fakeCollector }
+
+func (s fakeStream) Send(r *log.GRPCRecord) error {
+	if s.c.block != nil {
+		<-s.c.block
+	}
+	s.c.mu.Lock()
+	defer s.c.mu.Unlock()
+	if s.c.failSend > 0 {
+		s.c.failSend--
+		return io.EOF
+	}
+	s.c.received = append(s.c.received, r)
+	return nil
+}
+
+func (s fakeStream) CloseAndRecv() error {
+	s.c.mu.Lock()
+	defer s.c.mu.Unlock()
+	s.c.closed++
+	return s.c.closeErr
+}
+
+func TestGRPCSink(t *testing.T) {
+	start := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	t.Run("stream records", func(t *testing.T) {
+		c := &fakeCollector{}
+		sink := log.NewGRPCSink(c.open, 10)
+
+		l := log.New(io.Discard).WithClock(&fakeClock{now: start}).WithSink(sink)
+		l.WithTags("deploy").WithField("attempt", 2).Print("foo")
+		l.Print("bar")
+		assert.NoError(t, sink.Close())
+
+		assert.Equal(t, []*log.GRPCRecord{
+			{Time: start, Level: "info", Tags: []string{"deploy"}, Message: "foo", Fields: map[string]string{"attempt": "2"}, RunID: log.RunID()},
+			{Time: start, Level: "info", Tags: []string{}, Message: "bar", Fields: map[string]string{}, RunID: log.RunID()},
+		}, c.received)
+		assert.Equal(t, 1, c.opens)
+		assert.Equal(t, 1, c.closed)
+		assert.Equal(t, io.ErrClosedPipe, sink.WriteRecord(start, log.Record{}))
+	})
+
+	t.Run("reopen failed streams with backoff", func(t *testing.T) {
+		c := &fakeCollector{failOpen: 2, failSend: 1}
+		sink := log.NewGRPCSink(c.open, 10, log.WithGRPCBackoff(time.Millisecond, 2*time.Millisecond))
+
+		for _, msg := range []string{"foo", "bar", "baz"} {
+			assert.NoError(t, sink.WriteRecord(start, log.Record{Level: log.InfoLevel, Message: msg}))
+		}
+		assert.NoError(t, sink.Close())
+
+		assert.Equal(t, []string{"foo", "bar", "baz"}, c.Received())
+		assert.Equal(t, 4, c.opens)
+		assert.Equal(t, int64(0), sink.Dropped())
+	})
+
+	t.Run("return status of the stream", func(t *testing.T) {
+		c := &fakeCollector{closeErr: errors.New("permission denied")}
+		sink := log.NewGRPCSink(c.open, 10)
+
+		assert.NoError(t, sink.WriteRecord(start, log.Record{Level: log.InfoLevel, Message: "foo"}))
+		assert.EqualError(t, sink.Close(), "permission denied")
+		assert.Equal(t, 1, c.closed)
+	})
+
+	t.Run("drop records exceeding the queue", func(t *testing.T) {
+		c := &fakeCollector{block: make(chan struct{})}
+		sink := log.NewGRPCSink(c.open, 1)
+
+		for i := 0; i < 5; i++ {
+			sink.WriteRecord(start, log.Record{Level: log.InfoLevel, Message: "foo"})
+		}
+		close(c.block)
+		assert.NoError(t, sink.Close())
+
+		assert.GreaterOrEqual(t, sink.Dropped(), int64(3))
+		assert.Equal(t, int64(5), sink.Dropped()+int64(len(c.Received())))
+	})
+}
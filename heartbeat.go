@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"context"
+	"time"
+)
+
+// Heartbeat writes "Still working on <label> (elapsed 3m20s)" every interval, until the context is cancelled or the
+// returned function is called, so users don't assume a long silent operation hung:
+//
+//	defer l.Heartbeat(ctx, 30*time.Second, "uploading artifacts")()
+//
+// Nothing is written after the returned function returns.
+func (l *Logger) Heartbeat(ctx context.Context, interval time.Duration, label string) func() {
+	start := l.Clock().Now()
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				l.Printf("Still working on %s (elapsed %s)", label, formatDuration(l.since(start)))
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
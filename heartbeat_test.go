@@ -0,0 +1,39 @@
+package logger_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestHeartbeat(t *testing.T) {
+	t.Run("write heartbeats until stopped", func(t *testing.T) {
+		var b syncBuffer
+		stop := log.New(&b).WithOutputMode(log.PlainMode).Heartbeat(context.Background(), 10*time.Millisecond, "upload")
+
+		assert.Eventually(t, func() bool { return b.String() != "" }, time.Second, 5*time.Millisecond)
+		stop()
+		written := b.String()
+		time.Sleep(30 * time.Millisecond)
+
+		assert.Equal(t, written, b.String())
+		assert.Regexp(t, regexp.MustCompile(`^(\[INFO\] Still working on upload \(elapsed \d+ms\)\n)+$`), written)
+	})
+
+	t.Run("stop when context is cancelled", func(t *testing.T) {
+		var b syncBuffer
+		ctx, cancel := context.WithCancel(context.Background())
+		stop := log.New(&b).WithOutputMode(log.PlainMode).Heartbeat(ctx, 10*time.Millisecond, "upload")
+
+		cancel()
+		stop()
+		time.Sleep(30 * time.Millisecond)
+
+		assert.Empty(t, b.String())
+	})
+}
@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Hexdump writes data in the canonical hex+ASCII format (like "hexdump -C") in a single, multi-line record prefixed by
+// label. If limit is positive, only the first limit bytes are dumped.
+func (l *Logger) Hexdump(level Level, label string, data []byte, limit int) *Logger {
+	l = l.WithLevel(level)
+
+	header := fmt.Sprintf("%s (%d bytes):", label, len(data))
+	if len(data) == 0 {
+		return l.Print(header)
+	}
+
+	truncated := 0
+	if limit > 0 && len(data) > limit {
+		truncated = len(data) - limit
+		data = data[:limit]
+	}
+
+	msg := header + "\n" + strings.TrimSuffix(hex.Dump(data), "\n")
+	if truncated > 0 {
+		msg += fmt.Sprintf("\n... %d more bytes", truncated)
+	}
+	return l.Print(msg)
+}
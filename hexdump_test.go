@@ -0,0 +1,50 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestHexdump(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		limit    int
+		expected string
+	}{
+		{
+			name:  "dump data",
+			data:  []byte("Hello, world!\n\x1b_klio"),
+			limit: 0,
+			expected: "[DEBUG] Payload (20 bytes):\n" +
+				"00000000  48 65 6c 6c 6f 2c 20 77  6f 72 6c 64 21 0a 1b 5f  |Hello, world!.._|\n" +
+				"00000010  6b 6c 69 6f                                       |klio|\n",
+		},
+		{
+			name:  "truncate data",
+			data:  []byte("Hello, world!"),
+			limit: 4,
+			expected: "[DEBUG] Payload (13 bytes):\n" +
+				"00000000  48 65 6c 6c                                       |Hell|\n" +
+				"... 9 more bytes\n",
+		},
+		{
+			name:     "empty data",
+			data:     nil,
+			limit:    4,
+			expected: "[DEBUG] Payload (0 bytes):\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var b bytes.Buffer
+			log.New(&b).WithOutputMode(log.PlainMode).Hexdump(log.DebugLevel, "Payload", test.data, test.limit)
+			assert.Equal(t, test.expected, b.String())
+		})
+	}
+}
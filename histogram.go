@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"io"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// SizeBuckets are upper bounds (in bytes) of buckets of WriteHistograms.Sizes.
+var SizeBuckets = []float64{64, 128, 256, 512, 1024, 4096, 16384, 65536}
+
+// LatencyBuckets are upper bounds (in seconds) of buckets of WriteHistograms.Latency and WriteHistograms.Sinks.
+var LatencyBuckets = []float64{0.00001, 0.0001, 0.001, 0.01, 0.1, 1}
+
+// maxBuckets limits the number of buckets (including the overflow bucket) of histograms.
+const maxBuckets = 12
+
+// Histogram counts observations in buckets.
+type Histogram struct {
+	// Bounds are inclusive upper bounds of buckets. The last bucket (Counts has one more element than Bounds) counts
+	// observations greater than all bounds.
+	Bounds []float64 `json:"bounds"`
+	// Counts are numbers of observations in buckets.
+	Counts []int64 `json:"counts"`
+	// Count is the total number of observations.
+	Count int64 `json:"count"`
+	// Sum is the sum of observed values.
+	Sum float64 `json:"sum"`
+}
+
+// WriteHistograms describe performance of writes done by a logger, so regressions caused by logging (e.g. slow pipes
+// or network sinks) are visible. Like Stats, they are shared by a logger and all loggers derived from it.
+type WriteHistograms struct {
+	// Sizes of lines written to the output, in bytes.
+	Sizes Histogram `json:"sizes"`
+	// Latency of writes to the output, in seconds.
+	Latency Histogram `json:"latency"`
+	// Sinks holds latency of writes to sinks (see WithSink), in seconds, by sink type (e.g. "*logger.GRPCSink").
+	Sinks map[string]Histogram `json:"sinks"`
+}
+
+// WriteHistograms returns histograms of writes done by a logger.
+func (l *Logger) WriteHistograms() WriteHistograms {
+	l.mu.RLock()
+	s := l.stats
+	l.mu.RUnlock()
+
+	r := WriteHistograms{
+		Sizes:   s.sizes.snapshot(SizeBuckets),
+		Latency: s.latency.snapshot(LatencyBuckets),
+		Sinks:   map[string]Histogram{},
+	}
+	s.sinksMu.Lock()
+	defer s.sinksMu.Unlock()
+	for name, h := range s.sinkLatency {
+		r.Sinks[name] = h.snapshot(LatencyBuckets)
+	}
+	return r
+}
+
+// histogram is safe for concurrent use, its bounds are passed by callers.
+type histogram struct {
+	counts  [maxBuckets]int64
+	count   int64
+	sumBits uint64
+}
+
+func (h *histogram) observe(bounds []float64, v float64) {
+	i := 0
+	for i < len(bounds) && v > bounds[i] {
+		i++
+	}
+	atomic.AddInt64(&h.counts[i], 1)
+	atomic.AddInt64(&h.count, 1)
+	for {
+		old := atomic.LoadUint64(&h.sumBits)
+		if atomic.CompareAndSwapUint64(&h.sumBits, old, math.Float64bits(math.Float64frombits(old)+v)) {
+			return
+		}
+	}
+}
+
+func (h *histogram) snapshot(bounds []float64) Histogram {
+	r := Histogram{
+		Bounds: append([]float64(nil), bounds...),
+		Counts: make([]int64, len(bounds)+1),
+		Count:  atomic.LoadInt64(&h.count),
+		Sum:    math.Float64frombits(atomic.LoadUint64(&h.sumBits)),
+	}
+	for i := range r.Counts {
+		r.Counts[i] = atomic.LoadInt64(&h.counts[i])
+	}
+	return r
+}
+
+// write writes the line to the output, recording its size and latency of the write.
+func (s *stats) write(w io.Writer, p []byte) {
+	start := time.Now()
+	n, err := w.Write(p)
+	s.latency.observe(LatencyBuckets, time.Since(start).Seconds())
+	s.sizes.observe(SizeBuckets, float64(len(p)))
+	s.record(n, err)
+}
+
+// observeSink records latency of a write to the sink.
+func (s *stats) observeSink(sink Sink, d time.Duration) {
+	name := describeOutput(sink)
+	s.sinksMu.Lock()
+	h, ok := s.sinkLatency[name]
+	if !ok {
+		if s.sinkLatency == nil {
+			s.sinkLatency = map[string]*histogram{}
+		}
+		h = &histogram{}
+		s.sinkLatency[name] = h
+	}
+	s.sinksMu.Unlock()
+	h.observe(LatencyBuckets, d.Seconds())
+}
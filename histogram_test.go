@@ -0,0 +1,38 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+type slowSink struct{}
+
+func (slowSink) WriteRecord(t time.Time, r log.Record) error {
+	time.Sleep(2 * time.Millisecond)
+	return nil
+}
+
+func TestWriteHistograms(t *testing.T) {
+	var b bytes.Buffer
+	l := log.New(&b).WithOutputMode(log.PlainMode)
+
+	l.Print("foo")
+	l.WithSink(slowSink{}).Print(strings.Repeat("a", 100))
+	h := l.WriteHistograms()
+
+	assert.Equal(t, log.SizeBuckets, h.Sizes.Bounds)
+	assert.Equal(t, []int64{1, 1, 0, 0, 0, 0, 0, 0, 0}, h.Sizes.Counts)
+	assert.Equal(t, int64(2), h.Sizes.Count)
+	assert.Equal(t, float64(len("[INFO] foo\n")+len("[INFO] \n")+100), h.Sizes.Sum)
+	assert.Equal(t, int64(2), h.Latency.Count)
+	if sink, ok := h.Sinks["logger_test.slowSink"]; assert.True(t, ok) {
+		assert.Equal(t, int64(1), sink.Count)
+		assert.GreaterOrEqual(t, sink.Sum, 0.002)
+	}
+}
@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"bytes"
+	"regexp"
+	"sync"
+)
+
+// Rule assigns level and tags to ingested lines matching the pattern.
+type Rule struct {
+	Pattern *regexp.Regexp
+	// Level of matching lines, empty to keep the level of a logger.
+	Level Level
+	// Tags appended to tags of a logger.
+	Tags []string
+}
+
+// Parser decodes lines written in a structured format (e.g. JSON). It returns false if the line is not in a supported
+// format. Level of returned record may be empty if it is unknown.
+type Parser func(line string) (Record, bool)
+
+// IngestOption configures Ingester.
+type IngestOption func(*Ingester)
+
+// WithRules adds rules classifying plain lines, e.g. "^ERROR" as error or "warning:" as warn. Rules are evaluated in
+// order, the first matching rule wins. Lines not matching any rule are printed using a logger as it is.
+func WithRules(rules ...Rule) IngestOption {
+	return func(i *Ingester) {
+		i.rules = append(i.rules, rules...)
+	}
+}
+
+// WithParsers adds parsers of structured lines (e.g. ParseJSON). Parsers are tried in order, lines which cannot be
+// parsed are handled as plain text.
+func WithParsers(parsers ...Parser) IngestOption {
+	return func(i *Ingester) {
+		i.parsers = append(i.parsers, parsers...)
+	}
+}
+
+// MaxLineLength is the maximum length of a line processed by Ingester. Longer lines are split, so memory used by
+// ingester is bounded.
+const MaxLineLength = 64 * 1024
+
+// Ingester is an io.Writer turning output of other tools (e.g. make or npm) into meaningful log lines. Lines already
+// decorated with control sequences interpreted by Klio are decoded and printed using their level and tags.
+type Ingester struct {
+	mu         sync.Mutex
+	l          *Logger
+	rules      []Rule
+	parsers    []Parser
+	strippers  []*regexp.Regexp
+	groupings  []Grouping
+	grouping   *Grouping
+	groupLines []string
+	groupSize  int
+	buf        []byte
+}
+
+// NewIngester creates new Ingester writing to the logger.
+func NewIngester(l *Logger, opts ...IngestOption) *Ingester {
+	i := &Ingester{l: l}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return i
+}
+
+// Write processes complete lines of input. Incomplete line is buffered until the rest of it is written or the ingester
+// is closed.
+func (i *Ingester) Write(p []byte) (int, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.buf = append(i.buf, p...)
+	for {
+		n := bytes.IndexByte(i.buf, '\n')
+		if n < 0 {
+			break
+		}
+		i.process(string(bytes.TrimSuffix(i.buf[:n], []byte("\r"))))
+		i.buf = i.buf[n+1:]
+	}
+	for len(i.buf) >= MaxLineLength {
+		i.process(string(i.buf[:MaxLineLength]))
+		i.buf = i.buf[MaxLineLength:]
+	}
+	return len(p), nil
+}
+
+// Close processes buffered incomplete line and pending group of lines.
+func (i *Ingester) Close() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if len(i.buf) > 0 {
+		i.process(string(i.buf))
+		i.buf = nil
+	}
+	i.flushGroup()
+	return nil
+}
+
+func (i *Ingester) process(line string) {
+	if IsDecorated(line) {
+		i.flushGroup()
+		i.l.writeDecorated(line)
+		return
+	}
+	line = i.strip(line)
+	if i.group(line) {
+		return
+	}
+	i.emit(i.classify(i.parse(line)))
+}
+
+func (i *Ingester) parse(line string) Record {
+	for _, parse := range i.parsers {
+		if r, ok := parse(line); ok {
+			return r
+		}
+	}
+	return Record{Message: line}
+}
+
+func (i *Ingester) classify(r Record) Record {
+	if r.Level != "" {
+		return r
+	}
+	for _, rule := range i.rules {
+		if rule.Pattern.MatchString(r.Message) {
+			r.Level = rule.Level
+			r.Tags = append(r.Tags, rule.Tags...)
+			break
+		}
+	}
+	if r.Level == "" {
+		r.Level = i.l.Level()
+	}
+	return r
+}
+
+func (i *Ingester) emit(r Record) {
+	l := i.l.WithLevel(r.Level)
+	if len(r.Tags) > 0 {
+		l = l.WithAppendedTags(r.Tags...)
+	}
+	if len(r.Fields) > 0 {
+		l = l.WithFields(r.Fields...)
+	}
+	l.Print(r.Message)
+}
@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Grouping describes multiline messages (e.g. stack traces), which are grouped by Ingester into a single record.
+type Grouping struct {
+	// Start matches the first line of a group.
+	Start *regexp.Regexp
+	// Continuation matches following lines of a group.
+	Continuation *regexp.Regexp
+	// Level of grouped record, empty to classify it like a single line.
+	Level Level
+}
+
+var (
+	// GoPanicGrouping groups Go panics and fatal runtime errors.
+	GoPanicGrouping = Grouping{
+		Start:        regexp.MustCompile(`^(panic: |fatal error: )`),
+		Continuation: regexp.MustCompile(`^(\s*$|\t|goroutine \d+ \[|\S+\(.*\)$|created by |\[signal |exit status \d+$|\[recovered\])`),
+		Level:        ErrorLevel,
+	}
+	// JavaStackTraceGrouping groups Java (and other JVM languages) exceptions.
+	JavaStackTraceGrouping = Grouping{
+		Start:        regexp.MustCompile(`^(Exception in thread "|([\w$]+\.)+[\w$]*(Exception|Error)(: |$))`),
+		Continuation: regexp.MustCompile(`^(\s+at |\s+\.\.\. \d+ (more|common frames omitted)|Caused by: |\s+Suppressed: )`),
+		Level:        ErrorLevel,
+	}
+	// PythonTracebackGrouping groups Python tracebacks.
+	PythonTracebackGrouping = Grouping{
+		Start:        regexp.MustCompile(`^Traceback \(most recent call last\):`),
+		Continuation: regexp.MustCompile(`^([ \t]+|[\w.]+(Error|Exception|Exit|Interrupt|Warning)\b|During handling of the above exception|The above exception was|\s*$)`),
+		Level:        ErrorLevel,
+	}
+)
+
+// MaxGroupLength is the maximum length of a message grouped by Ingester, so memory used by ingester is bounded. Longer
+// groups are split into several records.
+const MaxGroupLength = 16 * MaxLineLength
+
+// WithGrouping enables grouping of multiline messages. Group ends with the first line not matching continuation
+// pattern, or when the ingester is closed. Groups exceeding MaxGroupLength are continued in the next record.
+func WithGrouping(groupings ...Grouping) IngestOption {
+	return func(i *Ingester) {
+		i.groupings = append(i.groupings, groupings...)
+	}
+}
+
+// group consumes line if it belongs to a group. Caller must hold i.mu.
+func (i *Ingester) group(line string) bool {
+	if i.grouping != nil {
+		if i.grouping.Continuation.MatchString(line) {
+			if i.groupSize+len(line) > MaxGroupLength {
+				g := i.grouping
+				i.flushGroup()
+				i.grouping = g
+			}
+			i.groupLines = append(i.groupLines, line)
+			i.groupSize += len(line) + 1
+			return true
+		}
+		i.flushGroup()
+	}
+	for n := range i.groupings {
+		if i.groupings[n].Start.MatchString(line) {
+			i.grouping = &i.groupings[n]
+			i.groupLines, i.groupSize = []string{line}, len(line)+1
+			return true
+		}
+	}
+	return false
+}
+
+// flushGroup emits pending group. Caller must hold i.mu.
+func (i *Ingester) flushGroup() {
+	if i.grouping == nil {
+		return
+	}
+	lines := i.groupLines
+	for len(lines) > 1 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	r := Record{Level: i.grouping.Level, Message: strings.Join(lines, "\n")}
+	i.grouping, i.groupLines, i.groupSize = nil, nil, 0
+	i.emit(i.classify(r))
+}
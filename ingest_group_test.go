@@ -0,0 +1,80 @@
+package logger_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestIngesterWithGrouping(t *testing.T) {
+	t.Run("group Go panic", func(t *testing.T) {
+		var b bytes.Buffer
+		i := log.NewIngester(log.New(&b), log.WithGrouping(log.GoPanicGrouping))
+
+		i.Write([]byte("before\npanic: boom\n\ngoroutine 1 [running]:\nmain.main()\n\t/tmp/main.go:8 +0x1d\nexit status 2\n\nafter\n"))
+		i.Close()
+
+		assert.Equal(
+			t,
+			"\033_klio_log_level \"info\"\033\\\033_klio_tags []\033\\before\033_klio_reset\033\\\n"+
+				"\033_klio_log_level \"error\"\033\\\033_klio_tags []\033\\panic: boom\n\ngoroutine 1 [running]:\nmain.main()\n\t/tmp/main.go:8 +0x1d\nexit status 2\033_klio_reset\033\\\n"+
+				"\033_klio_log_level \"info\"\033\\\033_klio_tags []\033\\after\033_klio_reset\033\\\n",
+			b.String(),
+		)
+	})
+
+	t.Run("group Java stack trace", func(t *testing.T) {
+		var b bytes.Buffer
+		i := log.NewIngester(log.New(&b), log.WithGrouping(log.JavaStackTraceGrouping))
+
+		i.Write([]byte("Exception in thread \"main\" java.lang.IllegalStateException: boom\n\tat Main.main(Main.java:5)\nCaused by: java.io.IOException\n\t... 3 more\n"))
+		i.Close()
+
+		assert.Equal(
+			t,
+			"\033_klio_log_level \"error\"\033\\\033_klio_tags []\033\\Exception in thread \"main\" java.lang.IllegalStateException: boom\n\tat Main.main(Main.java:5)\nCaused by: java.io.IOException\n\t... 3 more\033_klio_reset\033\\\n",
+			b.String(),
+		)
+	})
+
+	t.Run("group Python traceback", func(t *testing.T) {
+		var b bytes.Buffer
+		i := log.NewIngester(log.New(&b), log.WithGrouping(log.PythonTracebackGrouping))
+
+		i.Write([]byte("Traceback (most recent call last):\n  File \"x.py\", line 1, in <module>\n    1/0\nZeroDivisionError: division by zero\n"))
+		i.Close()
+
+		assert.Equal(
+			t,
+			"\033_klio_log_level \"error\"\033\\\033_klio_tags []\033\\Traceback (most recent call last):\n  File \"x.py\", line 1, in <module>\n    1/0\nZeroDivisionError: division by zero\033_klio_reset\033\\\n",
+			b.String(),
+		)
+	})
+
+	t.Run("split groups exceeding limit", func(t *testing.T) {
+		ring := log.NewRingBuffer(10)
+		i := log.NewIngester(log.New(io.Discard).WithRingBuffer(ring), log.WithGrouping(log.GoPanicGrouping))
+
+		frame := "\t" + strings.Repeat("x", 1023)
+		lines := []string{"panic: boom"}
+		for len(lines) < 2*log.MaxGroupLength/len(frame) {
+			lines = append(lines, frame)
+		}
+		i.Write([]byte(strings.Join(lines, "\n") + "\n"))
+		i.Close()
+
+		var messages []string
+		for _, r := range ring.Records() {
+			assert.Equal(t, log.ErrorLevel, r.Level)
+			assert.LessOrEqual(t, len(r.Message), log.MaxGroupLength)
+			messages = append(messages, r.Message)
+		}
+		assert.Len(t, messages, 3)
+		assert.Equal(t, strings.Join(lines, "\n"), strings.Join(messages, "\n"))
+	})
+}
@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+var ingestLevels = map[string]Level{
+	"trace":    SpamLevel,
+	"debug":    DebugLevel,
+	"verbose":  VerboseLevel,
+	"info":     InfoLevel,
+	"notice":   InfoLevel,
+	"warn":     WarnLevel,
+	"warning":  WarnLevel,
+	"err":      ErrorLevel,
+	"error":    ErrorLevel,
+	"crit":     FatalLevel,
+	"critical": FatalLevel,
+	"alert":    FatalLevel,
+	"emerg":    FatalLevel,
+	"dpanic":   FatalLevel,
+	"panic":    FatalLevel,
+	"fatal":    FatalLevel,
+}
+
+// ingestLevel converts level name used by other logging libraries to Level. It returns empty level for unknown names.
+func ingestLevel(name string) Level {
+	name = strings.ToLower(name)
+	if level, ok := levelsMap[name]; ok {
+		return level
+	}
+	return ingestLevels[name]
+}
+
+// pinoLevel converts numeric levels used by pino and bunyan.
+func pinoLevel(n json.Number) Level {
+	v, err := n.Int64()
+	switch {
+	case err != nil:
+		return ""
+	case v >= 60:
+		return FatalLevel
+	case v >= 50:
+		return ErrorLevel
+	case v >= 40:
+		return WarnLevel
+	case v >= 30:
+		return InfoLevel
+	case v >= 20:
+		return DebugLevel
+	default:
+		return SpamLevel
+	}
+}
+
+// ParseJSON parses JSON lines produced by structured loggers (e.g. zap, zerolog, logrus, pino). Level is read from
+// "level", "lvl" or "severity" key, message from "msg" or "message" key. Other keys are returned as fields, preserving
+// their order.
+func ParseJSON(line string) (Record, bool) {
+	if !strings.HasPrefix(strings.TrimSpace(line), "{") {
+		return Record{}, false
+	}
+
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return Record{}, false
+	}
+
+	r := Record{Fields: []Field{}}
+	levelFound, messageFound := false, false
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return Record{}, false
+		}
+		key, _ := tok.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return Record{}, false
+		}
+
+		var value interface{}
+		valueDec := json.NewDecoder(bytes.NewReader(raw))
+		valueDec.UseNumber()
+		valueDec.Decode(&value)
+
+		switch {
+		case !levelFound && (key == "level" || key == "lvl" || key == "severity"):
+			levelFound = true
+			switch v := value.(type) {
+			case string:
+				r.Level = ingestLevel(v)
+			case json.Number:
+				r.Level = pinoLevel(v)
+			}
+		case !messageFound && (key == "msg" || key == "message"):
+			messageFound = true
+			if s, ok := value.(string); ok {
+				r.Message = s
+			} else {
+				r.Message = string(raw)
+			}
+		default:
+			switch v := value.(type) {
+			case string, json.Number, bool:
+				r.Fields = append(r.Fields, Field{Key: key, Value: v})
+			default:
+				r.Fields = append(r.Fields, Field{Key: key, Value: string(raw)})
+			}
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return Record{}, false
+	}
+
+	return r, true
+}
@@ -0,0 +1,62 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestParseJSON(t *testing.T) {
+	t.Run("parse zap line", func(t *testing.T) {
+		r, ok := log.ParseJSON(`{"level":"warn","ts":1.5,"caller":"main.go:12","msg":"foo","user":{"id":1}}`)
+		assert.True(t, ok)
+		assert.Equal(t, log.Record{
+			Level:   log.WarnLevel,
+			Message: "foo",
+			Fields: []log.Field{
+				{Key: "ts", Value: json.Number("1.5")},
+				{Key: "caller", Value: "main.go:12"},
+				{Key: "user", Value: `{"id":1}`},
+			},
+		}, r)
+	})
+
+	t.Run("parse pino line", func(t *testing.T) {
+		r, ok := log.ParseJSON(`{"level":50,"pid":1,"msg":"foo"}`)
+		assert.True(t, ok)
+		assert.Equal(t, log.Record{Level: log.ErrorLevel, Message: "foo", Fields: []log.Field{{Key: "pid", Value: json.Number("1")}}}, r)
+	})
+
+	t.Run("parse line with unknown level", func(t *testing.T) {
+		r, ok := log.ParseJSON(`{"severity":"loud","message":"foo"}`)
+		assert.True(t, ok)
+		assert.Equal(t, log.Record{Level: "", Message: "foo", Fields: []log.Field{}}, r)
+	})
+
+	t.Run("reject invalid lines", func(t *testing.T) {
+		_, ok := log.ParseJSON(`foo`)
+		assert.False(t, ok)
+		_, ok = log.ParseJSON(`{"level":"info"`)
+		assert.False(t, ok)
+		_, ok = log.ParseJSON(`[1, 2]`)
+		assert.False(t, ok)
+	})
+}
+
+func TestIngesterWithJSON(t *testing.T) {
+	var b bytes.Buffer
+	i := log.NewIngester(log.New(&b), log.WithParsers(log.ParseJSON))
+
+	i.Write([]byte(`{"level":"error","msg":"foo","code":3}` + "\nbar\n"))
+
+	assert.Equal(
+		t,
+		"\033_klio_log_level \"error\"\033\\\033_klio_tags []\033\\foo code=3\033_klio_reset\033\\\n"+
+			"\033_klio_log_level \"info\"\033\\\033_klio_tags []\033\\bar\033_klio_reset\033\\\n",
+		b.String(),
+	)
+}
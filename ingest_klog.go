@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	klogLine   = regexp.MustCompile(`^([IWEF])(\d{4} \d{2}:\d{2}:\d{2}\.\d+)\s+(\d+) ([^ \]]+:\d+)\] ?(.*)$`)
+	klogLevels = map[string]Level{"I": InfoLevel, "W": WarnLevel, "E": ErrorLevel, "F": FatalLevel}
+)
+
+// ParseKlog parses lines written by glog and klog (e.g. "W0102 15:04:05.000000 1 file.go:12] msg"), used by kubectl
+// and many other Kubernetes tools. Severity letter is mapped to level, time, thread ID and caller are returned as
+// fields. Structured klog messages (`"msg" key="value"`) are also supported.
+func ParseKlog(line string) (Record, bool) {
+	m := klogLine.FindStringSubmatch(line)
+	if m == nil {
+		return Record{}, false
+	}
+
+	r := Record{
+		Level:   klogLevels[m[1]],
+		Message: m[5],
+		Fields: []Field{
+			{Key: "time", Value: m[2]},
+			{Key: "thread", Value: m[3]},
+			{Key: "caller", Value: m[4]},
+		},
+	}
+
+	if strings.HasPrefix(r.Message, `"`) {
+		if quoted, err := strconv.QuotedPrefix(r.Message); err == nil {
+			msg, _ := strconv.Unquote(quoted)
+			rest := strings.TrimLeft(r.Message[len(quoted):], " ")
+			var fields []Field
+			for ok := true; ok && rest != ""; {
+				var key, value string
+				if key, value, rest, ok = nextLogfmtPair(rest); ok {
+					fields = append(fields, Field{Key: key, Value: value})
+				}
+			}
+			if rest == "" {
+				r.Message = msg
+				r.Fields = append(r.Fields, fields...)
+			}
+		}
+	}
+
+	return r, true
+}
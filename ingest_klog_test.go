@@ -0,0 +1,56 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestParseKlog(t *testing.T) {
+	t.Run("parse plain line", func(t *testing.T) {
+		r, ok := log.ParseKlog("W0102 15:04:05.000000    1 file.go:12] foo bar")
+		assert.True(t, ok)
+		assert.Equal(t, log.Record{
+			Level:   log.WarnLevel,
+			Message: "foo bar",
+			Fields: []log.Field{
+				{Key: "time", Value: "0102 15:04:05.000000"},
+				{Key: "thread", Value: "1"},
+				{Key: "caller", Value: "file.go:12"},
+			},
+		}, r)
+	})
+
+	t.Run("parse structured line", func(t *testing.T) {
+		r, ok := log.ParseKlog(`E0102 15:04:05.000000 7 pod.go:3] "Pod status updated" pod="kube-system/dns" ready=false`)
+		assert.True(t, ok)
+		assert.Equal(t, log.ErrorLevel, r.Level)
+		assert.Equal(t, "Pod status updated", r.Message)
+		assert.Equal(t, []log.Field{
+			{Key: "time", Value: "0102 15:04:05.000000"},
+			{Key: "thread", Value: "7"},
+			{Key: "caller", Value: "pod.go:3"},
+			{Key: "pod", Value: "kube-system/dns"},
+			{Key: "ready", Value: "false"},
+		}, r.Fields)
+	})
+
+	t.Run("reject other lines", func(t *testing.T) {
+		_, ok := log.ParseKlog("X0102 15:04:05.000000 1 file.go:12] foo")
+		assert.False(t, ok)
+		_, ok = log.ParseKlog("foo")
+		assert.False(t, ok)
+	})
+}
+
+func TestIngesterWithKlog(t *testing.T) {
+	var b bytes.Buffer
+	i := log.NewIngester(log.New(&b), log.WithParsers(log.ParseKlog))
+
+	i.Write([]byte("F0102 15:04:05.000000 1 main.go:1] boom\n"))
+
+	assert.Equal(t, "\033_klio_log_level \"fatal\"\033\\\033_klio_tags []\033\\boom time=\"0102 15:04:05.000000\" thread=1 caller=main.go:1\033_klio_reset\033\\\n", b.String())
+}
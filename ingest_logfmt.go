@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParseLogfmt parses lines in logfmt format (e.g. `time="..." level=info msg="hello world"`) used by Docker, Vault and
+// other tools. Level is read from "level" or "lvl" key, message from "msg" or "message" key, other pairs are returned
+// as fields. Lines without level or message keys are not considered logfmt.
+func ParseLogfmt(line string) (Record, bool) {
+	r := Record{Fields: []Field{}}
+	levelFound, messageFound := false, false
+
+	s := strings.TrimSpace(line)
+	for s != "" {
+		var key, value string
+		var ok bool
+		if key, value, s, ok = nextLogfmtPair(s); !ok {
+			return Record{}, false
+		}
+
+		switch {
+		case !levelFound && (key == "level" || key == "lvl"):
+			levelFound = true
+			r.Level = ingestLevel(value)
+		case !messageFound && (key == "msg" || key == "message"):
+			messageFound = true
+			r.Message = value
+		default:
+			r.Fields = append(r.Fields, Field{Key: key, Value: value})
+		}
+	}
+
+	if !levelFound && !messageFound {
+		return Record{}, false
+	}
+	return r, true
+}
+
+// nextLogfmtPair reads single key=value pair from the beginning of s and returns the rest of s.
+func nextLogfmtPair(s string) (key, value, rest string, ok bool) {
+	i := strings.IndexAny(s, "= ")
+	if i == 0 {
+		return "", "", "", false
+	}
+	if i < 0 || s[i] == ' ' {
+		// Key without a value
+		if i < 0 {
+			i = len(s)
+		}
+		return s[:i], "true", strings.TrimLeft(s[i:], " "), true
+	}
+
+	key, s = s[:i], s[i+1:]
+	if strings.HasPrefix(s, `"`) {
+		quoted, err := strconv.QuotedPrefix(s)
+		if err != nil {
+			return "", "", "", false
+		}
+		if value, err = strconv.Unquote(quoted); err != nil {
+			return "", "", "", false
+		}
+		s = s[len(quoted):]
+		if s != "" && s[0] != ' ' {
+			return "", "", "", false
+		}
+	} else {
+		end := strings.IndexByte(s, ' ')
+		if end < 0 {
+			end = len(s)
+		}
+		value, s = s[:end], s[end:]
+		if strings.Contains(value, `"`) {
+			return "", "", "", false
+		}
+	}
+
+	return key, value, strings.TrimLeft(s, " "), true
+}
@@ -0,0 +1,52 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestParseLogfmt(t *testing.T) {
+	t.Run("parse docker line", func(t *testing.T) {
+		r, ok := log.ParseLogfmt(`time="2024-01-02T15:04:05Z" level=warning msg="foo \"bar\"" container=abc debug`)
+		assert.True(t, ok)
+		assert.Equal(t, log.Record{
+			Level:   log.WarnLevel,
+			Message: `foo "bar"`,
+			Fields: []log.Field{
+				{Key: "time", Value: "2024-01-02T15:04:05Z"},
+				{Key: "container", Value: "abc"},
+				{Key: "debug", Value: "true"},
+			},
+		}, r)
+	})
+
+	t.Run("parse line with empty value", func(t *testing.T) {
+		r, ok := log.ParseLogfmt(`msg= a=`)
+		assert.True(t, ok)
+		assert.Equal(t, log.Record{Message: "", Fields: []log.Field{{Key: "a", Value: ""}}}, r)
+	})
+
+	t.Run("reject lines which are not logfmt", func(t *testing.T) {
+		_, ok := log.ParseLogfmt(`hello world`)
+		assert.False(t, ok)
+		_, ok = log.ParseLogfmt(`a=b c=d`)
+		assert.False(t, ok)
+		_, ok = log.ParseLogfmt(`msg="unterminated`)
+		assert.False(t, ok)
+		_, ok = log.ParseLogfmt(`=foo msg=bar`)
+		assert.False(t, ok)
+	})
+}
+
+func TestIngesterWithLogfmt(t *testing.T) {
+	var b bytes.Buffer
+	i := log.NewIngester(log.New(&b), log.WithParsers(log.ParseJSON, log.ParseLogfmt))
+
+	i.Write([]byte("level=error msg=\"foo bar\" code=3\n"))
+
+	assert.Equal(t, "\033_klio_log_level \"error\"\033\\\033_klio_tags []\033\\foo bar code=3\033_klio_reset\033\\\n", b.String())
+}
@@ -0,0 +1,38 @@
+package logger
+
+import "regexp"
+
+var (
+	// TimestampPrefix matches common timestamps at the beginning of a line, e.g. "2024-01-02 15:04:05 ",
+	// "2024-01-02T15:04:05.000Z ", "[15:04:05] " or "Jan  2 15:04:05 ".
+	TimestampPrefix = regexp.MustCompile(`^\[?(\d{4}[-/]\d{2}[-/]\d{2}[T ]\d{2}:\d{2}:\d{2}([.,]\d+)?(Z|[+-]\d{2}:?\d{2})?|[A-Z][a-z]{2} +\d{1,2} \d{2}:\d{2}:\d{2}|\d{2}:\d{2}:\d{2}([.,]\d+)?)\]?:?\s+`)
+	// PIDPrefix matches process IDs at the beginning of a line, e.g. "[1234] " or "pid=1234 ".
+	PIDPrefix = regexp.MustCompile(`^(\[\d+\]|\(\d+\)|pid[=:] ?\d+):?\s+`)
+	// BracketPrefix matches any bracketed prefix at the beginning of a line, e.g. "[pool-1] ". Note, that it also strips
+	// markers like "[ERROR]", which may be needed by rules (see WithRules).
+	BracketPrefix = regexp.MustCompile(`^\[[^\]]*\]:?\s+`)
+)
+
+// WithStrippers removes prefixes matching any of the patterns (e.g. TimestampPrefix) from the beginning of lines before
+// they are processed, so Klio doesn't show duplicate noisy prefixes. Patterns are applied repeatedly as long as any of
+// them matches, so "2024-01-02 15:04:05 [pool-1] foo" becomes "foo" when used with TimestampPrefix and BracketPrefix.
+// Patterns should be anchored with "^".
+func WithStrippers(patterns ...*regexp.Regexp) IngestOption {
+	return func(i *Ingester) {
+		i.strippers = append(i.strippers, patterns...)
+	}
+}
+
+// strip removes prefixes from the line.
+func (i *Ingester) strip(line string) string {
+	for stripped := true; stripped; {
+		stripped = false
+		for _, p := range i.strippers {
+			if loc := p.FindStringIndex(line); loc != nil && loc[0] == 0 && loc[1] > 0 {
+				line = line[loc[1]:]
+				stripped = true
+			}
+		}
+	}
+	return line
+}
@@ -0,0 +1,30 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestPrefixes(t *testing.T) {
+	assert.Equal(t, "foo", log.TimestampPrefix.ReplaceAllString("2024-01-02 15:04:05 foo", ""))
+	assert.Equal(t, "foo", log.TimestampPrefix.ReplaceAllString("2024-01-02T15:04:05.123+02:00 foo", ""))
+	assert.Equal(t, "foo", log.TimestampPrefix.ReplaceAllString("[15:04:05] foo", ""))
+	assert.Equal(t, "foo", log.TimestampPrefix.ReplaceAllString("Jan  2 15:04:05 foo", ""))
+	assert.Equal(t, "2024 foo", log.TimestampPrefix.ReplaceAllString("2024 foo", ""))
+	assert.Equal(t, "foo", log.PIDPrefix.ReplaceAllString("[1234] foo", ""))
+	assert.Equal(t, "foo", log.PIDPrefix.ReplaceAllString("pid=1234 foo", ""))
+	assert.Equal(t, "foo", log.BracketPrefix.ReplaceAllString("[pool-1] foo", ""))
+}
+
+func TestIngesterWithStrippers(t *testing.T) {
+	var b bytes.Buffer
+	i := log.NewIngester(log.New(&b), log.WithStrippers(log.TimestampPrefix, log.BracketPrefix))
+
+	i.Write([]byte("2024-01-02 15:04:05 [pool-1] foo [bar]\n"))
+
+	assert.Equal(t, "\033_klio_log_level \"info\"\033\\\033_klio_tags []\033\\foo [bar]\033_klio_reset\033\\\n", b.String())
+}
@@ -0,0 +1,75 @@
+package logger
+
+// Interface is a minimal logger abstraction. Packages can depend on it instead of *Logger, so tests can inject fakes
+// (or Nop). WithLevel and WithTags return Interface, so fakes see chained calls too. Since Go doesn't allow methods of
+// *Logger to return Interface, use Logger.Interface to obtain an implementation backed by a logger.
+type Interface interface {
+	// Print writes log line. Arguments are handled in the manner of fmt.Print.
+	Print(v ...interface{})
+	// Printf writes log line. Arguments are handled in the manner of fmt.Printf.
+	Printf(format string, v ...interface{})
+	// WithLevel creates new logger logging at specified level.
+	WithLevel(level Level) Interface
+	// WithTags creates new logger with specified tags.
+	WithTags(tags ...string) Interface
+	// Write prints input line by line.
+	Write(p []byte) (int, error)
+}
+
+// Interface returns Interface implementation writing to the logger.
+func (l *Logger) Interface() Interface {
+	return loggerInterface{l}
+}
+
+type loggerInterface struct {
+	l *Logger
+}
+
+var _ Interface = loggerInterface{}
+
+func (i loggerInterface) Print(v ...interface{}) {
+	i.l.Print(v...)
+}
+
+func (i loggerInterface) Printf(format string, v ...interface{}) {
+	i.l.Printf(format, v...)
+}
+
+func (i loggerInterface) WithLevel(level Level) Interface {
+	return loggerInterface{i.l.WithLevel(level)}
+}
+
+func (i loggerInterface) WithTags(tags ...string) Interface {
+	return loggerInterface{i.l.WithTags(tags...)}
+}
+
+func (i loggerInterface) Write(p []byte) (int, error) {
+	return i.l.Write(p)
+}
+
+// Nop is Interface implementation discarding everything. It doesn't format messages and has no state, so it can be
+// shared freely.
+type Nop struct{}
+
+var _ Interface = Nop{}
+
+// Print does nothing.
+func (Nop) Print(v ...interface{}) {}
+
+// Printf does nothing.
+func (Nop) Printf(format string, v ...interface{}) {}
+
+// WithLevel returns Nop.
+func (n Nop) WithLevel(level Level) Interface {
+	return n
+}
+
+// WithTags returns Nop.
+func (n Nop) WithTags(tags ...string) Interface {
+	return n
+}
+
+// Write discards input.
+func (Nop) Write(p []byte) (int, error) {
+	return len(p), nil
+}
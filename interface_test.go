@@ -0,0 +1,80 @@
+package logger_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestInterface(t *testing.T) {
+	var b bytes.Buffer
+	var l log.Interface = log.New(&b).Interface()
+
+	l.WithTags("a").WithLevel(log.WarnLevel).Printf("%s", "foo")
+	l.Print("bar")
+	l.Write([]byte("baz\n"))
+
+	assert.Equal(
+		t,
+		"\033_klio_log_level \"warn\"\033\\\033_klio_tags [\"a\"]\033\\foo\033_klio_reset\033\\\n"+
+			"\033_klio_log_level \"info\"\033\\\033_klio_tags []\033\\bar\033_klio_reset\033\\\n"+
+			"\033_klio_log_level \"info\"\033\\\033_klio_tags []\033\\baz\033_klio_reset\033\\\n",
+		b.String(),
+	)
+}
+
+// fakeLogger records messages together with level and tags set by chained calls.
+type fakeLogger struct {
+	level    log.Level
+	tags     []string
+	messages *[]string
+}
+
+func (f fakeLogger) Print(v ...interface{}) {
+	*f.messages = append(*f.messages, fmt.Sprintf("%s %v %s", f.level, f.tags, fmt.Sprint(v...)))
+}
+
+func (f fakeLogger) Printf(format string, v ...interface{}) {
+	f.Print(fmt.Sprintf(format, v...))
+}
+
+func (f fakeLogger) WithLevel(level log.Level) log.Interface {
+	f.level = level
+	return f
+}
+
+func (f fakeLogger) WithTags(tags ...string) log.Interface {
+	f.tags = tags
+	return f
+}
+
+func (f fakeLogger) Write(p []byte) (int, error) {
+	f.Print(string(p))
+	return len(p), nil
+}
+
+func TestInterfaceFake(t *testing.T) {
+	var messages []string
+	var l log.Interface = fakeLogger{level: log.InfoLevel, messages: &messages}
+
+	l.WithLevel(log.WarnLevel).WithTags("a").Printf("%d", 1)
+	l.Print("foo")
+
+	assert.Equal(t, []string{"warn [a] 1", "info [] foo"}, messages)
+}
+
+func TestNop(t *testing.T) {
+	var l log.Interface = log.Nop{}
+
+	n, err := l.WithTags("a").WithLevel(log.WarnLevel).Write([]byte("foo"))
+	l.Print("foo")
+	l.Printf("%s", "foo")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, log.Nop{}, l.WithLevel(log.InfoLevel))
+}
@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+)
+
+var (
+	flushersMu sync.Mutex
+	flushers   = map[*func()]struct{}{}
+)
+
+// Flush flushes outputs of the standard and error loggers, as well as buffers which hold lines not written yet.
+func Flush() {
+	flushersMu.Lock()
+	fns := make([]func(), 0, len(flushers))
+	for f := range flushers {
+		fns = append(fns, *f)
+	}
+	flushersMu.Unlock()
+
+	for _, f := range fns {
+		f()
+	}
+	flushOutput(standardLogger.Output())
+	flushOutput(errorLogger.Output())
+}
+
+// registerFlusher adds function called by Flush and returns function removing it.
+func registerFlusher(f func()) func() {
+	p := &f
+	flushersMu.Lock()
+	defer flushersMu.Unlock()
+	flushers[p] = struct{}{}
+	return func() {
+		flushersMu.Lock()
+		defer flushersMu.Unlock()
+		delete(flushers, p)
+	}
+}
+
+// HandleInterrupts terminates the program when it receives one of specified signals (SIGINT and SIGTERM if none are
+// specified), after writing "Interrupted by <signal>" at warn level and flushing logs (see Flush). The exit code is 128
+// plus the signal number. Calling the returned function stops handling signals.
+func HandleInterrupts(l *Logger, signals ...os.Signal) func() {
+	if len(signals) == 0 {
+		signals = interruptSignals
+	}
+
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, signals...)
+
+	go func() {
+		select {
+		case <-done:
+		case sig := <-ch:
+			l.WithLevel(WarnLevel).Printf("Interrupted by %s", sig)
+			Flush()
+			flushOutput(l.Output())
+			os.Exit(signalExitCode(sig))
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
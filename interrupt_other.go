@@ -0,0 +1,18 @@
+//go:build !plan9
+
+package logger
+
+import (
+	"os"
+	"syscall"
+)
+
+var interruptSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// signalExitCode returns exit code conventionally used by shells for processes terminated by the signal.
+func signalExitCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 1
+}
@@ -0,0 +1,10 @@
+package logger
+
+import "os"
+
+var interruptSignals = []os.Signal{os.Interrupt}
+
+// signalExitCode returns 1, notes don't have numbers on this platform.
+func signalExitCode(sig os.Signal) int {
+	return 1
+}
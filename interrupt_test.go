@@ -0,0 +1,14 @@
+package logger_test
+
+import (
+	"io"
+	"testing"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestHandleInterruptsStop(t *testing.T) {
+	stop := log.HandleInterrupts(log.New(io.Discard))
+	stop()
+	stop()
+}
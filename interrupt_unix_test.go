@@ -0,0 +1,46 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package logger_test
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestHandleInterrupts(t *testing.T) {
+	if os.Getenv("KLIO_TEST_INTERRUPT") == "1" {
+		log.HandleInterrupts(log.StandardLogger())
+		log.Info("ready")
+		time.Sleep(10 * time.Second)
+		return
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHandleInterrupts$")
+	cmd.Env = append(os.Environ(), "KLIO_TEST_INTERRUPT=1", log.EnvMode+"=plain")
+	stdout, err := cmd.StdoutPipe()
+	assert.NoError(t, err)
+	assert.NoError(t, cmd.Start())
+
+	r := bufio.NewReader(stdout)
+	line, err := r.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "[INFO] ready\n", line)
+
+	assert.NoError(t, cmd.Process.Signal(syscall.SIGTERM))
+	rest, _ := io.ReadAll(r)
+	err = cmd.Wait()
+
+	assert.Equal(t, "[WARN] Interrupted by terminated\n", string(rest))
+	var exitErr *exec.ExitError
+	if assert.ErrorAs(t, err, &exitErr) {
+		assert.Equal(t, 128+int(syscall.SIGTERM), exitErr.ExitCode())
+	}
+}
@@ -0,0 +1,129 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JournaldSocket is the path of the socket receiving journal entries using the native protocol.
+const JournaldSocket = "/run/systemd/journal/socket"
+
+var journaldPriorities = map[Level]int{
+	FatalLevel:   2, // crit
+	ErrorLevel:   3, // err
+	WarnLevel:    4, // warning
+	InfoLevel:    6, // info
+	VerboseLevel: 7, // debug
+	DebugLevel:   7,
+	SpamLevel:    7,
+}
+
+// JournaldPriority returns syslog priority corresponding to the level, used as PRIORITY of journal entries. Unknown
+// levels are mapped to 5 (notice).
+func JournaldPriority(level Level) int {
+	if p, ok := journaldPriorities[level]; ok {
+		return p
+	}
+	return 5
+}
+
+// JournaldSink writes records to the systemd journal using its native protocol, so they can be filtered with
+// journalctl, e.g. "journalctl KLIO_TAG=deploy PRIORITY=3":
+//
+//	sink, err := log.NewJournaldSink(log.JournaldSocket, "deploy")
+//	l = l.WithSink(sink)
+//
+// Each entry contains MESSAGE, PRIORITY (see JournaldPriority), SYSLOG_IDENTIFIER, KLIO_LEVEL, KLIO_RUN_ID and
+// KLIO_TAG (once for each tag). Fields are added as journal fields with names converted to uppercase, e.g. "release"
+// becomes RELEASE. Fields named like the fields above are prefixed with FIELD_.
+type JournaldSink struct {
+	mu         sync.Mutex
+	conn       *net.UnixConn
+	identifier string
+}
+
+// NewJournaldSink creates new JournaldSink writing to the socket (usually JournaldSocket). If identifier is empty,
+// the name of the executable is used. The sink should be closed when no longer needed.
+func NewJournaldSink(socket, identifier string) (*JournaldSink, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	if identifier == "" {
+		identifier = filepath.Base(os.Args[0])
+	}
+	return &JournaldSink{conn: conn, identifier: identifier}, nil
+}
+
+// WriteRecord sends the record to the journal, see Sink.
+func (s *JournaldSink) WriteRecord(t time.Time, r Record) error {
+	var b bytes.Buffer
+	writeJournalField(&b, "MESSAGE", r.Message)
+	writeJournalField(&b, "PRIORITY", strconv.Itoa(JournaldPriority(r.Level)))
+	writeJournalField(&b, "SYSLOG_IDENTIFIER", s.identifier)
+	writeJournalField(&b, "KLIO_LEVEL", string(r.Level))
+	writeJournalField(&b, "KLIO_RUN_ID", runID)
+	for _, tag := range r.Tags {
+		writeJournalField(&b, "KLIO_TAG", tag)
+	}
+	for _, f := range r.Fields {
+		writeJournalField(&b, journalFieldName(f.Key), safeSprint(f.Value))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return writeJournal(s.conn, b.Bytes())
+}
+
+// Close closes connection to the journal.
+func (s *JournaldSink) Close() error {
+	return s.conn.Close()
+}
+
+// writeJournalField appends the field serialized as described by https://systemd.io/JOURNAL_NATIVE_PROTOCOL.
+func writeJournalField(b *bytes.Buffer, name, value string) {
+	b.WriteString(name)
+	if !strings.ContainsRune(value, '\n') {
+		b.WriteByte('=')
+		b.WriteString(value)
+		b.WriteByte('\n')
+		return
+	}
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len(value)))
+	b.WriteByte('\n')
+	b.Write(size[:])
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+// journalFieldName converts field key to a valid journal field name: uppercase letters, digits and underscores, not
+// starting with an underscore or a digit, at most 64 characters long.
+func journalFieldName(key string) string {
+	name := strings.TrimLeft(strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, key), "_")
+	switch {
+	case name == "", name[0] >= '0' && name[0] <= '9', strings.HasPrefix(name, "KLIO_"),
+		name == "MESSAGE", name == "PRIORITY", name == "SYSLOG_IDENTIFIER":
+		name = "FIELD_" + name
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
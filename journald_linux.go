@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+)
+
+// writeJournal sends the entry to the journal. Entries too large for a datagram are passed using a temporary file
+// descriptor.
+func writeJournal(conn *net.UnixConn, data []byte) error {
+	_, err := conn.Write(data)
+	if !errors.Is(err, syscall.EMSGSIZE) && !errors.Is(err, syscall.ENOBUFS) {
+		return err
+	}
+
+	f, err := os.CreateTemp("/dev/shm", "klio-journal-")
+	if err != nil {
+		if f, err = os.CreateTemp("", "klio-journal-"); err != nil {
+			return err
+		}
+	}
+	defer f.Close()
+	if err := os.Remove(f.Name()); err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	rc, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	// WriteMsgUnix refuses to write to connected datagram sockets
+	var sendErr error
+	err = rc.Write(func(fd uintptr) bool {
+		sendErr = syscall.Sendmsg(int(fd), nil, syscall.UnixRights(int(f.Fd())), nil, 0)
+		return sendErr != syscall.EAGAIN
+	})
+	if err != nil {
+		return err
+	}
+	return sendErr
+}
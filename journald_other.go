@@ -0,0 +1,11 @@
+//go:build !linux
+
+package logger
+
+import "net"
+
+// writeJournal sends the entry to the journal. Entries too large for a datagram are supported only on Linux.
+func writeJournal(conn *net.UnixConn, data []byte) error {
+	_, err := conn.Write(data)
+	return err
+}
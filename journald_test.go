@@ -0,0 +1,54 @@
+package logger_test
+
+import (
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestJournaldPriority(t *testing.T) {
+	assert.Equal(t, 2, log.JournaldPriority(log.FatalLevel))
+	assert.Equal(t, 3, log.JournaldPriority(log.ErrorLevel))
+	assert.Equal(t, 4, log.JournaldPriority(log.WarnLevel))
+	assert.Equal(t, 6, log.JournaldPriority(log.InfoLevel))
+	assert.Equal(t, 7, log.JournaldPriority(log.DebugLevel))
+	assert.Equal(t, 5, log.JournaldPriority("custom"))
+}
+
+func TestJournaldSink(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "journal.sock")
+	journal, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Skip("unixgram sockets are not supported:", err)
+	}
+	defer journal.Close()
+
+	sink, err := log.NewJournaldSink(socket, "klio-test")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer sink.Close()
+
+	l := log.New(io.Discard).WithSink(sink).WithTags("deploy", "eu").WithLevel(log.WarnLevel)
+	l.WithFields(log.Field{Key: "release-name", Value: "api"}, log.Field{Key: "priority", Value: 1}).Print("multi\nline")
+
+	buf := make([]byte, 4096)
+	assert.NoError(t, journal.SetReadDeadline(time.Now().Add(5*time.Second)))
+	n, err := journal.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "MESSAGE\n\x0a\x00\x00\x00\x00\x00\x00\x00multi\nline\n"+
+		"PRIORITY=4\n"+
+		"SYSLOG_IDENTIFIER=klio-test\n"+
+		"KLIO_LEVEL=warn\n"+
+		"KLIO_RUN_ID="+log.RunID()+"\n"+
+		"KLIO_TAG=deploy\n"+
+		"KLIO_TAG=eu\n"+
+		"RELEASE_NAME=api\n"+
+		"FIELD_PRIORITY=1\n", string(buf[:n]))
+}
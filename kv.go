@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// KV is a key/value pair written by Logger.KV.
+type KV struct {
+	Key   string
+	Value interface{}
+}
+
+// KV writes pairs as "key: value" lines with values aligned to the same column, e.g. for summaries.
+func (l *Logger) KV(level Level, pairs ...KV) *Logger {
+	rows := make([][]string, len(pairs))
+	for i, p := range pairs {
+		rows[i] = []string{p.Key + ":", safeSprint(p.Value)}
+	}
+	return l.Columns(level, rows...)
+}
+
+// Columns writes each row as a line with cells aligned to columns separated by two spaces.
+func (l *Logger) Columns(level Level, rows ...[]string) *Logger {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			if w := utf8.RuneCountInString(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	l = l.WithLevel(level)
+	for _, row := range rows {
+		var b strings.Builder
+		for i, cell := range row {
+			b.WriteString(cell)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)+2))
+			}
+		}
+		l.Print(b.String())
+	}
+	return l
+}
@@ -0,0 +1,38 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestKV(t *testing.T) {
+	var b bytes.Buffer
+	log.New(&b).WithOutputMode(log.PlainMode).KV(log.InfoLevel,
+		log.KV{Key: "Release", Value: "api"},
+		log.KV{Key: "Namespace", Value: "production"},
+		log.KV{Key: "Pods", Value: 3},
+	)
+
+	assert.Equal(t, ""+
+		"[INFO] Release:    api\n"+
+		"[INFO] Namespace:  production\n"+
+		"[INFO] Pods:       3\n", b.String())
+}
+
+func TestColumns(t *testing.T) {
+	var b bytes.Buffer
+	log.New(&b).WithOutputMode(log.PlainMode).Columns(log.InfoLevel,
+		[]string{"NAME", "STATUS", "AGE"},
+		[]string{"api", "Running", "5m"},
+		[]string{"żółw", "Pending"},
+	)
+
+	assert.Equal(t, ""+
+		"[INFO] NAME  STATUS   AGE\n"+
+		"[INFO] api   Running  5m\n"+
+		"[INFO] żółw  Pending\n", b.String())
+}
@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"sync/atomic"
+)
+
+// standardLevels caches children of the standard logger used by package-level functions like Info.
+var standardLevels = &levelCache{parent: standardLogger}
+
+// levelCache holds children of a logger using each level, so functions writing at a level don't create a new logger
+// (and render its line prefix) on every call. Children are created again once the logger is modified in place (e.g.
+// by SetOutput or Assign). Reading the cache doesn't lock anything, so concurrent calls don't contend on it.
+type levelCache struct {
+	parent   *Logger
+	snapshot atomic.Value // *levelSnapshot
+}
+
+type levelSnapshot struct {
+	generation uint64
+	loggers    map[Level]*Logger
+}
+
+// get returns child of the parent logger using the level.
+func (c *levelCache) get(level Level) *Logger {
+	generation := atomic.LoadUint64(&c.parent.generation)
+	s, _ := c.snapshot.Load().(*levelSnapshot)
+	if s != nil && s.generation == generation {
+		if l, ok := s.loggers[level]; ok {
+			return l
+		}
+	}
+
+	// The child may already use settings newer than the generation, then it is just created again on the next call
+	l := c.parent.WithLevel(level)
+	next := &levelSnapshot{generation: generation, loggers: map[Level]*Logger{level: l}}
+	if s != nil && s.generation == generation {
+		for k, v := range s.loggers {
+			if k != level {
+				next.loggers[k] = v
+			}
+		}
+	}
+	c.snapshot.Store(next)
+	return l
+}
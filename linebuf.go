@@ -0,0 +1,28 @@
+package logger
+
+import "sync"
+
+// maxPooledLine is the capacity above which line buffers are not reused, so a single huge line doesn't stay in memory.
+const maxPooledLine = 64 << 10
+
+// lineBuffers holds buffers in which lines are assembled, so prefix, message and reset sequence are written to the
+// output using a single write (and usually a single syscall) without allocating a new buffer for each line. Outputs
+// must not retain written data, as required by io.Writer.
+var lineBuffers = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 256)
+		return &b
+	},
+}
+
+func getLineBuffer() *[]byte {
+	return lineBuffers.Get().(*[]byte)
+}
+
+func putLineBuffer(b *[]byte) {
+	if cap(*b) > maxPooledLine {
+		return
+	}
+	*b = (*b)[:0]
+	lineBuffers.Put(b)
+}
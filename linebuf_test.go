@@ -0,0 +1,41 @@
+package logger_test
+
+import (
+	"strings"
+	"testing"
+
+	log "github.com/g2a-com/klio-logger-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// chunkWriter records data passed to each write.
+type chunkWriter struct {
+	chunks []string
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	w.chunks = append(w.chunks, string(p))
+	return len(p), nil
+}
+
+func TestSingleWritePerLine(t *testing.T) {
+	w := &chunkWriter{}
+	l := log.New(w).WithTags("a")
+	l.Print("foo")
+	l.Print("bar")
+
+	assert.Equal(t, []string{
+		"\033_klio_log_level \"info\"\033\\\033_klio_tags [\"a\"]\033\\foo\033_klio_reset\033\\\n",
+		"\033_klio_log_level \"info\"\033\\\033_klio_tags [\"a\"]\033\\bar\033_klio_reset\033\\\n",
+	}, w.chunks)
+}
+
+func TestLongLineWrite(t *testing.T) {
+	w := &chunkWriter{}
+	l := log.New(w).WithOutputMode(log.PlainMode)
+	long := strings.Repeat("x", 100<<10)
+	l.Print(long)
+	l.Print("short")
+
+	assert.Equal(t, []string{"[INFO] " + long + "\n", "[INFO] short\n"}, w.chunks)
+}
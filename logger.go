@@ -1,17 +1,20 @@
 // This logger is meant to be used for building Klio commands (https://github.com/g2a-com/klio).
 // It writes logs decorated with control sequences interpreted by Klio (https://github.com/g2a-com/klio/blob/main/docs/output-handling.md).
-// It doesn't filter or modify messages besides that.
+// By default it doesn't filter or modify messages besides escaping control sequences interpreted by Klio.
 
 package logger
 
 import (
-	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Level type.
@@ -37,8 +40,8 @@ const (
 )
 
 var (
-	standardLogger = New(os.Stdout)
-	errorLogger    = New(os.Stderr).WithLevel(ErrorLevel)
+	standardLogger = newStandardLogger()
+	errorLogger    = configureFromEnv(New(os.Stderr).WithLevel(ErrorLevel))
 	levelsMap      = map[string]Level{
 		string(FatalLevel):   FatalLevel,
 		string(ErrorLevel):   ErrorLevel,
@@ -61,18 +64,80 @@ func ParseLevel(s string) (level Level, ok bool) {
 
 // Logger.
 type Logger struct {
-	output     io.Writer
-	tags       []string
-	level      Level
-	linePrefix string
+	// generation is incremented each time options are modified in place. It is accessed atomically, so it comes first
+	// to be 64-bit aligned on 32-bit platforms.
+	generation uint64
+	mu         *sync.RWMutex
+	options
 }
 
-// New creates new instance of Logger.
+// options holds settings of a logger, it is guarded by Logger.mu.
+type options struct {
+	output         io.Writer
+	tags           []string
+	dedupTags      bool
+	tagPrefix      string
+	tagLimits      TagLimits
+	pretty         *PrettyLimits
+	goroutineID    bool
+	correlationID  string
+	audit          *AuditLog
+	profilerLabels bool
+	profilerCtx    context.Context
+	outputMu       *sync.Mutex
+	name           string
+	nameParts      []string
+	level          Level
+	threshold      Level
+	vmodule        VModule
+	fields         []Field
+	trusted        bool
+	wrap           int
+	collector      *Collector
+	stats          *stats
+	sequence       *uint64
+	clock          Clock
+	timeLayout     string
+	location       *time.Location
+	catalog        Catalog
+	tagColors      map[string]string
+	tagHints       map[string]TagHint
+	protocol       int
+	filters        []func(Record) bool
+	redactions     []RedactionRule
+	scrubber       *scrubber
+	scrubKey       []byte
+	renderStacks   bool
+	ring           *RingBuffer
+	errorContext   int
+	sinks          []Sink
+	syncAll        bool
+	syncLevels     map[Level]bool
+	mode           OutputMode
+	format         OutputMode
+	linePrefix     string
+	lineSuffix     string
+	lineEnd        string
+}
+
+// New creates new instance of Logger. Each line is written to the output using a single write, and loggers derived
+// from the returned one (e.g. using WithTags, or by Exec for streams of a subprocess) never write to the output at
+// the same time, so lines are never interleaved.
 func New(output io.Writer) *Logger {
 	l := &Logger{
-		output: output,
-		tags:   []string{},
-		level:  DefaultLevel,
+		mu: &sync.RWMutex{},
+		options: options{
+			output:   output,
+			tags:     []string{},
+			level:    DefaultLevel,
+			mode:     AutoMode,
+			format:   resolveMode(AutoMode, output),
+			stats:    &stats{},
+			outputMu: &sync.Mutex{},
+			clock:    systemClock{},
+			location: time.UTC,
+			protocol: ProtocolVersion,
+		},
 	}
 
 	l.updateLinePrefix()
@@ -81,21 +146,41 @@ func New(output io.Writer) *Logger {
 }
 
 func (l *Logger) updateLinePrefix() {
+	l.linePrefix, l.lineEnd = l.renderLinePrefix(l.renderedTags())
+}
+
+// renderLinePrefix returns prefix and end of lines written by a logger with specified tags.
+func (l *Logger) renderLinePrefix(tags []string) (prefix, end string) {
+	if l.format == PlainMode || l.format == ColorMode {
+		visible, colors := l.humanTags(tags)
+		return humanLinePrefix(l.level, visible, l.format == ColorMode, colors), "\n"
+	}
+
 	level, err := json.Marshal(l.level)
 	if err != nil {
 		level = []byte("\"" + DefaultLevel + "\"")
 	}
-	tags, err := json.Marshal(l.tags)
-	if err != nil || string(tags) == "null" {
-		tags = []byte("[]")
+	encodedTags, err := l.encodeTags(tags)
+	if err != nil {
+		encodedTags = []byte("[]")
 	}
-	l.linePrefix = fmt.Sprintf(
-		"\033_klio_log_level %s\033\\\033_klio_tags %s\033\\", level, tags,
+	prefix = fmt.Sprintf(
+		"\033_klio_log_level %s\033\\\033_klio_tags %s\033\\", level, encodedTags,
 	)
+	return prefix, resetSequence + "\n"
+}
+
+// clone returns a copy of a logger with its own lock.
+func (l *Logger) clone() *Logger {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return &Logger{mu: &sync.RWMutex{}, options: l.options}
 }
 
 // Tags returns tags used by a logger. Tags are prepended to each line produced by a logger.
 func (l *Logger) Tags() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
 	r := make([]string, len(l.tags))
 	copy(r, l.tags)
 	return r
@@ -103,59 +188,271 @@ func (l *Logger) Tags() []string {
 
 // Level returns log level used by a logger.
 func (l *Logger) Level() Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
 	return l.level
 }
 
 // Output returns writer used by a logger.
 func (l *Logger) Output() io.Writer {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
 	return l.output
 }
 
 // SetOutput changes Writer used to print logs. In contrast to other methods it modifies logger instance instead creating a new one.
 func (l *Logger) SetOutput(output io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	atomic.AddUint64(&l.generation, 1)
 	l.output = output
+	l.outputMu = &sync.Mutex{}
+	l.format = resolveMode(l.mode, output)
+	l.updateLinePrefix()
+}
+
+// Assign replaces all settings of a logger (level, tags, fields, output) with ones used by src. Like SetOutput, it modifies
+// logger instance. Changes are applied atomically, so concurrent calls to Print never use a mix of old and new settings.
+func (l *Logger) Assign(src *Logger) {
+	src.mu.RLock()
+	o := src.options
+	src.mu.RUnlock()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	atomic.AddUint64(&l.generation, 1)
+	l.options = o
 }
 
 // WithLevel creates new logger instance logging at specified level.
 func (l *Logger) WithLevel(level Level) *Logger {
-	n := *l
+	n := l.clone()
 	n.level = level
 	n.updateLinePrefix()
-	return &n
+	return n
 }
 
-// WithTags creates new logger instance with specified tags. Tags are prepended to each line produced by a logger.
+// WithTags creates new logger instance with specified tags, replacing tags of the current logger. Tags are prepended
+// to each line produced by a logger.
 func (l *Logger) WithTags(tags ...string) *Logger {
-	n := *l
-	n.tags = tags
+	n := l.clone()
+	n.tags = prefixTags(n.tagPrefix, tags)
 	n.updateLinePrefix()
-	return &n
+	return n
 }
 
 // Printf writes log line. Arguments are handled in the manner of fmt.Print.
 func (l *Logger) Print(v ...interface{}) *Logger {
-	line := l.linePrefix + fmt.Sprint(v...) + "\033_klio_reset\033\\\n"
-	l.output.Write([]byte(line))
+	return l.print(nil, printMessage(v...))
+}
+
+// PrintT writes log line with tags added to tags of a logger, without creating new logger instance. Arguments are
+// handled in the manner of fmt.Print.
+func (l *Logger) PrintT(tags []string, v ...interface{}) *Logger {
+	return l.print(tags, printMessage(v...))
+}
+
+// PrintfT writes log line with tags added to tags of a logger, without creating new logger instance. Arguments are
+// handled in the manner of fmt.Printf.
+func (l *Logger) PrintfT(tags []string, format string, v ...interface{}) *Logger {
+	return l.print(tags, printfMessage(format, v...))
+}
+
+// message holds arguments of Print or Printf, which are formatted only if the line is written or recorded by a ring
+// buffer.
+type message struct {
+	render func(pretty *PrettyLimits) string
+}
+
+// printMessage returns message formatted in the manner of fmt.Print, rendering complex values using the pretty printer
+// if limits are set.
+func printMessage(v ...interface{}) message {
+	return message{render: func(pretty *PrettyLimits) string {
+		if pretty != nil {
+			return safeSprint(prettyArgs(v, *pretty)...)
+		}
+		return safeSprint(v...)
+	}}
+}
+
+// printfMessage is like printMessage, but arguments are handled in the manner of fmt.Printf. Format string is passed
+// directly to safeSprintf, so go vet checks calls of Printf and other functions which use it.
+func printfMessage(format string, v ...interface{}) message {
+	return message{render: func(pretty *PrettyLimits) string {
+		if pretty != nil {
+			return safeSprintf(format, prettyArgs(v, *pretty)...)
+		}
+		return safeSprintf(format, v...)
+	}}
+}
+
+func (l *Logger) print(extraTags []string, m message) *Logger {
+	l.mu.RLock()
+	enabled, trusted, width := l.enabled(), l.trusted, l.lineWidth()
+	prefix, suffix, end, output, outputMu := l.linePrefix, l.lineSuffix, l.lineEnd, l.output, l.outputMu
+	tags, tagErr := l.lineTags(extraTags)
+	level, fields, collector, stats := l.level, l.fields, l.collector, l.stats
+	sequence, clock, timeLayout, location := l.sequence, l.clock, l.timeLayout, l.location
+	filters, redactions, scrubber := l.filters, l.redactions, l.scrubber
+	stacks := l.renderStacks && (l.format == PlainMode || l.format == ColorMode)
+	ring, errorContext, sinks := l.ring, l.errorContext, l.sinks
+	durable := l.syncAll || l.syncLevels[level]
+	pretty, goroutineID := l.pretty, l.goroutineID
+	if len(extraTags) > 0 {
+		prefix, end = l.renderLinePrefix(tags)
+	}
+	l.mu.RUnlock()
+
+	if tagErr != nil {
+		stats.record(0, tagErr)
+	}
+
+	var context []Record
+	if s, ok := levelSeverity[level]; ok && s <= levelSeverity[ErrorLevel] && ring != nil && errorContext > 0 {
+		context = ring.context(tags, errorContext)
+	}
+	if ring == nil && !enabled {
+		return l
+	}
+	msg := m.render(pretty)
+	if tagErr != nil {
+		msg += " (" + tagErr.Error() + ")"
+	}
+	if scrubber != nil {
+		fields = scrubber.scrub(fields)
+		suffix = renderFields(fields)
+	}
+	var notes map[string]bool
+	if len(redactions) > 0 {
+		notes = map[string]bool{}
+		msg = redactString(redactions, msg, notes)
+		fields = redactFields(redactions, fields, notes)
+		suffix = renderFields(fields)
+	}
+
+	if len(filters) > 0 && !accepts(filters, Record{Level: level, Tags: tags, Message: msg, Fields: fields}) {
+		return l
+	}
+	// Lines are stored after scrubbing and redaction, so they are safe to show as the context of errors
+	if ring != nil {
+		ring.add(Record{Level: level, Tags: tags, Message: msg, Fields: fields})
+	}
+	if !enabled {
+		return l
+	}
+	if !trusted {
+		msg = escapeMessage(msg)
+	}
+
+	if timeLayout != "" || sequence != nil || goroutineID {
+		// Fields of the logger are shared, so added fields are appended to a copy (allocated once for all of them)
+		extra := make([]Field, len(fields), len(fields)+3)
+		copy(extra, fields)
+		if timeLayout != "" {
+			extra = append(extra, Field{Key: TimeField, Value: clock.Now().In(location).Format(timeLayout)})
+		}
+		if sequence != nil {
+			extra = append(extra, nextSequenceField(sequence))
+		}
+		if goroutineID {
+			extra = append(extra, Field{Key: GoroutineField, Value: currentGoroutineID()})
+		}
+		suffix += renderFields(extra[len(fields):])
+		fields = extra
+	}
+
+	noteSeverity(level)
+	noteWrite()
+	if collector != nil {
+		collector.record(Record{Level: level, Tags: tags, Message: msg, Fields: fields})
+	}
+	if len(sinks) > 0 {
+		writeSinks(sinks, stats, clock.Now(), Record{Level: level, Tags: tags, Message: msg, Fields: fields})
+	}
+
+	if stacks {
+		if rest, lines := renderStacks(fields); lines != "" {
+			suffix = renderFields(rest) + lines
+		}
+	}
+
+	if len(context) > 0 {
+		suffix += renderContext(context, trusted)
+	}
+
+	buf := getLineBuffer()
+	line := append(*buf, prefix...)
+	if width > 0 {
+		line = append(line, wrapMessage(msg+suffix, width, visibleWidth(prefix))...)
+	} else {
+		line = append(append(line, msg...), suffix...)
+	}
+	line = append(line, end...)
+	// Loggers sharing the output write whole lines one at a time, so lines are never interleaved
+	outputMu.Lock()
+	if lw, ok := output.(leveledWriter); ok {
+		lw.writeLevel(level, line)
+	} else {
+		stats.write(output, line)
+	}
+	outputMu.Unlock()
+	*buf = line
+	putLineBuffer(buf)
+	if durable {
+		if err := syncOutputs(output, sinks); err != nil {
+			stats.record(0, err)
+		}
+	}
+	if len(notes) > 0 {
+		l.noteRedactions(notes)
+	}
 	return l
 }
 
 // Printf writes log line. Arguments are handled in the manner of fmt.Printf.
 func (l *Logger) Printf(format string, v ...interface{}) *Logger {
-	return l.Print(fmt.Sprintf(format, v...))
+	return l.print(nil, printfMessage(format, v...))
 }
 
-// Write prints input line by line.
+// Write prints input line by line. Lines which are already decorated with control sequences interpreted by Klio (e.g.
+// output of another Klio command) are decoded and printed using their level and tags, instead of being decorated again.
+// Lines are not limited in length.
 func (l *Logger) Write(p []byte) (int, error) {
-	scanner := bufio.NewScanner(bytes.NewReader(p)) // Scan lines
-	for scanner.Scan() {
-		l.Print(scanner.Text())
-	}
-	if err := scanner.Err(); err != nil {
-		return 0, err
+	for rest := p; len(rest) > 0; {
+		line := rest
+		if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+			line, rest = rest[:i], rest[i+1:]
+		} else {
+			rest = nil
+		}
+		if n := len(line); n > 0 && line[n-1] == '\r' {
+			line = line[:n-1]
+		}
+		if s := string(line); IsDecorated(s) {
+			l.writeDecorated(s)
+		} else {
+			l.Print(s)
+		}
 	}
 	return len(p), nil
 }
 
+// WriteRaw writes input to the output of a logger as it is, without decorating or escaping it. It can be used to pass
+// through binary payloads or content which is already decorated.
+func (l *Logger) WriteRaw(p []byte) (int, error) {
+	l.mu.RLock()
+	output, stats, outputMu := l.output, l.stats, l.outputMu
+	l.mu.RUnlock()
+
+	outputMu.Lock()
+	n, err := output.Write(p)
+	outputMu.Unlock()
+	if _, ok := output.(*asyncWriter); !ok {
+		stats.record(n, err)
+	}
+	return n, err
+}
+
 // StandardLogger returns logger instance writing to the stdout. It writes using "info" level by default.
 func StandardLogger() *Logger {
 	return standardLogger
@@ -166,72 +463,58 @@ func ErrorLogger() *Logger {
 	return errorLogger
 }
 
-// Spam writes a message at level Spam on the standard logger. Arguments are handled in the manner of fmt.Print.
-func Spam(v ...interface{}) {
-	standardLogger.WithLevel(SpamLevel).Print(v...)
-}
-
-// Debug writes a message at level Debug on the standard logger. Arguments are handled in the manner of fmt.Print.
-func Debug(v ...interface{}) {
-	standardLogger.WithLevel(DebugLevel).Print(v...)
-}
-
 // Verbose writes a message at level Verbose on the standard logger. Arguments are handled in the manner of fmt.Print.
 func Verbose(v ...interface{}) {
-	standardLogger.WithLevel(VerboseLevel).Print(v...)
+	standardLevels.get(VerboseLevel).Print(v...)
 }
 
 // Info writes a message at level Info on the standard logger. Arguments are handled in the manner of fmt.Print.
 func Info(v ...interface{}) {
-	standardLogger.WithLevel(InfoLevel).Print(v...)
+	standardLevels.get(InfoLevel).Print(v...)
 }
 
 // Warn writes a message at level Warn on the standard logger. Arguments are handled in the manner of fmt.Print.
 func Warn(v ...interface{}) {
-	standardLogger.WithLevel(WarnLevel).Print(v...)
+	standardLevels.get(WarnLevel).Print(v...)
 }
 
 // Error writes a message at level Error on the standard logger. Arguments are handled in the manner of fmt.Print.
 func Error(v ...interface{}) {
-	standardLogger.WithLevel(ErrorLevel).Print(v...)
+	standardLevels.get(ErrorLevel).Print(v...)
 }
 
-// Fatal writes a message at level Fatal on the standard logger. Arguments are handled in the manner of fmt.Print.
+// Fatal writes a message at level Fatal on the standard logger. Arguments are handled in the manner of fmt.Print. It
+// terminates the program if SetExitOnFatal was enabled.
 func Fatal(v ...interface{}) {
-	standardLogger.WithLevel(FatalLevel).Print(v...)
-}
-
-// Spamf writes a message at level Spam on the standard logger. Arguments are handled in the manner of fmt.Printf.
-func Spamf(format string, v ...interface{}) {
-	standardLogger.WithLevel(SpamLevel).Printf(format, v...)
-}
-
-// Debugf writes a message at level Debug on the standard logger. Arguments are handled in the manner of fmt.Printf.
-func Debugf(format string, v ...interface{}) {
-	standardLogger.WithLevel(DebugLevel).Printf(format, v...)
+	l := standardLevels.get(FatalLevel)
+	l.Print(v...)
+	exitIfFatal(l.newRecord(safeSprint(v...)))
 }
 
 // Verbosef writes a message at level Verbose on the standard logger. Arguments are handled in the manner of fmt.Printf.
 func Verbosef(format string, v ...interface{}) {
-	standardLogger.WithLevel(VerboseLevel).Printf(format, v...)
+	standardLevels.get(VerboseLevel).Printf(format, v...)
 }
 
 // Infof writes a message at level Info on the standard logger. Arguments are handled in the manner of fmt.Printf.
 func Infof(format string, v ...interface{}) {
-	standardLogger.WithLevel(InfoLevel).Printf(format, v...)
+	standardLevels.get(InfoLevel).Printf(format, v...)
 }
 
 // Warnf writes a message at level Warn on the standard logger. Arguments are handled in the manner of fmt.Printf.
 func Warnf(format string, v ...interface{}) {
-	standardLogger.WithLevel(WarnLevel).Printf(format, v...)
+	standardLevels.get(WarnLevel).Printf(format, v...)
 }
 
 // Errorf writes a message at level Error on the standard logger. Arguments are handled in the manner of fmt.Printf.
 func Errorf(format string, v ...interface{}) {
-	standardLogger.WithLevel(ErrorLevel).Printf(format, v...)
+	standardLevels.get(ErrorLevel).Printf(format, v...)
 }
 
-// Fatalf writes a message at level Fatal on the standard logger. Arguments are handled in the manner of fmt.Printf.
+// Fatalf writes a message at level Fatal on the standard logger. Arguments are handled in the manner of fmt.Printf. It
+// terminates the program if SetExitOnFatal was enabled.
 func Fatalf(format string, v ...interface{}) {
-	standardLogger.WithLevel(FatalLevel).Printf(format, v...)
+	l := standardLevels.get(FatalLevel)
+	l.Printf(format, v...)
+	exitIfFatal(l.newRecord(safeSprintf(format, v...)))
 }
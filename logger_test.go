@@ -2,8 +2,12 @@ package logger_test
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"os"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -157,6 +161,41 @@ func TestPrintf(t *testing.T) {
 	assert.Equal(t, "\033_klio_log_level \"info\"\033\\\033_klio_tags []\033\\foo\033_klio_reset\033\\\n", b.String())
 }
 
+func TestPrintT(t *testing.T) {
+	t.Run("add tags to single line", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithTags("deploy")
+
+		l.PrintT([]string{"api"}, "foo")
+		l.PrintfT([]string{"worker"}, "%s", "bar")
+		l.Print("baz")
+
+		assert.Equal(t, "\033_klio_log_level \"info\"\033\\\033_klio_tags [\"deploy\",\"api\"]\033\\foo\033_klio_reset\033\\\n"+
+			"\033_klio_log_level \"info\"\033\\\033_klio_tags [\"deploy\",\"worker\"]\033\\bar\033_klio_reset\033\\\n"+
+			"\033_klio_log_level \"info\"\033\\\033_klio_tags [\"deploy\"]\033\\baz\033_klio_reset\033\\\n", b.String())
+	})
+
+	t.Run("render tags in human readable mode", func(t *testing.T) {
+		var b bytes.Buffer
+
+		log.New(&b).WithOutputMode(log.PlainMode).WithLevel(log.WarnLevel).PrintT([]string{"api"}, "foo")
+
+		assert.Equal(t, "[WARN][API] foo\n", b.String())
+	})
+
+	t.Run("pass tags to filters", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithTags("deploy").WithFilter(func(r log.Record) bool {
+			return len(r.Tags) == 2
+		})
+
+		l.PrintT(nil, "foo")
+		l.PrintT([]string{"api"}, "bar")
+
+		assert.Equal(t, "\033_klio_log_level \"info\"\033\\\033_klio_tags [\"deploy\",\"api\"]\033\\bar\033_klio_reset\033\\\n", b.String())
+	})
+}
+
 func TestSetOutput(t *testing.T) {
 	var b1 bytes.Buffer
 	var b2 bytes.Buffer
@@ -201,6 +240,62 @@ func TestWriter(t *testing.T) {
 	)
 }
 
+func TestWriterWithDecoratedLines(t *testing.T) {
+	var b bytes.Buffer
+	var w io.Writer = log.New(&b).WithTags("a")
+
+	_, err := w.Write([]byte("\033_klio_log_level \"warn\"\033\\\033_klio_tags [\"b\"]\033\\foo\033_klio_reset\033\\\nbar\n"))
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"\033_klio_log_level \"warn\"\033\\\033_klio_tags [\"b\"]\033\\foo\033_klio_reset\033\\\n"+
+			"\033_klio_log_level \"info\"\033\\\033_klio_tags [\"a\"]\033\\bar\033_klio_reset\033\\\n",
+		b.String(),
+	)
+}
+
+func TestWriterLongLines(t *testing.T) {
+	var b bytes.Buffer
+	long := strings.Repeat("x", 100<<10)
+
+	n, err := log.New(&b).WithOutputMode(log.PlainMode).Write([]byte(long + "\r\n\nfoo"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, len(long)+6, n)
+	assert.Equal(t, "[INFO] "+long+"\n[INFO] \n[INFO] foo\n", b.String())
+}
+
+func BenchmarkWrite(b *testing.B) {
+	l := log.New(io.Discard)
+	p := []byte(strings.Repeat("some output of a command\n", 16))
+	b.ReportAllocs()
+	b.SetBytes(int64(len(p)))
+	for i := 0; i < b.N; i++ {
+		l.Write(p)
+	}
+}
+
+func BenchmarkWriteSingleLine(b *testing.B) {
+	l := log.New(io.Discard)
+	p := []byte("some output of a command\n")
+	b.ReportAllocs()
+	b.SetBytes(int64(len(p)))
+	for i := 0; i < b.N; i++ {
+		l.Write(p)
+	}
+}
+
+func TestWriteRaw(t *testing.T) {
+	var b bytes.Buffer
+
+	n, err := log.New(&b).WithTags("a").WriteRaw([]byte("foo\033_klio_reset\033\\\nbar"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 21, n)
+	assert.Equal(t, "foo\033_klio_reset\033\\\nbar", b.String())
+}
+
 func TestConvenienceFunctions(t *testing.T) {
 	var b bytes.Buffer
 
@@ -208,12 +303,18 @@ func TestConvenienceFunctions(t *testing.T) {
 	defer log.StandardLogger().SetOutput(os.Stdout)
 
 	t.Run("Spam", func(t *testing.T) {
+		if !log.DebugEnabled {
+			t.Skip("disabled by kliolog_nodebug build tag")
+		}
 		b.Reset()
 		log.Spam("foo")
 		assert.Equal(t, "\033_klio_log_level \"spam\"\033\\\033_klio_tags []\033\\foo\033_klio_reset\033\\\n", b.String())
 	})
 
 	t.Run("Debug", func(t *testing.T) {
+		if !log.DebugEnabled {
+			t.Skip("disabled by kliolog_nodebug build tag")
+		}
 		b.Reset()
 		log.Debug("foo")
 		assert.Equal(t, "\033_klio_log_level \"debug\"\033\\\033_klio_tags []\033\\foo\033_klio_reset\033\\\n", b.String())
@@ -250,12 +351,18 @@ func TestConvenienceFunctions(t *testing.T) {
 	})
 
 	t.Run("Spamf", func(t *testing.T) {
+		if !log.DebugEnabled {
+			t.Skip("disabled by kliolog_nodebug build tag")
+		}
 		b.Reset()
 		log.Spamf("%s", "foo")
 		assert.Equal(t, "\033_klio_log_level \"spam\"\033\\\033_klio_tags []\033\\foo\033_klio_reset\033\\\n", b.String())
 	})
 
 	t.Run("Debugf", func(t *testing.T) {
+		if !log.DebugEnabled {
+			t.Skip("disabled by kliolog_nodebug build tag")
+		}
 		b.Reset()
 		log.Debugf("%s", "foo")
 		assert.Equal(t, "\033_klio_log_level \"debug\"\033\\\033_klio_tags []\033\\foo\033_klio_reset\033\\\n", b.String())
@@ -291,3 +398,111 @@ func TestConvenienceFunctions(t *testing.T) {
 		assert.Equal(t, "\033_klio_log_level \"fatal\"\033\\\033_klio_tags []\033\\foo\033_klio_reset\033\\\n", b.String())
 	})
 }
+
+func TestAssign(t *testing.T) {
+	var b1 bytes.Buffer
+	var b2 bytes.Buffer
+
+	l := log.New(&b1)
+	l.Assign(log.New(&b2).WithTags("a").WithLevel(log.WarnLevel))
+	l.Print("foo")
+
+	assert.Equal(t, log.WarnLevel, l.Level())
+	assert.Equal(t, []string{"a"}, l.Tags())
+	assert.Equal(t, "", b1.String())
+	assert.Equal(t, "\033_klio_log_level \"warn\"\033\\\033_klio_tags [\"a\"]\033\\foo\033_klio_reset\033\\\n", b2.String())
+}
+
+// bytewiseWriter writes data one byte at a time, yielding in between, so concurrent writes get interleaved.
+type bytewiseWriter struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (w *bytewiseWriter) Write(p []byte) (int, error) {
+	for _, c := range p {
+		w.mu.Lock()
+		w.b.WriteByte(c)
+		w.mu.Unlock()
+		runtime.Gosched()
+	}
+	return len(p), nil
+}
+
+func TestLineAtomicWrites(t *testing.T) {
+	w := &bytewiseWriter{}
+	l := log.New(w)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			view := l.WithTags(fmt.Sprint("worker", i))
+			for j := 0; j < 20; j++ {
+				if j%2 == 0 {
+					view.Printf("line %d", j)
+				} else {
+					view.Write([]byte(fmt.Sprintf("line %d\n", j)))
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(w.b.String(), "\n"), "\n")
+	assert.Len(t, lines, 160)
+	for _, line := range lines {
+		r, ok := log.ParseLine(line)
+		assert.True(t, ok, line)
+		assert.Regexp(t, `^line \d+$`, r.Message)
+	}
+}
+
+func TestPrintAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations are not measurable with the race detector")
+	}
+	l := log.New(io.Discard).WithTags("a").WithFields(log.Field{Key: "b", Value: 1})
+
+	assert.LessOrEqual(t, testing.AllocsPerRun(100, func() { l.Print("foo") }), 1.0)
+	assert.LessOrEqual(t, testing.AllocsPerRun(100, func() { l.Printf("foo %d", 1) }), 1.0)
+}
+
+func TestStandardLoggerModifiedInPlace(t *testing.T) {
+	var b1, b2 bytes.Buffer
+	l := log.StandardLogger()
+	saved := log.New(nil)
+	saved.Assign(l)
+	defer l.Assign(saved)
+
+	l.SetOutput(&b1)
+	log.Warn("foo")
+	l.Assign(log.New(&b2).WithTags("a"))
+	log.Warn("bar")
+
+	assert.Equal(t, "\033_klio_log_level \"warn\"\033\\\033_klio_tags []\033\\foo\033_klio_reset\033\\\n", b1.String())
+	assert.Equal(t, "\033_klio_log_level \"warn\"\033\\\033_klio_tags [\"a\"]\033\\bar\033_klio_reset\033\\\n", b2.String())
+}
+
+func BenchmarkStandardLoggerParallel(b *testing.B) {
+	log.StandardLogger().SetOutput(io.Discard)
+	defer log.StandardLogger().SetOutput(os.Stdout)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			log.Info("foo")
+		}
+	})
+}
+
+func BenchmarkStandardLoggerParallelDisabled(b *testing.B) {
+	log.StandardLogger().SetOutput(io.Discard)
+	defer log.StandardLogger().SetOutput(os.Stdout)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			log.Verbosef("foo %d", 1)
+		}
+	})
+}
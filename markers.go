@@ -0,0 +1,56 @@
+package logger
+
+const (
+	// SuccessMarker is prepended to messages written by Logger.Success.
+	SuccessMarker = "✓"
+	// FailureMarker is prepended to messages written by Logger.Failure.
+	FailureMarker = "✗"
+	// SkippedMarker is prepended to messages written by Logger.Skipped.
+	SkippedMarker = "-"
+
+	successColor = "\033[32m"
+	failureColor = "\033[31m"
+	skippedColor = "\033[90m"
+)
+
+// Success writes a message about successfully finished work at info level, prepended with SuccessMarker. Arguments are
+// handled in the manner of fmt.Print.
+func (l *Logger) Success(v ...interface{}) *Logger {
+	return l.mark(InfoLevel, SuccessMarker, successColor, safeSprint(v...))
+}
+
+// Failure writes a message about failed work at error level, prepended with FailureMarker. Arguments are handled in the
+// manner of fmt.Print.
+func (l *Logger) Failure(v ...interface{}) *Logger {
+	return l.mark(ErrorLevel, FailureMarker, failureColor, safeSprint(v...))
+}
+
+// Skipped writes a message about skipped work at info level, prepended with SkippedMarker. Arguments are handled in the
+// manner of fmt.Print.
+func (l *Logger) Skipped(v ...interface{}) *Logger {
+	return l.mark(InfoLevel, SkippedMarker, skippedColor, safeSprint(v...))
+}
+
+// Successf is like Success, but arguments are handled in the manner of fmt.Printf.
+func (l *Logger) Successf(format string, v ...interface{}) *Logger {
+	return l.Success(safeSprintf(format, v...))
+}
+
+// Failuref is like Failure, but arguments are handled in the manner of fmt.Printf.
+func (l *Logger) Failuref(format string, v ...interface{}) *Logger {
+	return l.Failure(safeSprintf(format, v...))
+}
+
+// Skippedf is like Skipped, but arguments are handled in the manner of fmt.Printf.
+func (l *Logger) Skippedf(format string, v ...interface{}) *Logger {
+	return l.Skipped(safeSprintf(format, v...))
+}
+
+// mark writes the message prepended with the marker, which is colored in ColorMode.
+func (l *Logger) mark(level Level, marker, color, msg string) *Logger {
+	l = l.WithLevel(level)
+	l.mu.RLock()
+	colored := l.format == ColorMode
+	l.mu.RUnlock()
+	return l.Print(colorize(marker, color, colored) + " " + msg)
+}
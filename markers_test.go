@@ -0,0 +1,52 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestMarkers(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     log.OutputMode
+		write    func(l *log.Logger)
+		expected string
+	}{
+		{
+			name:     "success",
+			mode:     log.PlainMode,
+			write:    func(l *log.Logger) { l.Success("Deployed ", "api") },
+			expected: "[INFO] ✓ Deployed api\n",
+		},
+		{
+			name:     "failure",
+			mode:     log.PlainMode,
+			write:    func(l *log.Logger) { l.Failuref("Deploying %s", "api") },
+			expected: "[ERROR] ✗ Deploying api\n",
+		},
+		{
+			name:     "skipped",
+			mode:     log.PlainMode,
+			write:    func(l *log.Logger) { l.Skipped("Migrations") },
+			expected: "[INFO] - Migrations\n",
+		},
+		{
+			name:     "colored success",
+			mode:     log.ColorMode,
+			write:    func(l *log.Logger) { l.Successf("Deployed %s", "api") },
+			expected: "[INFO] \033[32m✓\033[0m Deployed api\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var b bytes.Buffer
+			test.write(log.New(&b).WithOutputMode(test.mode))
+			assert.Equal(t, test.expected, b.String())
+		})
+	}
+}
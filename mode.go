@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// EnvMode is the name of the environment variable overriding output mode of loggers using AutoMode.
+const EnvMode = "KLIO_LOG_MODE"
+
+// OutputMode describes format of lines written by a logger.
+type OutputMode string
+
+const (
+	// AutoMode selects output mode based on the environment, see Mode.
+	AutoMode OutputMode = "auto"
+	// KlioMode writes lines decorated with control sequences interpreted by Klio.
+	KlioMode OutputMode = "klio"
+	// PlainMode writes human readable lines, e.g. "[WARN][FOO] message".
+	PlainMode OutputMode = "plain"
+	// ColorMode writes human readable lines with levels highlighted using ANSI colors.
+	ColorMode OutputMode = "color"
+)
+
+// IsKlio reports whether the process was started by Klio or by a Klio-aware parent, which is detected by presence of
+// KLIO_LOG_PROTOCOL or KLIO_LOG_LEVEL environment variables (see ChildEnv).
+func IsKlio() bool {
+	for _, key := range []string{EnvProtocol, EnvLevel} {
+		if _, ok := os.LookupEnv(key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal reports whether the writer is a terminal.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(interface{ Fd() uintptr })
+	return ok && isTerminal(f.Fd())
+}
+
+// Mode returns output mode used by loggers writing to the stdout in AutoMode:
+//   - mode set using KLIO_LOG_MODE environment variable ("klio", "plain" or "color"), if any,
+//   - KlioMode, if the process was started by Klio (see IsKlio),
+//   - ColorMode, if the stdout is a terminal (PlainMode if NO_COLOR environment variable is set or the terminal doesn't
+//     interpret ANSI escape sequences, e.g. legacy Windows console without virtual terminal processing),
+//   - KlioMode otherwise.
+//
+// Commands can use it to make presentation decisions (e.g. whether to show interactive prompts) consistently with
+// loggers.
+func Mode() OutputMode {
+	return resolveMode(AutoMode, os.Stdout)
+}
+
+func resolveMode(mode OutputMode, output io.Writer) OutputMode {
+	if mode != AutoMode && mode != "" {
+		return mode
+	}
+	switch m := OutputMode(strings.ToLower(os.Getenv(EnvMode))); m {
+	case KlioMode, PlainMode, ColorMode:
+		return m
+	}
+	if IsKlio() {
+		return KlioMode
+	}
+	if f, ok := output.(interface{ Fd() uintptr }); ok && isTerminal(f.Fd()) {
+		if _, ok := os.LookupEnv("NO_COLOR"); ok || !supportsANSI(f.Fd()) {
+			return PlainMode
+		}
+		return ColorMode
+	}
+	return KlioMode
+}
+
+// OutputMode returns output mode used by a logger. AutoMode is resolved based on the output of a logger, see Mode.
+func (l *Logger) OutputMode() OutputMode {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.format
+}
+
+// WithOutputMode creates new logger instance using specified output mode. By default loggers use AutoMode.
+func (l *Logger) WithOutputMode(mode OutputMode) *Logger {
+	n := l.clone()
+	n.mode = mode
+	n.format = resolveMode(mode, n.output)
+	n.updateLinePrefix()
+	return n
+}
+
+var levelColors = map[Level]string{
+	FatalLevel:   "\033[1;31m",
+	ErrorLevel:   "\033[31m",
+	WarnLevel:    "\033[33m",
+	VerboseLevel: "\033[36m",
+	DebugLevel:   "\033[90m",
+	SpamLevel:    "\033[90m",
+}
+
+// humanLinePrefix returns prefix of lines written in PlainMode and ColorMode, e.g. "[WARN][FOO] ". In ColorMode tags
+// are colored using tagColors overrides or colors based on their names.
+func humanLinePrefix(level Level, tags []string, color bool, tagColors map[string]string) string {
+	var b strings.Builder
+	if c, ok := levelColors[level]; ok && color {
+		b.WriteString(c + "[" + strings.ToUpper(string(level)) + "]\033[0m")
+	} else {
+		b.WriteString("[" + strings.ToUpper(string(level)) + "]")
+	}
+	for _, tag := range tags {
+		if color {
+			b.WriteString(tagColor(tag, tagColors) + "[" + strings.ToUpper(tag) + "]\033[0m")
+		} else {
+			b.WriteString("[" + strings.ToUpper(tag) + "]")
+		}
+	}
+	b.WriteByte(' ')
+	return b.String()
+}
@@ -0,0 +1,63 @@
+package logger_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestIsTerminal(t *testing.T) {
+	var b bytes.Buffer
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	assert.NoError(t, err)
+	defer f.Close()
+
+	assert.False(t, log.IsTerminal(&b))
+	assert.False(t, log.IsTerminal(f))
+}
+
+func TestIsKlio(t *testing.T) {
+	os.Setenv(log.EnvProtocol, "1")
+	defer os.Unsetenv(log.EnvProtocol)
+
+	assert.True(t, log.IsKlio())
+}
+
+func TestMode(t *testing.T) {
+	os.Setenv(log.EnvMode, "plain")
+	defer os.Unsetenv(log.EnvMode)
+
+	assert.Equal(t, log.PlainMode, log.Mode())
+	assert.Equal(t, log.PlainMode, log.New(&bytes.Buffer{}).OutputMode())
+	assert.Equal(t, log.ColorMode, log.New(&bytes.Buffer{}).WithOutputMode(log.ColorMode).OutputMode())
+}
+
+func TestWithOutputMode(t *testing.T) {
+	t.Run("write lines in klio mode by default", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b)
+		l.Print("foo")
+		assert.Equal(t, log.KlioMode, l.OutputMode())
+		assert.Equal(t, "\033_klio_log_level \"info\"\033\\\033_klio_tags []\033\\foo\033_klio_reset\033\\\n", b.String())
+	})
+
+	t.Run("write lines in plain mode", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.PlainMode).WithName("cmd").WithTags("a").WithLevel(log.WarnLevel)
+		l.WithField("x", 1).Print("foo")
+		l.Write([]byte("\033_klio_log_level \"error\"\033\\\033_klio_tags [\"b\"]\033\\bar\033_klio_reset\033\\\n"))
+		assert.Equal(t, "[WARN][CMD][A] foo x=1\n[ERROR][CMD][B] bar\n", b.String())
+	})
+
+	t.Run("write lines in color mode", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.ColorMode)
+		l.WithLevel(log.ErrorLevel).Print("foo")
+		l.Print("bar")
+		assert.Equal(t, "\033[31m[ERROR]\033[0m foo\n[INFO] bar\n", b.String())
+	})
+}
@@ -0,0 +1,35 @@
+package logger
+
+import "strings"
+
+// Name returns name of a logger, empty if logger has no name.
+func (l *Logger) Name() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.name
+}
+
+// WithName creates new logger instance with the name appended to the name of the current logger, separated by a dot
+// (e.g. WithName("deploy").WithName("helm") results in "deploy.helm"). Name is prepended to tags of each line produced
+// by a logger.
+func (l *Logger) WithName(name string) *Logger {
+	n := l.clone()
+	if n.name == "" {
+		n.name = name
+	} else if name != "" {
+		n.name += "." + name
+	}
+	// Components are matched by vmodule rules, so they are split once instead of on every line
+	n.nameParts = nil
+	if n.name != "" {
+		n.nameParts = strings.Split(n.name, ".")
+	}
+	n.updateLinePrefix()
+	return n
+}
+
+// renderedTags returns tags written to the output.
+func (l *Logger) renderedTags() []string {
+	tags, _ := l.lineTags(nil)
+	return tags
+}
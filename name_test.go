@@ -0,0 +1,33 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestWithName(t *testing.T) {
+	var b bytes.Buffer
+
+	l1 := log.New(&b)
+	l2 := l1.WithName("deploy")
+	l3 := l2.WithName("helm")
+	l4 := l3.WithName("")
+
+	assert.Equal(t, "", l1.Name())
+	assert.Equal(t, "deploy", l2.Name())
+	assert.Equal(t, "deploy.helm", l3.Name())
+	assert.Equal(t, "deploy.helm", l4.Name())
+}
+
+func TestPrintWithName(t *testing.T) {
+	var b bytes.Buffer
+	l := log.New(&b).WithName("deploy").WithTags("a").WithName("helm")
+	l.Print("foo")
+
+	assert.Equal(t, []string{"a"}, l.Tags())
+	assert.Equal(t, "\033_klio_log_level \"info\"\033\\\033_klio_tags [\"deploy.helm\",\"a\"]\033\\foo\033_klio_reset\033\\\n", b.String())
+}
@@ -0,0 +1,19 @@
+//go:build kliolog_nodebug
+
+package logger
+
+// DebugEnabled is false if the package was built with the kliolog_nodebug build tag. In such case Spam, Debug, Spamf
+// and Debugf functions do nothing, and code guarded with "if logger.DebugEnabled" is removed by the compiler.
+const DebugEnabled = false
+
+// Spam does nothing, because the package was built with the kliolog_nodebug build tag.
+func Spam(v ...interface{}) {}
+
+// Debug does nothing, because the package was built with the kliolog_nodebug build tag.
+func Debug(v ...interface{}) {}
+
+// Spamf does nothing, because the package was built with the kliolog_nodebug build tag.
+func Spamf(format string, v ...interface{}) {}
+
+// Debugf does nothing, because the package was built with the kliolog_nodebug build tag.
+func Debugf(format string, v ...interface{}) {}
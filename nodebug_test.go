@@ -0,0 +1,28 @@
+//go:build kliolog_nodebug
+
+package logger_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestNoDebug(t *testing.T) {
+	var b bytes.Buffer
+
+	log.StandardLogger().SetOutput(&b)
+	defer log.StandardLogger().SetOutput(os.Stdout)
+
+	log.Spam("foo")
+	log.Debug("foo")
+	log.Spamf("%s", "foo")
+	log.Debugf("%s", "foo")
+
+	assert.False(t, log.DebugEnabled)
+	assert.Equal(t, "", b.String())
+}
@@ -0,0 +1,240 @@
+package logger
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DroppedNoticeInterval is the least interval between notices about log lines dropped by asynchronous loggers.
+var DroppedNoticeInterval = 10 * time.Second
+
+// QueuePolicy decides what asynchronous logger does when its queue is full.
+type QueuePolicy int
+
+const (
+	// DropNewest drops lines which don't fit in the queue.
+	DropNewest QueuePolicy = iota
+	// DropOldest drops the oldest queued line to make room for the new one.
+	DropOldest
+	// Block waits until there is room in the queue, so no lines are lost.
+	Block
+)
+
+// AsyncOption configures asynchronous logger, see Logger.WithAsync.
+type AsyncOption func(*asyncWriter)
+
+// WithQueuePolicy sets what happens when the queue is full. Defaults to DropNewest.
+func WithQueuePolicy(policy QueuePolicy) AsyncOption {
+	return func(a *asyncWriter) {
+		a.policy = policy
+	}
+}
+
+// WithDropBelow makes lines less severe than level dropped when the queue is full, regardless of the queue policy. It
+// can be combined with Block policy to never lose important lines, while not waiting because of less important ones.
+func WithDropBelow(level Level) AsyncOption {
+	return func(a *asyncWriter) {
+		a.dropBelow = level
+	}
+}
+
+// WithAsync creates new logger instance writing in the background. Lines are queued and if the queue is full, they are
+// handled according to the queue policy (see WithQueuePolicy). Dropped lines are reported by a "Dropped N log lines"
+// warning written later. Queued lines are written by Flush. Since each call starts a new goroutine, the logger should
+// be created once and shared, and closed when no longer needed (see Close).
+func (l *Logger) WithAsync(queueSize int, opts ...AsyncOption) *Logger {
+	n := l.clone()
+	a := &asyncWriter{
+		w:        n.output,
+		outputMu: n.outputMu,
+		stats:    n.stats,
+		notice:   n.WithLevel(WarnLevel),
+		queue:    make(chan asyncLine, queueSize),
+		flush:    make(chan chan struct{}),
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	a.unregister = registerFlusher(a.Flush)
+	go a.run()
+	n.output = a
+	// Lines are only queued while holding the lock of the logger, the output is locked by the background goroutine
+	n.outputMu = &sync.Mutex{}
+	return n
+}
+
+// Close stops background writing of a logger created using WithAsync or WithNonBlocking (and of loggers derived from
+// it): queued lines are written and the goroutine is stopped. Lines written later are written synchronously. The output
+// itself is not closed. For other loggers Close does nothing.
+func (l *Logger) Close() error {
+	l.mu.RLock()
+	output := l.output
+	l.mu.RUnlock()
+	if a, ok := output.(*asyncWriter); ok {
+		a.close()
+	}
+	return nil
+}
+
+// WithNonBlocking creates new logger instance which never blocks on writing, it is a shorthand for WithAsync using
+// DropNewest policy.
+func (l *Logger) WithNonBlocking(queueSize int) *Logger {
+	return l.WithAsync(queueSize, WithQueuePolicy(DropNewest))
+}
+
+// leveledWriter is implemented by outputs which handle lines differently depending on their level.
+type leveledWriter interface {
+	writeLevel(level Level, p []byte) (int, error)
+}
+
+type asyncLine struct {
+	level Level
+	data  []byte
+}
+
+type asyncWriter struct {
+	w          io.Writer
+	outputMu   *sync.Mutex
+	stats      *stats
+	notice     *Logger
+	policy     QueuePolicy
+	dropBelow  Level
+	queue      chan asyncLine
+	flush      chan chan struct{}
+	stop       chan struct{}
+	stopped    chan struct{}
+	unregister func()
+	dropped    int64
+
+	mu     sync.Mutex
+	closed bool
+	// active counts writes and flushes in progress, Close waits for them before stopping the goroutine
+	active sync.WaitGroup
+}
+
+func (a *asyncWriter) Write(p []byte) (int, error) {
+	return a.writeLevel("", p)
+}
+
+func (a *asyncWriter) writeLevel(level Level, p []byte) (int, error) {
+	if !a.begin() {
+		a.write(p)
+		return len(p), nil
+	}
+	defer a.active.Done()
+
+	line := asyncLine{level: level, data: append([]byte(nil), p...)}
+	for {
+		select {
+		case a.queue <- line:
+			return len(p), nil
+		default:
+		}
+
+		switch {
+		case a.dropBelow != "" && !level.Enabled(a.dropBelow):
+			a.drop()
+			return len(p), nil
+		case a.policy == Block:
+			a.queue <- line
+			return len(p), nil
+		case a.policy == DropOldest:
+			select {
+			case <-a.queue:
+				a.drop()
+			default:
+			}
+		default:
+			a.drop()
+			return len(p), nil
+		}
+	}
+}
+
+// begin registers a write or flush in progress. It returns false if the writer is closed.
+func (a *asyncWriter) begin() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return false
+	}
+	a.active.Add(1)
+	return true
+}
+
+// Flush waits until queued lines are written.
+func (a *asyncWriter) Flush() {
+	if !a.begin() {
+		return
+	}
+	defer a.active.Done()
+	done := make(chan struct{})
+	a.flush <- done
+	<-done
+}
+
+// close writes queued lines and stops the goroutine.
+func (a *asyncWriter) close() {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return
+	}
+	a.closed = true
+	a.mu.Unlock()
+
+	a.active.Wait()
+	close(a.stop)
+	<-a.stopped
+	a.unregister()
+}
+
+func (a *asyncWriter) run() {
+	defer close(a.stopped)
+	ticker := time.NewTicker(DroppedNoticeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case line := <-a.queue:
+			a.write(line.data)
+		case <-ticker.C:
+			a.reportDropped()
+		case done := <-a.flush:
+			a.drain()
+			close(done)
+		case <-a.stop:
+			a.drain()
+			return
+		}
+	}
+}
+
+// drain writes queued lines and reports dropped ones.
+func (a *asyncWriter) drain() {
+	for len(a.queue) > 0 {
+		a.write((<-a.queue).data)
+	}
+	a.reportDropped()
+}
+
+// write writes the line holding the lock of the output, so it isn't interleaved with lines of other loggers.
+func (a *asyncWriter) write(p []byte) {
+	a.outputMu.Lock()
+	defer a.outputMu.Unlock()
+	a.stats.write(a.w, p)
+}
+
+func (a *asyncWriter) drop() {
+	atomic.AddInt64(&a.dropped, 1)
+	atomic.AddInt64(&a.stats.dropped, 1)
+}
+
+func (a *asyncWriter) reportDropped() {
+	if n := atomic.SwapInt64(&a.dropped, 0); n > 0 {
+		a.notice.Printf("Dropped %d log lines", n)
+	}
+}
@@ -0,0 +1,156 @@
+package logger_test
+
+import (
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestWithNonBlocking(t *testing.T) {
+	t.Run("write queued lines", func(t *testing.T) {
+		var b syncBuffer
+		l := log.New(&b).WithOutputMode(log.PlainMode).WithNonBlocking(10)
+
+		l.Print("foo")
+		l.Print("bar")
+		log.Flush()
+
+		assert.Equal(t, "[INFO] foo\n[INFO] bar\n", b.String())
+	})
+
+	t.Run("drop lines when queue is full", func(t *testing.T) {
+		b := newBlockingWriter()
+		defer b.unblock()
+		l := log.New(b).WithOutputMode(log.PlainMode).WithNonBlocking(2)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 10; i++ {
+				l.Print("line")
+			}
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("logger is blocked")
+		}
+
+		b.unblock()
+		log.Flush()
+
+		assert.Regexp(t, regexp.MustCompile(`^(\[INFO\] line\n){2,3}\[WARN\] Dropped [78] log lines\n$`), b.String())
+	})
+}
+
+func TestWithAsync(t *testing.T) {
+	t.Run("drop oldest", func(t *testing.T) {
+		b := newBlockingWriter()
+		defer b.unblock()
+		l := log.New(b).WithOutputMode(log.PlainMode).WithAsync(2, log.WithQueuePolicy(log.DropOldest))
+
+		l.Print("first")
+		time.Sleep(20 * time.Millisecond) // Let the background goroutine take the first line, which blocks it
+		for _, msg := range []string{"a", "b", "c", "d"} {
+			l.Print(msg)
+		}
+
+		b.unblock()
+		log.Flush()
+
+		assert.Equal(t, "[INFO] first\n[INFO] c\n[INFO] d\n[WARN] Dropped 2 log lines\n", b.String())
+	})
+
+	t.Run("block", func(t *testing.T) {
+		b := newBlockingWriter()
+		defer b.unblock()
+		l := log.New(b).WithOutputMode(log.PlainMode).WithAsync(1, log.WithQueuePolicy(log.Block))
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 5; i++ {
+				l.Print("line")
+			}
+		}()
+
+		select {
+		case <-done:
+			t.Fatal("logger is not blocked")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		b.unblock()
+		<-done
+		log.Flush()
+
+		assert.Equal(t, "[INFO] line\n[INFO] line\n[INFO] line\n[INFO] line\n[INFO] line\n", b.String())
+	})
+
+	t.Run("drop below level", func(t *testing.T) {
+		b := newBlockingWriter()
+		defer b.unblock()
+		l := log.New(b).WithOutputMode(log.PlainMode).
+			WithAsync(1, log.WithQueuePolicy(log.DropOldest), log.WithDropBelow(log.WarnLevel))
+
+		l.Print("first")
+		time.Sleep(20 * time.Millisecond)
+		l.Print("queued")
+		l.Print("info")
+		l.WithLevel(log.ErrorLevel).Print("error")
+
+		b.unblock()
+		log.Flush()
+
+		assert.Equal(t, "[INFO] first\n[ERROR] error\n[WARN] Dropped 2 log lines\n", b.String())
+	})
+}
+
+func TestAsyncClose(t *testing.T) {
+	var b syncBuffer
+	before := runtime.NumGoroutine()
+	l := log.New(&b).WithOutputMode(log.PlainMode).WithNonBlocking(10)
+
+	l.Print("foo")
+	assert.NoError(t, l.WithTags("a").Close())
+	assert.Equal(t, "[INFO] foo\n", b.String())
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+
+	l.Print("bar")
+	assert.Equal(t, "[INFO] foo\n[INFO] bar\n", b.String())
+	assert.NoError(t, l.Close())
+	log.Flush()
+}
+
+func TestAsyncAtomicWrites(t *testing.T) {
+	w := &bytewiseWriter{}
+	l := log.New(w)
+	async := l.WithNonBlocking(100)
+	defer async.Close()
+
+	var wg sync.WaitGroup
+	for _, view := range []*log.Logger{l.WithTags("sync"), async.WithTags("async")} {
+		wg.Add(1)
+		go func(view *log.Logger) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				view.Printf("line %d", i)
+			}
+		}(view)
+	}
+	wg.Wait()
+	async.Close()
+
+	lines := strings.Split(strings.TrimSuffix(w.b.String(), "\n"), "\n")
+	assert.Len(t, lines, 40)
+	for _, line := range lines {
+		assert.Regexp(t, regexp.MustCompile(`^\033_klio_log_level "info"\033\\\033_klio_tags \["(a?sync)"\]\033\\line \d+\033_klio_reset\033\\$`), line)
+	}
+}
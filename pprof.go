@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// WithProfilerLabels creates new logger instance setting pprof labels of the current goroutine while its steps (see
+// Step) are running: "component" (name of a logger, see WithName) and "step" (name of the step), so CPU profiles of a
+// command can be correlated with its steps. Labels are restored when the step is done. Work done in other goroutines
+// should be wrapped with ProfileDo.
+func (l *Logger) WithProfilerLabels() *Logger {
+	n := l.clone()
+	n.profilerLabels = true
+	if n.profilerCtx == nil {
+		n.profilerCtx = context.Background()
+	}
+	return n
+}
+
+// ProfileDo calls f with pprof labels "component" (name of a logger) and "step" set for the current goroutine, see
+// pprof.Do. Goroutines started by f inherit the labels.
+func (l *Logger) ProfileDo(ctx context.Context, step string, f func(ctx context.Context)) {
+	pprof.Do(ctx, pprof.Labels(l.profilerLabelPairs(step)...), f)
+}
+
+// profilerLabelPairs returns pprof labels describing the step.
+func (l *Logger) profilerLabelPairs(step string) []string {
+	if name := l.Name(); name != "" {
+		return []string{"component", name, "step", step}
+	}
+	return []string{"step", step}
+}
+
+// startProfilerStep sets pprof labels of the step for the current goroutine. It returns logger which should be used
+// within the step and function restoring labels, or nil if labels are disabled.
+func (l *Logger) startProfilerStep(step string) (*Logger, func()) {
+	l.mu.RLock()
+	enabled, parent := l.profilerLabels, l.profilerCtx
+	l.mu.RUnlock()
+	if !enabled {
+		return l, nil
+	}
+
+	ctx := pprof.WithLabels(parent, pprof.Labels(l.profilerLabelPairs(step)...))
+	pprof.SetGoroutineLabels(ctx)
+	n := l.clone()
+	n.profilerCtx = ctx
+	return n, func() { pprof.SetGoroutineLabels(parent) }
+}
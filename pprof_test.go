@@ -0,0 +1,55 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+// goroutineLabels returns labels of goroutines reported by the goroutine profile.
+func goroutineLabels() string {
+	var b bytes.Buffer
+	pprof.Lookup("goroutine").WriteTo(&b, 1)
+	return b.String()
+}
+
+func TestWithProfilerLabels(t *testing.T) {
+	t.Run("label goroutines running steps", func(t *testing.T) {
+		l := log.New(&bytes.Buffer{}).WithName("deploy").WithProfilerLabels()
+
+		step := l.Step("build")
+		during := goroutineLabels()
+		nested := step.Logger().Step("push")
+		duringNested := goroutineLabels()
+		nested.Done()
+		afterNested := goroutineLabels()
+		step.Done()
+		after := goroutineLabels()
+
+		assert.Contains(t, during, `# labels: {"component":"deploy", "step":"build"}`)
+		assert.Contains(t, duringNested, `# labels: {"component":"deploy", "step":"push"}`)
+		assert.Contains(t, afterNested, `# labels: {"component":"deploy", "step":"build"}`)
+		assert.NotContains(t, after, `"step":"build"`)
+	})
+
+	t.Run("don't label goroutines by default", func(t *testing.T) {
+		step := log.New(&bytes.Buffer{}).Step("test")
+
+		assert.NotContains(t, goroutineLabels(), `"step":"test"`)
+		step.Done()
+	})
+
+	t.Run("label functions", func(t *testing.T) {
+		log.New(&bytes.Buffer{}).WithName("deploy").ProfileDo(context.Background(), "build", func(ctx context.Context) {
+			v, _ := pprof.Label(ctx, "step")
+			assert.Equal(t, "build", v)
+			v, _ = pprof.Label(ctx, "component")
+			assert.Equal(t, "deploy", v)
+		})
+	})
+}
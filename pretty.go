@@ -0,0 +1,237 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PrettyLimits limits output of the pretty printer, see WithPrettyValues. Zero disables a limit.
+type PrettyLimits struct {
+	// MaxDepth is the maximum nesting of structs, maps, slices and arrays. Deeper values are replaced with "{…}" or
+	// "[…]".
+	MaxDepth int
+	// MaxElements is the maximum number of elements of maps, slices and arrays, the rest is replaced with "…+N".
+	MaxElements int
+	// MaxBytes is the maximum length of a rendered value, it is truncated with "…" if exceeded.
+	MaxBytes int
+}
+
+// DefaultPrettyLimits are limits suitable for most commands.
+var DefaultPrettyLimits = PrettyLimits{MaxDepth: 5, MaxElements: 20, MaxBytes: 4096}
+
+// WithPrettyValues creates new logger instance rendering struct, map, slice and array arguments of Print and Printf
+// (formatted with %v or %s) using the pretty printer, limited by limits, instead of unbounded %+v. It prevents
+// accidental multi-megabyte lines when a whole object (e.g. a Kubernetes resource) is logged. Values implementing
+// fmt.Stringer, error or fmt.Formatter are formatted using their methods.
+func (l *Logger) WithPrettyValues(limits PrettyLimits) *Logger {
+	n := l.clone()
+	n.pretty = &limits
+	return n
+}
+
+// prettyArgs returns arguments with complex values wrapped, so they are rendered by the pretty printer.
+func prettyArgs(args []interface{}, limits PrettyLimits) []interface{} {
+	var r []interface{}
+	for i, arg := range args {
+		if !isComplexValue(arg) {
+			if r != nil {
+				r = append(r, arg)
+			}
+			continue
+		}
+		if r == nil {
+			r = append(make([]interface{}, 0, len(args)), args[:i]...)
+		}
+		r = append(r, prettyValue{arg, limits})
+	}
+	if r == nil {
+		return args
+	}
+	return r
+}
+
+// isComplexValue reports whether the value is a struct, map, slice or array (or a pointer to one) without its own
+// formatting methods.
+func isComplexValue(v interface{}) bool {
+	switch v.(type) {
+	case nil, fmt.Stringer, error, fmt.Formatter, fmt.GoStringer:
+		return false
+	}
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
+}
+
+// prettyValue is an argument rendered by the pretty printer. Verbs other than %v and %s (and %#v) are handled by fmt
+// as usual.
+type prettyValue struct {
+	v      interface{}
+	limits PrettyLimits
+}
+
+func (p prettyValue) Format(f fmt.State, verb rune) {
+	if (verb == 'v' || verb == 's') && !f.Flag('#') {
+		io.WriteString(f, prettyPrint(p.v, p.limits))
+		return
+	}
+	fmt.Fprintf(f, formatDirective(f, verb), p.v)
+}
+
+// formatDirective reconstructs the directive which was used to format a value.
+func formatDirective(f fmt.State, verb rune) string {
+	var b strings.Builder
+	b.WriteByte('%')
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			b.WriteRune(flag)
+		}
+	}
+	if w, ok := f.Width(); ok {
+		b.WriteString(strconv.Itoa(w))
+	}
+	if p, ok := f.Precision(); ok {
+		b.WriteString("." + strconv.Itoa(p))
+	}
+	b.WriteRune(verb)
+	return b.String()
+}
+
+// prettyPrint renders the value in the manner of %+v, within the limits.
+func prettyPrint(v interface{}, limits PrettyLimits) string {
+	p := prettyPrinter{limits: limits}
+	p.print(reflect.ValueOf(v), 0)
+	s := p.b.String()
+	if limits.MaxBytes > 0 && len(s) > limits.MaxBytes {
+		s = truncateUTF8(s, limits.MaxBytes) + truncatedSuffix
+	}
+	return s
+}
+
+type prettyPrinter struct {
+	limits PrettyLimits
+	b      strings.Builder
+}
+
+// full reports whether the output already exceeds MaxBytes, so the rest can be skipped.
+func (p *prettyPrinter) full() bool {
+	return p.limits.MaxBytes > 0 && p.b.Len() > p.limits.MaxBytes
+}
+
+func (p *prettyPrinter) print(v reflect.Value, depth int) {
+	if p.full() {
+		return
+	}
+	if !v.IsValid() {
+		p.b.WriteString("<nil>")
+		return
+	}
+	if depth > 0 && v.CanInterface() {
+		switch i := v.Interface().(type) {
+		case error, fmt.Stringer, fmt.Formatter:
+			p.b.WriteString(safeSprint(i))
+			return
+		}
+	}
+
+	deep := p.limits.MaxDepth > 0 && depth >= p.limits.MaxDepth
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			p.b.WriteString("<nil>")
+			return
+		}
+		switch v.Elem().Kind() {
+		case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+			p.b.WriteByte('&')
+			p.print(v.Elem(), depth)
+		default:
+			p.b.WriteString(safeSprint(v))
+		}
+	case reflect.Interface:
+		p.print(v.Elem(), depth)
+	case reflect.Struct:
+		if deep {
+			p.b.WriteString("{…}")
+			return
+		}
+		p.b.WriteByte('{')
+		for i := 0; i < v.NumField() && !p.full(); i++ {
+			if i > 0 {
+				p.b.WriteByte(' ')
+			}
+			p.b.WriteString(v.Type().Field(i).Name + ":")
+			p.print(v.Field(i), depth+1)
+		}
+		p.b.WriteByte('}')
+	case reflect.Map:
+		if v.IsNil() {
+			p.b.WriteString("map[]")
+			return
+		}
+		if deep {
+			p.b.WriteString("map[…]")
+			return
+		}
+		keys := v.MapKeys()
+		names := make([]string, len(keys))
+		for i, k := range keys {
+			names[i] = safeSprint(k)
+		}
+		sort.Sort(keysByName{keys, names})
+		p.b.WriteString("map[")
+		p.elements(len(keys), func(i int) {
+			p.b.WriteString(names[i] + ":")
+			p.print(v.MapIndex(keys[i]), depth+1)
+		})
+		p.b.WriteByte(']')
+	case reflect.Slice, reflect.Array:
+		if deep {
+			p.b.WriteString("[…]")
+			return
+		}
+		p.b.WriteByte('[')
+		p.elements(v.Len(), func(i int) {
+			p.print(v.Index(i), depth+1)
+		})
+		p.b.WriteByte(']')
+	default:
+		p.b.WriteString(safeSprint(v))
+	}
+}
+
+// elements prints n elements separated by spaces, within the MaxElements limit.
+func (p *prettyPrinter) elements(n int, print func(i int)) {
+	for i := 0; i < n && !p.full(); i++ {
+		if i > 0 {
+			p.b.WriteByte(' ')
+		}
+		if p.limits.MaxElements > 0 && i >= p.limits.MaxElements {
+			p.b.WriteString("…+" + strconv.Itoa(n-i))
+			return
+		}
+		print(i)
+	}
+}
+
+// keysByName sorts map keys by their rendered names.
+type keysByName struct {
+	keys  []reflect.Value
+	names []string
+}
+
+func (k keysByName) Len() int           { return len(k.keys) }
+func (k keysByName) Less(i, j int) bool { return k.names[i] < k.names[j] }
+func (k keysByName) Swap(i, j int) {
+	k.keys[i], k.keys[j] = k.keys[j], k.keys[i]
+	k.names[i], k.names[j] = k.names[j], k.names[i]
+}
@@ -0,0 +1,63 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+type prettyNode struct {
+	Name     string
+	Labels   map[string]string
+	Children []*prettyNode
+	Err      error
+	hidden   int
+}
+
+func TestWithPrettyValues(t *testing.T) {
+	node := &prettyNode{
+		Name:   "root",
+		Labels: map[string]string{"b": "2", "a": "1"},
+		Children: []*prettyNode{
+			{Name: "child", Children: []*prettyNode{{Name: "grandchild"}}},
+		},
+		Err:    errors.New("failed"),
+		hidden: 7,
+	}
+
+	t.Run("render complex values within limits", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.PlainMode).WithPrettyValues(log.PrettyLimits{MaxDepth: 3, MaxElements: 2})
+
+		l.Print("node:", node)
+		l.Printf("values: %v %d", []int{1, 2, 3, 4}, []int{5})
+
+		assert.Equal(t, "[INFO] node:&{Name:root Labels:map[a:1 b:2] Children:[&{Name:child Labels:map[] "+
+			"Children:[…] Err:<nil> hidden:0}] Err:failed hidden:7}\n"+
+			"[INFO] values: [1 2 …+2] [5]\n", b.String())
+	})
+
+	t.Run("truncate long values", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.PlainMode).WithPrettyValues(log.PrettyLimits{MaxBytes: 10})
+
+		l.Print([]string{strings.Repeat("a", 1000), "b"})
+
+		assert.Equal(t, "[INFO] [aaaaaaaaa…\n", b.String())
+	})
+
+	t.Run("leave other values to fmt", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.PlainMode).WithPrettyValues(log.DefaultPrettyLimits)
+
+		l.Print("foo", 1, errors.New("bar"))
+		l.Printf("%#v %5.1f", []int{1}, 2.0)
+
+		assert.Equal(t, "[INFO] foo1 bar\n[INFO] []int{1}   2.0\n", b.String())
+	})
+}
@@ -0,0 +1,32 @@
+package logger
+
+import (
+	"os"
+	"os/user"
+)
+
+// WithProcessInfo creates new logger instance with "host", "pid" and "user" fields describing the current process.
+// It is useful when logs are shipped off-box to a central aggregation.
+func (l *Logger) WithProcessInfo() *Logger {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return l.WithFields(
+		Field{Key: "host", Value: host},
+		Field{Key: "pid", Value: os.Getpid()},
+		Field{Key: "user", Value: currentUser()},
+	)
+}
+
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	for _, key := range []string{"USER", "USERNAME"} {
+		if name := os.Getenv(key); name != "" {
+			return name
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,24 @@
+package logger_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestWithProcessInfo(t *testing.T) {
+	var b bytes.Buffer
+
+	fields := log.New(&b).WithProcessInfo().Fields()
+	host, _ := os.Hostname()
+
+	assert.Len(t, fields, 3)
+	assert.Equal(t, log.Field{Key: "host", Value: host}, fields[0])
+	assert.Equal(t, log.Field{Key: "pid", Value: os.Getpid()}, fields[1])
+	assert.Equal(t, "user", fields[2].Key)
+	assert.NotEmpty(t, fields[2].Value)
+}
@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Progress reports progress of a long operation. Updates are coalesced, so at most one line per interval (1 second by
+// default) is written, and long loops don't flood the log.
+type Progress struct {
+	mu       sync.Mutex
+	l        *Logger
+	label    string
+	interval time.Duration
+	start    time.Time
+	last     time.Time
+	percent  float64
+	current  int64
+	total    int64
+	counted  bool
+	done     bool
+}
+
+// Progress creates new Progress writing to the logger.
+func (l *Logger) Progress(label string) *Progress {
+	return &Progress{l: l, label: label, interval: time.Second, start: l.Clock().Now(), percent: -1}
+}
+
+// SetInterval changes minimal interval between written lines.
+func (p *Progress) SetInterval(interval time.Duration) *Progress {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.interval = interval
+	return p
+}
+
+// SetPercent updates progress, percent should be between 0 and 100.
+func (p *Progress) SetPercent(percent float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.percent = percent
+	p.update(false)
+}
+
+// SetTotal sets total number of bytes to process, so percentage can be computed from bytes passed to Add.
+func (p *Progress) SetTotal(total int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.total = total
+}
+
+// Add updates progress with number of processed bytes. Lines include throughput, e.g. "Uploading: 42% (4.2MiB/10.0MiB,
+// 1.1MiB/s)".
+func (p *Progress) Add(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current += n
+	p.counted = true
+	if p.total > 0 {
+		p.percent = float64(p.current) * 100 / float64(p.total)
+	}
+	p.update(false)
+}
+
+// Done writes the final state of progress, regardless of interval. Subsequent updates are ignored. Percentage is set
+// to 100% unless bytes are counted without known total, or fewer bytes than total were processed.
+func (p *Progress) Done() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if (p.total > 0 && p.current >= p.total) || (p.total <= 0 && !p.counted) {
+		p.percent = 100
+	}
+	p.update(true)
+	p.done = true
+}
+
+// update writes the current state if enough time passed since the previous line. Caller must hold p.mu.
+func (p *Progress) update(force bool) {
+	now := p.l.Clock().Now()
+	if p.done || (!force && !p.last.IsZero() && now.Sub(p.last) < p.interval) {
+		return
+	}
+	p.last = now
+
+	var details []string
+	if p.counted {
+		if p.total > 0 {
+			details = append(details, formatBytes(p.current)+"/"+formatBytes(p.total))
+		} else {
+			details = append(details, formatBytes(p.current))
+		}
+		if elapsed := now.Sub(p.start).Seconds(); elapsed > 0 {
+			details = append(details, formatBytes(int64(float64(p.current)/elapsed))+"/s")
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(p.label + ":")
+	if p.percent >= 0 {
+		fmt.Fprintf(&b, " %.0f%%", p.percent)
+	}
+	if len(details) > 0 {
+		if p.percent >= 0 {
+			b.WriteString(" (" + strings.Join(details, ", ") + ")")
+		} else {
+			b.WriteString(" " + details[0])
+			if len(details) > 1 {
+				b.WriteString(" (" + details[1] + ")")
+			}
+		}
+	}
+	p.l.Print(b.String())
+}
@@ -0,0 +1,50 @@
+package logger
+
+import "io"
+
+type progressWriter struct {
+	p *Progress
+}
+
+// ProgressWriter returns writer counting bytes written to it and reporting them using Progress. It discards the data, so
+// it is meant to be combined with io.MultiWriter or io.TeeReader. Closing the writer writes the final progress line. If
+// total is not positive, only number of bytes and throughput is reported.
+func ProgressWriter(l *Logger, label string, total int64) io.WriteCloser {
+	p := l.Progress(label)
+	p.SetTotal(total)
+	return &progressWriter{p: p}
+}
+
+func (w *progressWriter) Write(b []byte) (int, error) {
+	w.p.Add(int64(len(b)))
+	return len(b), nil
+}
+
+func (w *progressWriter) Close() error {
+	w.p.Done()
+	return nil
+}
+
+type progressReader struct {
+	r io.Reader
+	p *Progress
+}
+
+// ProgressReader returns reader reading from r and reporting number of read bytes using Progress. The final progress
+// line is written when r reaches EOF. If total is not positive, only number of bytes and throughput is reported.
+func ProgressReader(l *Logger, label string, total int64, r io.Reader) io.Reader {
+	p := l.Progress(label)
+	p.SetTotal(total)
+	return &progressReader{r: r, p: p}
+}
+
+func (r *progressReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	if n > 0 {
+		r.p.Add(int64(n))
+	}
+	if err == io.EOF {
+		r.p.Done()
+	}
+	return n, err
+}
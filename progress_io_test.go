@@ -0,0 +1,40 @@
+package logger_test
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestProgressWriter(t *testing.T) {
+	var b bytes.Buffer
+	w := log.ProgressWriter(log.New(&b).WithOutputMode(log.PlainMode), "Uploading", 8)
+
+	n, err := io.Copy(w, strings.NewReader("abcdefgh"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8), n)
+	assert.NoError(t, w.Close())
+
+	assert.Regexp(t, regexp.MustCompile(`^`+
+		`\[INFO\] Uploading: 100% \(8B/8B, \S+/s\)\n`+
+		`\[INFO\] Uploading: 100% \(8B/8B, \S+/s\)\n$`), b.String())
+}
+
+func TestProgressReader(t *testing.T) {
+	var b bytes.Buffer
+	r := log.ProgressReader(log.New(&b).WithOutputMode(log.PlainMode), "Downloading", 0, strings.NewReader("abcd"))
+
+	data, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "abcd", string(data))
+
+	assert.Regexp(t, regexp.MustCompile(`^`+
+		`\[INFO\] Downloading: 4B \(\S+/s\)\n`+
+		`\[INFO\] Downloading: 4B \(\S+/s\)\n$`), b.String())
+}
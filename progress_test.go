@@ -0,0 +1,53 @@
+package logger_test
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestProgress(t *testing.T) {
+	t.Run("coalesce percentage updates", func(t *testing.T) {
+		var b bytes.Buffer
+		p := log.New(&b).WithOutputMode(log.PlainMode).Progress("Hashing")
+
+		for i := 0; i < 100; i++ {
+			p.SetPercent(float64(i))
+		}
+		p.Done()
+		p.SetPercent(50)
+
+		assert.Equal(t, "[INFO] Hashing: 0%\n[INFO] Hashing: 100%\n", b.String())
+	})
+
+	t.Run("report throughput", func(t *testing.T) {
+		var b bytes.Buffer
+		p := log.New(&b).WithOutputMode(log.PlainMode).Progress("Uploading").SetInterval(0)
+
+		p.SetTotal(2048)
+		p.Add(1024)
+		p.Add(1024)
+		p.Done()
+
+		assert.Regexp(t, regexp.MustCompile(`^`+
+			`\[INFO\] Uploading: 50% \(1\.0KiB/2\.0KiB, \S+/s\)\n`+
+			`\[INFO\] Uploading: 100% \(2\.0KiB/2\.0KiB, \S+/s\)\n`+
+			`\[INFO\] Uploading: 100% \(2\.0KiB/2\.0KiB, \S+/s\)\n$`), b.String())
+	})
+
+	t.Run("report bytes without total", func(t *testing.T) {
+		var b bytes.Buffer
+		p := log.New(&b).WithOutputMode(log.PlainMode).Progress("Downloading")
+
+		p.Add(10)
+		p.Done()
+
+		assert.Regexp(t, regexp.MustCompile(`^`+
+			`\[INFO\] Downloading: 10B \(\S+/s\)\n`+
+			`\[INFO\] Downloading: 10B \(\S+/s\)\n$`), b.String())
+	})
+}
@@ -0,0 +1,6 @@
+//go:build race
+
+package logger_test
+
+// raceEnabled is true if tests are run with the race detector, which allocates memory in instrumented code.
+const raceEnabled = true
@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// RecoverOption configures RecoverAndLog and Go.
+type RecoverOption func(*recoverOptions)
+
+type recoverOptions struct {
+	repanic bool
+	exit    bool
+}
+
+// WithRepanic makes RecoverAndLog panic again with the recovered value after logging it.
+func WithRepanic() RecoverOption {
+	return func(o *recoverOptions) {
+		o.repanic = true
+	}
+}
+
+// WithExit makes RecoverAndLog terminate the program with ExitCode after logging a panic. Fatal hooks are called
+// before (see RegisterFatalHook).
+func WithExit() RecoverOption {
+	return func(o *recoverOptions) {
+		o.exit = true
+	}
+}
+
+// RecoverAndLog recovers from a panic and logs its value and stack trace at fatal level. It must be called directly
+// using defer:
+//
+//	defer logger.RecoverAndLog(l, logger.WithExit())
+func RecoverAndLog(l *Logger, opts ...RecoverOption) {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	o := recoverOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	l = l.WithLevel(FatalLevel)
+	msg := fmt.Sprintf("panic: %v\n%s", v, debug.Stack())
+	l.Print(msg)
+	flushOutput(l.Output())
+
+	if o.repanic {
+		panic(v)
+	}
+	if o.exit {
+		runFatalHooks(l.newRecord(msg))
+		Exit()
+	}
+}
+
+// Go runs fn in a new goroutine, logging panics using RecoverAndLog.
+func Go(l *Logger, fn func(), opts ...RecoverOption) {
+	go func() {
+		defer RecoverAndLog(l, opts...)
+		fn()
+	}()
+}
+
+// flushOutput flushes buffered writers and syncs files, errors are ignored.
+func flushOutput(w interface{}) {
+	switch w := w.(type) {
+	case interface{ Flush() error }:
+		w.Flush()
+	case interface{ Sync() error }:
+		w.Sync()
+	}
+}
@@ -0,0 +1,62 @@
+package logger_test
+
+import (
+	"bytes"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestRecoverAndLog(t *testing.T) {
+	defer log.ResetExitCode()
+
+	t.Run("log panic", func(t *testing.T) {
+		var b bytes.Buffer
+		func() {
+			defer log.RecoverAndLog(log.New(&b).WithOutputMode(log.PlainMode))
+			panic("boom")
+		}()
+
+		assert.Regexp(t, regexp.MustCompile(`^\[FATAL\] panic: boom\ngoroutine \d+ \[running\]:\n(.|\n)+\n$`), b.String())
+	})
+
+	t.Run("do nothing without panic", func(t *testing.T) {
+		var b bytes.Buffer
+		func() {
+			defer log.RecoverAndLog(log.New(&b))
+		}()
+
+		assert.Empty(t, b.String())
+	})
+
+	t.Run("panic again", func(t *testing.T) {
+		var b bytes.Buffer
+		assert.PanicsWithValue(t, "boom", func() {
+			defer log.RecoverAndLog(log.New(&b).WithOutputMode(log.PlainMode), log.WithRepanic())
+			panic("boom")
+		})
+
+		assert.Contains(t, b.String(), "[FATAL] panic: boom\n")
+	})
+}
+
+func TestGo(t *testing.T) {
+	defer log.ResetExitCode()
+
+	var b syncBuffer
+	var wg sync.WaitGroup
+	wg.Add(1)
+	log.Go(log.New(&b).WithOutputMode(log.PlainMode), func() {
+		defer wg.Done()
+		panic("boom")
+	})
+	wg.Wait()
+
+	assert.Eventually(t, func() bool { return b.String() != "" }, time.Second, 5*time.Millisecond)
+	assert.Contains(t, b.String(), "[FATAL] panic: boom\n")
+}
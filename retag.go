@@ -0,0 +1,49 @@
+package logger
+
+import "bytes"
+
+// RetagWriter re-emits output of a child Klio command through a logger. Tags of the logger are prepended to tags of
+// decorated lines (e.g. ["child"] becomes ["parent", "child"]), while their levels are preserved. Plain lines are
+// printed like with Logger.Write.
+type RetagWriter struct {
+	l   *Logger
+	buf []byte
+}
+
+// Retag creates new RetagWriter writing to the logger.
+func Retag(l *Logger) *RetagWriter {
+	return &RetagWriter{l: l}
+}
+
+// Write processes complete lines of input. Incomplete line is buffered until the rest of it is written or the writer is
+// closed.
+func (w *RetagWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(string(bytes.TrimSuffix(w.buf[:i], []byte("\r"))))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Close writes buffered incomplete line.
+func (w *RetagWriter) Close() error {
+	if len(w.buf) > 0 {
+		w.emit(string(w.buf))
+		w.buf = nil
+	}
+	return nil
+}
+
+func (w *RetagWriter) emit(line string) {
+	r, ok := ParseLine(line)
+	if !ok {
+		w.l.Print(line)
+		return
+	}
+	w.l.WithLevel(r.Level).WithAppendedTags(r.Tags...).Print(r.Message)
+}
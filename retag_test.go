@@ -0,0 +1,32 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestRetag(t *testing.T) {
+	var child bytes.Buffer
+	log.New(&child).WithTags("child").WithLevel(log.WarnLevel).Print("foo")
+	child.WriteString("bar\nbaz")
+
+	var b bytes.Buffer
+	w := log.Retag(log.New(&b).WithName("cmd").WithTags("parent"))
+
+	data := child.Bytes()
+	w.Write(data[:10])
+	w.Write(data[10:])
+	assert.NoError(t, w.Close())
+
+	assert.Equal(
+		t,
+		"\033_klio_log_level \"warn\"\033\\\033_klio_tags [\"cmd\",\"parent\",\"child\"]\033\\foo\033_klio_reset\033\\\n"+
+			"\033_klio_log_level \"info\"\033\\\033_klio_tags [\"cmd\",\"parent\"]\033\\bar\033_klio_reset\033\\\n"+
+			"\033_klio_log_level \"info\"\033\\\033_klio_tags [\"cmd\",\"parent\"]\033\\baz\033_klio_reset\033\\\n",
+		b.String(),
+	)
+}
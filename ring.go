@@ -0,0 +1,82 @@
+package logger
+
+import "sync"
+
+// RingBuffer keeps the last lines written by loggers using it (see Logger.WithRingBuffer), including lines discarded
+// because of the threshold. When an error occurs, they can be written using DumpTo to show what led to it.
+type RingBuffer struct {
+	mu      sync.Mutex
+	records []Record
+	next    int
+	full    bool
+	seq     uint64
+}
+
+// NewRingBuffer creates new RingBuffer keeping at most size lines.
+func NewRingBuffer(size int) *RingBuffer {
+	return &RingBuffer{records: make([]Record, size)}
+}
+
+// WithRingBuffer creates new logger instance storing all lines in the ring buffer, regardless of the threshold.
+func (l *Logger) WithRingBuffer(rb *RingBuffer) *Logger {
+	n := l.clone()
+	n.ring = rb
+	return n
+}
+
+// Records returns stored lines, from the oldest to the newest.
+func (rb *RingBuffer) Records() []Record {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	return rb.stored()
+}
+
+// RecordsSince returns stored lines added after the line with specified sequence number, from the oldest to the newest,
+// and the sequence number of the newest line. Lines are numbered from 1 in the order they were added, so passing the
+// returned number to the next call returns only new lines.
+func (rb *RingBuffer) RecordsSince(seq uint64) ([]Record, uint64) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	if seq >= rb.seq {
+		return nil, rb.seq
+	}
+	records := rb.stored()
+	if n := rb.seq - seq; n < uint64(len(records)) {
+		records = records[len(records)-int(n):]
+	}
+	return records, rb.seq
+}
+
+// stored returns copy of stored lines, from the oldest to the newest. Caller must hold rb.mu.
+func (rb *RingBuffer) stored() []Record {
+	if !rb.full {
+		return append([]Record(nil), rb.records[:rb.next]...)
+	}
+	return append(append([]Record(nil), rb.records[rb.next:]...), rb.records[:rb.next]...)
+}
+
+// DumpTo writes stored lines using the logger, with their original levels, tags and fields. Lines are written
+// regardless of the threshold of the logger.
+func (rb *RingBuffer) DumpTo(l *Logger) {
+	for _, r := range rb.Records() {
+		n := l.clone()
+		n.level, n.name, n.tags = r.Level, "", r.Tags
+		n.threshold, n.vmodule, n.ring = "", nil, nil
+		n.updateLinePrefix()
+		n.WithFields(r.Fields...).Print(r.Message)
+	}
+}
+
+func (rb *RingBuffer) add(r Record) {
+	if len(rb.records) == 0 {
+		return
+	}
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	rb.records[rb.next] = r
+	rb.seq++
+	rb.next = (rb.next + 1) % len(rb.records)
+	if rb.next == 0 {
+		rb.full = true
+	}
+}
@@ -0,0 +1,67 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestRingBuffer(t *testing.T) {
+	rb := log.NewRingBuffer(3)
+
+	var b bytes.Buffer
+	l := log.New(&b).WithOutputMode(log.PlainMode).WithThreshold(log.InfoLevel).WithRingBuffer(rb)
+
+	l.WithLevel(log.DebugLevel).Print("first")
+	l.WithLevel(log.DebugLevel).WithTags("git").Print("cloning")
+	l.WithLevel(log.SpamLevel).WithField("ref", "main").Print("checkout")
+	l.WithName("helm").WithLevel(log.ErrorLevel).Print("failed")
+	assert.Equal(t, "[ERROR][HELM] failed\n", b.String())
+	assert.Len(t, rb.Records(), 3)
+
+	b.Reset()
+	rb.DumpTo(l.WithName("cmd"))
+
+	assert.Equal(t, ""+
+		"[DEBUG][GIT] cloning\n"+
+		"[SPAM] checkout ref=main\n"+
+		"[ERROR][HELM] failed\n", b.String())
+	assert.Len(t, rb.Records(), 3)
+}
+
+func TestRingBufferRecordsSince(t *testing.T) {
+	rb := log.NewRingBuffer(3)
+	l := log.New(&bytes.Buffer{}).WithRingBuffer(rb)
+
+	records, seq := rb.RecordsSince(0)
+	assert.Empty(t, records)
+	assert.Equal(t, uint64(0), seq)
+
+	l.Print("a")
+	l.Print("b")
+	records, seq = rb.RecordsSince(0)
+	assert.Equal(t, []string{"a", "b"}, messages(records))
+	assert.Equal(t, uint64(2), seq)
+
+	l.Print("c")
+	l.Print("d")
+	l.Print("e")
+	records, seq = rb.RecordsSince(2)
+	assert.Equal(t, []string{"c", "d", "e"}, messages(records))
+	assert.Equal(t, uint64(5), seq)
+	records, _ = rb.RecordsSince(4)
+	assert.Equal(t, []string{"e"}, messages(records))
+	records, _ = rb.RecordsSince(5)
+	assert.Empty(t, records)
+}
+
+func messages(records []log.Record) []string {
+	r := []string{}
+	for _, rec := range records {
+		r = append(r, rec.Message)
+	}
+	return r
+}
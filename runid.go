@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+)
+
+// EnvRunID is the name of the environment variable used to pass run ID to subprocesses.
+const EnvRunID = "KLIO_RUN_ID"
+
+var runID = initRunID()
+
+func initRunID() string {
+	if id := os.Getenv(EnvRunID); id != "" {
+		return id
+	}
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "00000000"
+	}
+	return hex.EncodeToString(b)
+}
+
+// RunID returns short identifier of the current run. It is generated at startup, unless it was inherited from the
+// parent process via KLIO_RUN_ID environment variable. It can be used to correlate interleaved output of parallel
+// runs.
+func RunID() string {
+	return runID
+}
+
+// RunIDEnv returns "KLIO_RUN_ID=<id>" entry which can be appended to the environment of a subprocess (exec.Cmd.Env),
+// so it shares run ID with the current process.
+func RunIDEnv() string {
+	return EnvRunID + "=" + runID
+}
+
+// ExportRunID sets KLIO_RUN_ID environment variable of the current process, so all subprocesses started afterwards
+// inherit run ID.
+func ExportRunID() error {
+	return os.Setenv(EnvRunID, runID)
+}
+
+// WithRunID creates new logger instance with "run:<id>" tag appended to its tags.
+func (l *Logger) WithRunID() *Logger {
+	return l.WithAppendedTags("run:" + runID)
+}
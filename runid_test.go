@@ -0,0 +1,32 @@
+package logger_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestRunID(t *testing.T) {
+	assert.Regexp(t, "^[0-9a-f]{8}$", log.RunID())
+	assert.Equal(t, log.RunID(), log.RunID())
+	assert.Equal(t, "KLIO_RUN_ID="+log.RunID(), log.RunIDEnv())
+}
+
+func TestExportRunID(t *testing.T) {
+	defer os.Unsetenv(log.EnvRunID)
+
+	assert.NoError(t, log.ExportRunID())
+	assert.Equal(t, log.RunID(), os.Getenv(log.EnvRunID))
+}
+
+func TestWithRunID(t *testing.T) {
+	var b bytes.Buffer
+
+	l := log.New(&b).WithTags("foo").WithRunID()
+
+	assert.Equal(t, []string{"foo", "run:" + log.RunID()}, l.Tags())
+}
@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"context"
+	"runtime"
+	"time"
+)
+
+// ReportRuntimeStats writes heap size, number of goroutines and garbage collector statistics at debug level every
+// interval, until the context is cancelled. It does nothing if DebugEnabled is false.
+func (l *Logger) ReportRuntimeStats(ctx context.Context, interval time.Duration) {
+	if !DebugEnabled {
+		return
+	}
+	l = l.WithLevel(DebugLevel)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				l.WithFields(runtimeStats()...).Print("Runtime statistics")
+			}
+		}
+	}()
+}
+
+func runtimeStats() []Field {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return []Field{
+		{Key: "heap_alloc", Value: formatBytes(int64(m.HeapAlloc))},
+		{Key: "heap_sys", Value: formatBytes(int64(m.HeapSys))},
+		{Key: "heap_objects", Value: m.HeapObjects},
+		{Key: "goroutines", Value: runtime.NumGoroutine()},
+		{Key: "gc_cycles", Value: m.NumGC},
+		{Key: "gc_pause_total", Value: time.Duration(m.PauseTotalNs)},
+		{Key: "gc_pause_last", Value: time.Duration(m.PauseNs[(m.NumGC+255)%256])},
+	}
+}
@@ -0,0 +1,28 @@
+package logger_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestReportRuntimeStats(t *testing.T) {
+	if !log.DebugEnabled {
+		t.Skip("debug logging is disabled")
+	}
+
+	var b syncBuffer
+	ctx, cancel := context.WithCancel(context.Background())
+	log.New(&b).WithOutputMode(log.PlainMode).ReportRuntimeStats(ctx, 10*time.Millisecond)
+
+	assert.Eventually(t, func() bool { return b.String() != "" }, time.Second, 5*time.Millisecond)
+	cancel()
+
+	assert.Regexp(t, regexp.MustCompile(`^\[DEBUG\] Runtime statistics heap_alloc=\S+ heap_sys=\S+ heap_objects=\d+ `+
+		`goroutines=\d+ gc_cycles=\d+ gc_pause_total=\S+ gc_pause_last=\S+\n`), b.String())
+}
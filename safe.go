@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Arguments are formatted only when a line is written (or recorded by a ring buffer), and only once. Panics inside
+// String, Error, Format or MarshalJSON methods of arguments and fields never escape the logger, they are replaced by
+// placeholders in the style of the fmt package, e.g. "%!v(PANIC=String method: boom)".
+
+// safeSprint formats arguments in the manner of fmt.Sprint, replacing the whole message with a placeholder if a panic
+// escapes fmt (e.g. when a value panics while its panic is formatted).
+func safeSprint(v ...interface{}) (s string) {
+	defer recoverPlaceholder(&s, "Sprint")
+	return fmt.Sprint(v...)
+}
+
+// safeSprintln formats arguments in the manner of fmt.Sprintln, see safeSprint.
+func safeSprintln(v ...interface{}) (s string) {
+	defer recoverPlaceholder(&s, "Sprintln")
+	return fmt.Sprintln(v...)
+}
+
+// safeSprintf formats arguments in the manner of fmt.Sprintf, see safeSprint.
+func safeSprintf(format string, v ...interface{}) (s string) {
+	defer recoverPlaceholder(&s, "Sprintf")
+	return fmt.Sprintf(format, v...)
+}
+
+// safeError returns message of the error, or a placeholder if Error panics.
+func safeError(err error) (s string) {
+	defer recoverPlaceholder(&s, "Error method")
+	return err.Error()
+}
+
+// safeMarshal encodes the value as JSON, returning an error if a MarshalJSON method panics.
+func safeMarshal(v interface{}) (data []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			data, err = nil, fmt.Errorf("panic in MarshalJSON: %s", describePanic(r))
+		}
+	}()
+	return json.Marshal(v)
+}
+
+func recoverPlaceholder(s *string, method string) {
+	if r := recover(); r != nil {
+		*s = "%!v(PANIC=" + method + ": " + describePanic(r) + ")"
+	}
+}
+
+// describePanic returns description of a recovered value. Values other than strings and runtime errors aren't
+// formatted, since they could panic again.
+func describePanic(r interface{}) string {
+	switch r := r.(type) {
+	case string:
+		return r
+	case interface{ RuntimeError() }:
+		return safeError(r.(error))
+	default:
+		return fmt.Sprintf("%T", r)
+	}
+}
@@ -0,0 +1,80 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+type panickingValue struct{}
+
+func (panickingValue) String() string { panic("boom") }
+
+func (panickingValue) Error() string { panic("boom") }
+
+type panickingJSON struct{}
+
+func (panickingJSON) MarshalJSON() ([]byte, error) { panic("boom") }
+
+// recursivePanic panics with itself, so the panic escapes fmt while it formats the panic value.
+type recursivePanic struct{}
+
+func (recursivePanic) String() string { panic(recursivePanic{}) }
+
+type countingStringer struct{ calls *int }
+
+func (s countingStringer) String() string {
+	*s.calls++
+	return "counted"
+}
+
+func TestSafeFormatting(t *testing.T) {
+	t.Run("replace panicking values with placeholders", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.PlainMode)
+
+		l.Print("value:", panickingValue{})
+		l.Printf("value: %s", panickingValue{})
+		l.WithField("value", panickingValue{}).Print("foo")
+
+		assert.Equal(t, "[INFO] value:%!v(PANIC=Error method: boom)\n"+
+			"[INFO] value: %!s(PANIC=Error method: boom)\n"+
+			"[INFO] foo value=\"%!v(PANIC=Error method: boom)\" value_type=logger_test.panickingValue\n", b.String())
+	})
+
+	t.Run("replace messages when panic escapes fmt", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.PlainMode)
+
+		l.Successf("value: %s", recursivePanic{})
+		l.Msg("missing.key", recursivePanic{})
+
+		assert.Equal(t, "[INFO] ✓ %!v(PANIC=Sprintf: logger_test.recursivePanic)\n"+
+			"[INFO] %!v(PANIC=Sprintln: logger_test.recursivePanic)\n", b.String())
+	})
+
+	t.Run("don't crash sinks", func(t *testing.T) {
+		var b bytes.Buffer
+		sink := log.NewGCPSink(&b, nil)
+
+		log.New(&bytes.Buffer{}).WithSink(sink).WithFields(log.Field{Key: "value", Value: panickingJSON{}}).Print("foo")
+
+		assert.Contains(t, b.String(), `"value":"{}"`)
+	})
+
+	t.Run("evaluate arguments once, only if needed", func(t *testing.T) {
+		var b bytes.Buffer
+		calls := 0
+		l := log.New(&b).WithOutputMode(log.PlainMode)
+
+		l.WithThreshold(log.InfoLevel).WithLevel(log.DebugLevel).Print(countingStringer{&calls})
+		assert.Equal(t, 0, calls)
+
+		l.WithRingBuffer(log.NewRingBuffer(10)).Printf("%s", countingStringer{&calls})
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, "[INFO] counted\n", b.String())
+	})
+}
@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// SamplingRule limits number of lines written at the level, see Logger.WithSampling.
+type SamplingRule struct {
+	// Level of lines the rule applies to.
+	Level Level
+	// Tick is the interval after which counters are reset.
+	Tick time.Duration
+	// First is the number of lines with the same message written during each tick.
+	First int
+	// Thereafter makes every Thereafter-th line written after the first ones. Zero drops them.
+	Thereafter int
+}
+
+// samplingCounters is the number of counters used by a sampler, messages with the same hash share a counter.
+const samplingCounters = 4096
+
+// WithSampling creates new logger instance sampling lines like zap's sampler: during each tick, the first lines with
+// the same level and message are written and then only every Thereafter-th one. It lets verbose instrumentation stay
+// enabled without flooding the output, e.g.:
+//
+//	l = l.WithSampling(logger.SamplingRule{Level: logger.SpamLevel, Tick: time.Second, First: 10, Thereafter: 100})
+//
+// Levels without a rule are not sampled. Sampling uses the clock of the logger (see WithClock).
+func (l *Logger) WithSampling(rules ...SamplingRule) *Logger {
+	s := &sampler{clock: l.Clock(), rules: map[Level]SamplingRule{}}
+	for _, r := range rules {
+		s.rules[r.Level] = r
+	}
+	return l.WithFilter(s.accept)
+}
+
+type sampler struct {
+	mu       sync.Mutex
+	clock    Clock
+	rules    map[Level]SamplingRule
+	counters [samplingCounters]samplingCounter
+}
+
+type samplingCounter struct {
+	resetAt time.Time
+	n       int
+}
+
+func (s *sampler) accept(r Record) bool {
+	rule, ok := s.rules[r.Level]
+	if !ok {
+		return true
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(r.Level))
+	h.Write([]byte{0})
+	h.Write([]byte(r.Message))
+
+	now := s.clock.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := &s.counters[h.Sum32()%samplingCounters]
+	if !now.Before(c.resetAt) {
+		c.resetAt, c.n = now.Add(rule.Tick), 0
+	}
+	c.n++
+
+	if c.n <= rule.First {
+		return true
+	}
+	return rule.Thereafter > 0 && (c.n-rule.First)%rule.Thereafter == 0
+}
@@ -0,0 +1,38 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestWithSampling(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)}
+
+	var b bytes.Buffer
+	l := log.New(&b).WithOutputMode(log.PlainMode).WithClock(clock).WithSampling(
+		log.SamplingRule{Level: log.SpamLevel, Tick: time.Second, First: 2, Thereafter: 3},
+	)
+	spam := l.WithLevel(log.SpamLevel)
+
+	for i := 0; i < 8; i++ {
+		spam.Print("tick")
+	}
+	spam.Print("other")
+	for i := 0; i < 3; i++ {
+		l.Print("info")
+	}
+	clock.Add(time.Second)
+	spam.Print("tick")
+
+	assert.Equal(t, ""+
+		strings.Repeat("[SPAM] tick\n", 4)+ // 1st, 2nd, 5th and 8th
+		"[SPAM] other\n"+
+		strings.Repeat("[INFO] info\n", 3)+
+		"[SPAM] tick\n", b.String())
+}
@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"regexp"
+	"time"
+)
+
+// ScrubMode decides what happens with fields which are not allowed, see Logger.WithScrubbing.
+type ScrubMode int
+
+const (
+	// ScrubRemove removes fields which are not allowed.
+	ScrubRemove ScrubMode = iota
+	// ScrubHash replaces values of fields which are not allowed with their keyed hashes (HMAC-SHA256), e.g.
+	// "hmac:5e884898da28". Lines concerning the same value can still be correlated, without revealing the value. The key
+	// is generated randomly for each process, unless it is set using Logger.WithScrubbingKey.
+	ScrubHash
+)
+
+// processScrubKey is the key of hashes computed by ScrubHash, unless another one is set using WithScrubbingKey.
+var processScrubKey = initScrubKey()
+
+func initScrubKey() []byte {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		// Still unknown to readers of logs, although easier to guess
+		binary.BigEndian.PutUint64(b, uint64(time.Now().UnixNano()))
+	}
+	return b
+}
+
+type scrubber struct {
+	mode    ScrubMode
+	allowed map[string]bool
+	key     []byte
+}
+
+func init() {
+	redactionPresets["email"] = []RedactionRule{{
+		Name:    "email",
+		Pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	}}
+	redactionPresets["ip"] = []RedactionRule{{
+		Name:    "ip",
+		Pattern: regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b|\b(?:[0-9A-Fa-f]{1,4}:){7}[0-9A-Fa-f]{1,4}\b`),
+	}}
+}
+
+// WithScrubbing creates new logger instance removing or hashing (depending on mode) values of fields which keys are not
+// on the allowlist, so personal data like emails or IP addresses doesn't end up in logs. Personal data in messages can
+// be masked using "email" and "ip" redaction presets (see RedactionPreset).
+func (l *Logger) WithScrubbing(mode ScrubMode, allowed ...string) *Logger {
+	n := l.clone()
+	s := &scrubber{mode: mode, allowed: make(map[string]bool, len(allowed)), key: n.scrubKey}
+	if s.key == nil {
+		s.key = processScrubKey
+	}
+	for _, key := range allowed {
+		s.allowed[key] = true
+	}
+	n.scrubber = s
+	return n
+}
+
+// WithScrubbingKey creates new logger instance using the key to hash values of fields in ScrubHash mode, instead of the
+// key generated for the process. Hashes computed with the same key can be correlated across processes, so the key must
+// be kept secret. Nil restores the key of the process.
+func (l *Logger) WithScrubbingKey(key []byte) *Logger {
+	n := l.clone()
+	n.scrubKey = key
+	if n.scrubber != nil {
+		s := *n.scrubber
+		s.key = key
+		if s.key == nil {
+			s.key = processScrubKey
+		}
+		n.scrubber = &s
+	}
+	return n
+}
+
+// scrub returns fields without ones which are not allowed or with their values hashed.
+func (s *scrubber) scrub(fields []Field) []Field {
+	r := make([]Field, 0, len(fields))
+	for _, f := range fields {
+		switch {
+		case s.allowed[f.Key]:
+			r = append(r, f)
+		case s.mode == ScrubHash:
+			mac := hmac.New(sha256.New, s.key)
+			mac.Write([]byte(safeSprint(f.Value)))
+			r = append(r, Field{Key: f.Key, Value: "hmac:" + hex.EncodeToString(mac.Sum(nil)[:6])})
+		}
+	}
+	return r
+}
@@ -0,0 +1,58 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestWithScrubbing(t *testing.T) {
+	t.Run("remove fields", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.PlainMode).WithScrubbing(log.ScrubRemove, "release")
+
+		l.WithField("release", "api").WithField("email", "bob@example.com").WithField("ip", "10.0.0.1").Print("deployed")
+
+		assert.Equal(t, "[INFO] deployed release=api\n", b.String())
+	})
+
+	t.Run("hash fields", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.PlainMode).WithScrubbing(log.ScrubHash, "release").WithSequence().
+			WithScrubbingKey([]byte("secret"))
+
+		l.WithField("release", "api").WithField("email", "bob@example.com").Print("deployed")
+
+		assert.Equal(t, "[INFO] deployed release=api email=hmac:19d2874a5656 seq=1\n", b.String())
+	})
+
+	t.Run("hash fields using key of the process", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.PlainMode).WithScrubbing(log.ScrubHash)
+
+		l.WithField("email", "bob@example.com").Print("first")
+		l.WithField("email", "bob@example.com").Print("second")
+		l.WithScrubbingKey([]byte("secret")).WithField("email", "bob@example.com").Print("keyed")
+
+		lines := strings.Split(b.String(), "\n")
+		assert.Regexp(t, `^\[INFO\] first email=hmac:[0-9a-f]{12}$`, lines[0])
+		assert.Equal(t, strings.TrimPrefix(lines[0], "[INFO] first"), strings.TrimPrefix(lines[1], "[INFO] second"))
+		assert.NotContains(t, lines[0], "5ff860bf1190")
+		assert.Equal(t, "[INFO] keyed email=hmac:19d2874a5656", lines[2])
+	})
+
+	t.Run("mask personal data in messages", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.PlainMode).
+			WithRedaction(log.RedactionPreset("email")...).
+			WithRedaction(log.RedactionPreset("ip")...)
+
+		l.Print("login of bob.smith+ci@example.co.uk from 192.168.1.20 and fe80:0:0:0:202:b3ff:fe1e:8329")
+
+		assert.Equal(t, "[INFO] login of [REDACTED] from [REDACTED] and [REDACTED]\n", b.String())
+	})
+}
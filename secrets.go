@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var secretDetectors = []RedactionRule{
+	{
+		Name:    "aws-access-key",
+		Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`),
+		Notify:  true,
+	},
+	{
+		Name:        "aws-secret-key",
+		Pattern:     regexp.MustCompile(`(?i)(aws_secret_access_key["']?\s*[=:]\s*["']?)[a-z0-9/+=]{40}`),
+		Replacement: "${1}" + RedactedValue,
+		Notify:      true,
+	},
+	{
+		Name:    "github-token",
+		Pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{22,255})\b`),
+		Notify:  true,
+	},
+	{
+		Name:    "jwt",
+		Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+		Notify:  true,
+	},
+	{
+		Name:    "private-key",
+		Pattern: regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----.*?(?:-----END [A-Z ]*PRIVATE KEY-----|$)`),
+		Notify:  true,
+	},
+}
+
+func init() {
+	redactionPresets["secrets"] = secretDetectors
+	for _, d := range secretDetectors {
+		redactionPresets[d.Name] = []RedactionRule{d}
+	}
+}
+
+// SecretDetectors returns rules masking common credentials: AWS access keys and secret keys, GitHub tokens, JWTs and
+// private key blocks. They are also available as presets (see RedactionPreset), named "secrets" (all of them),
+// "aws-access-key", "aws-secret-key", "github-token", "jwt" and "private-key".
+func SecretDetectors() []RedactionRule {
+	return append([]RedactionRule(nil), secretDetectors...)
+}
+
+// WithSecretDetection creates new logger instance masking common credentials (see SecretDetectors). Whenever something
+// is masked, a debug line naming the detector is written.
+func (l *Logger) WithSecretDetection() *Logger {
+	return l.WithRedaction(secretDetectors...)
+}
+
+// noteRedactions writes a debug line naming rules which masked something in the previous line.
+func (l *Logger) noteRedactions(notes map[string]bool) {
+	names := make([]string, 0, len(notes))
+	for name := range notes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	l.WithLevel(DebugLevel).Printf("Masked %s in the previous line", strings.Join(names, ", "))
+}
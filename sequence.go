@@ -0,0 +1,20 @@
+package logger
+
+import "sync/atomic"
+
+// SequenceField is the key of the field holding sequence numbers of lines, see Logger.WithSequence.
+const SequenceField = "seq"
+
+// WithSequence creates new logger instance stamping each written line with a sequence number (1, 2, 3...) in the
+// "seq" field, so consumers can detect reordered or lost lines. The counter is shared with loggers derived from the
+// returned one.
+func (l *Logger) WithSequence() *Logger {
+	n := l.clone()
+	n.sequence = new(uint64)
+	return n
+}
+
+// nextSequenceField returns field with the next sequence number. Caller must check sequence isn't nil.
+func nextSequenceField(sequence *uint64) Field {
+	return Field{Key: SequenceField, Value: atomic.AddUint64(sequence, 1)}
+}
@@ -0,0 +1,27 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestWithSequence(t *testing.T) {
+	var b bytes.Buffer
+	l := log.New(&b).WithOutputMode(log.PlainMode).WithSequence()
+
+	l.Print("foo")
+	l.WithTags("bar").WithField("key", "value").Print("baz")
+	l.WithThreshold(log.WarnLevel).Print("filtered")
+	l.Print("qux")
+	log.New(&b).WithOutputMode(log.PlainMode).Print("other")
+
+	assert.Equal(t, ""+
+		"[INFO] foo seq=1\n"+
+		"[INFO][BAR] baz key=value seq=2\n"+
+		"[INFO] qux seq=3\n"+
+		"[INFO] other\n", b.String())
+}
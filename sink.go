@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Sink receives lines written by a logger as structured records, in addition to its output, see Logger.WithSink.
+// Records passed to sinks are already scrubbed and redacted.
+type Sink interface {
+	WriteRecord(t time.Time, r Record) error
+}
+
+// WithSink creates new logger instance passing written lines to the sinks. Sinks are combined with sinks of the
+// logger. Errors returned by sinks are counted in Stats.WriteErrors.
+func (l *Logger) WithSink(sinks ...Sink) *Logger {
+	n := l.clone()
+	n.sinks = append(n.sinks[:len(n.sinks):len(n.sinks)], sinks...)
+	return n
+}
+
+func writeSinks(sinks []Sink, s *stats, t time.Time, r Record) {
+	for _, sink := range sinks {
+		start := time.Now()
+		err := sink.WriteRecord(t, r)
+		s.observeSink(sink, time.Since(start))
+		if err != nil {
+			s.record(0, err)
+		}
+	}
+}
+
+// marshalFields returns fields encoded as a JSON object. Values which can't be encoded are converted to strings.
+func marshalFields(fields []Field) ([]byte, error) {
+	m := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		m[f.Key] = f.Value
+	}
+	data, err := safeMarshal(m)
+	if err == nil {
+		return data, nil
+	}
+	for k, v := range m {
+		m[k] = safeSprint(v)
+	}
+	return json.Marshal(m)
+}
+
+// encodeRecord returns the record encoded as a JSON object with "time", "level", "tags", "message", "fields" and
+// "run_id" keys.
+func encodeRecord(t time.Time, r Record) ([]byte, error) {
+	tags := r.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	fields, err := marshalFields(r.Fields)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		Time    string          `json:"time"`
+		Level   Level           `json:"level"`
+		Tags    []string        `json:"tags"`
+		Message string          `json:"message"`
+		Fields  json.RawMessage `json:"fields"`
+		RunID   string          `json:"run_id"`
+	}{t.UTC().Format(time.RFC3339Nano), r.Level, tags, r.Message, fields, runID})
+}
@@ -0,0 +1,52 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+type recordingSink struct {
+	times   []time.Time
+	records []log.Record
+	err     error
+}
+
+func (s *recordingSink) WriteRecord(t time.Time, r log.Record) error {
+	s.times = append(s.times, t)
+	s.records = append(s.records, r)
+	return s.err
+}
+
+func TestWithSink(t *testing.T) {
+	t.Run("pass written lines to sinks", func(t *testing.T) {
+		clock := &fakeClock{now: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)}
+		first, second := &recordingSink{}, &recordingSink{}
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.PlainMode).WithClock(clock).WithThreshold(log.InfoLevel).WithSink(first)
+
+		l.WithTags("foo").WithField("key", "value").Print("bar")
+		l.WithLevel(log.DebugLevel).Print("skipped")
+		l.WithSink(second).WithRedaction(log.RedactionPreset("bearer")...).WithLevel(log.WarnLevel).Print("Authorization: Bearer abc")
+
+		assert.Equal(t, []log.Record{
+			{Level: log.InfoLevel, Tags: []string{"foo"}, Message: "bar", Fields: []log.Field{{Key: "key", Value: "value"}}},
+			{Level: log.WarnLevel, Tags: []string{}, Message: "Authorization: Bearer [REDACTED]", Fields: nil},
+		}, first.records)
+		assert.Equal(t, []time.Time{clock.now, clock.now}, first.times)
+		assert.Equal(t, first.records[1:], second.records)
+	})
+
+	t.Run("count errors", func(t *testing.T) {
+		l := log.New(&bytes.Buffer{}).WithSink(&recordingSink{err: errors.New("disk full")})
+
+		l.Print("foo")
+
+		assert.Equal(t, int64(1), l.Stats().WriteErrors)
+	})
+}
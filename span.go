@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	// TraceIDField is the key of the field holding trace ID of the span active in a context, see SetSpanExtractor.
+	TraceIDField = "trace_id"
+	// SpanIDField is the key of the field holding ID of the span active in a context, see SetSpanExtractor.
+	SpanIDField = "span_id"
+)
+
+// SpanContext identifies a span of a distributed trace.
+type SpanContext struct {
+	TraceID string
+	SpanID  string
+}
+
+var (
+	spanExtractorMu sync.RWMutex
+	spanExtractor   func(ctx context.Context) (SpanContext, bool)
+)
+
+// SetSpanExtractor sets function returning the span active in a context. Loggers returned by FromContext add IDs of
+// the span in "trace_id" and "span_id" fields, linking lines to distributed traces. Tracing libraries are kept out of
+// dependencies of this package, so the function is usually a small adapter, e.g. for OpenTelemetry:
+//
+//	log.SetSpanExtractor(func(ctx context.Context) (log.SpanContext, bool) {
+//		sc := trace.SpanContextFromContext(ctx)
+//		return log.SpanContext{TraceID: sc.TraceID().String(), SpanID: sc.SpanID().String()}, sc.IsValid()
+//	})
+//
+// Nil removes the extractor.
+func SetSpanExtractor(extractor func(ctx context.Context) (SpanContext, bool)) {
+	spanExtractorMu.Lock()
+	defer spanExtractorMu.Unlock()
+	spanExtractor = extractor
+}
+
+// spanFields returns fields identifying the span active in the context, if any.
+func spanFields(ctx context.Context) []Field {
+	spanExtractorMu.RLock()
+	extractor := spanExtractor
+	spanExtractorMu.RUnlock()
+	if extractor == nil {
+		return nil
+	}
+	span, ok := extractor(ctx)
+	if !ok {
+		return nil
+	}
+	return []Field{{Key: TraceIDField, Value: span.TraceID}, {Key: SpanIDField, Value: span.SpanID}}
+}
@@ -0,0 +1,30 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+type spanKey struct{}
+
+func TestSetSpanExtractor(t *testing.T) {
+	log.SetSpanExtractor(func(ctx context.Context) (log.SpanContext, bool) {
+		span, ok := ctx.Value(spanKey{}).(log.SpanContext)
+		return span, ok
+	})
+	defer log.SetSpanExtractor(nil)
+
+	var b bytes.Buffer
+	ctx := log.NewContext(context.Background(), log.New(&b).WithOutputMode(log.PlainMode))
+
+	log.FromContext(ctx).Print("foo")
+	ctx = context.WithValue(ctx, spanKey{}, log.SpanContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"})
+	log.FromContext(ctx).Print("bar")
+
+	assert.Equal(t, "[INFO] foo\n[INFO] bar trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7\n", b.String())
+}
@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// SpoolRetryInterval is the minimum time between attempts of SpoolWriter to replay spooled data on write.
+const SpoolRetryInterval = 5 * time.Second
+
+// SpoolWriter protects against transient failures of the output (e.g. broken pipes or network outages). When writing
+// to the output fails, data is stored in a temporary file (spooled) and later replayed, in the original order, once
+// the output accepts writes again. Replay is attempted on write (at most every SpoolRetryInterval), by Flush and by
+// Close.
+type SpoolWriter struct {
+	mu         sync.Mutex
+	w          io.Writer
+	dir        string
+	clock      Clock
+	spool      *os.File
+	size       int64
+	offset     int64
+	skip       int
+	pending    int64
+	lastRetry  time.Time
+	closed     bool
+	unregister func()
+}
+
+// NewSpoolWriter creates new SpoolWriter writing to w and spooling to temporary files created in dir (the default
+// directory for temporary files if empty). It should be closed when no longer needed.
+func NewSpoolWriter(w io.Writer, dir string) *SpoolWriter {
+	s := &SpoolWriter{w: w, dir: dir, clock: systemClock{}}
+	s.unregister = registerFlusher(func() { s.Flush() })
+	return s
+}
+
+// Write writes data to the output or, if it fails, to the spool file. It returns an error only if spooling fails.
+func (s *SpoolWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return 0, os.ErrClosed
+	}
+
+	if s.spool != nil && s.clock.Now().Sub(s.lastRetry) >= SpoolRetryInterval {
+		s.replay()
+	}
+	total := len(p)
+	if s.spool == nil {
+		n, err := s.w.Write(p)
+		if err == nil {
+			return n, nil
+		}
+		p = p[n:]
+	}
+	if err := s.append(p); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// Pending returns the number of writes waiting in the spool file.
+func (s *SpoolWriter) Pending() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pending
+}
+
+// Flush replays spooled data. It returns an error if the output still fails.
+func (s *SpoolWriter) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.spool == nil {
+		return nil
+	}
+	return s.replay()
+}
+
+// Close replays spooled data. If the output still fails, the spool file is left on the disk and the returned error
+// contains its path.
+func (s *SpoolWriter) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	s.unregister()
+	if s.spool == nil {
+		return nil
+	}
+	if err := s.replay(); err != nil {
+		name := s.spool.Name()
+		s.spool.Close()
+		return fmt.Errorf("%d writes left in %s: %w", s.pending, name, err)
+	}
+	return nil
+}
+
+// append stores data in the spool file, creating it if needed. Each write is stored prefixed with its length.
+func (s *SpoolWriter) append(p []byte) error {
+	if s.spool == nil {
+		f, err := os.CreateTemp(s.dir, "klio-spool-")
+		if err != nil {
+			return err
+		}
+		s.spool, s.size, s.offset, s.skip, s.lastRetry = f, 0, 0, 0, s.clock.Now()
+	}
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(p)))
+	if _, err := s.spool.WriteAt(append(size[:], p...), s.size); err != nil {
+		return err
+	}
+	s.size += int64(len(size) + len(p))
+	s.pending++
+	return nil
+}
+
+// replay writes spooled data to the output until it fails. The spool file is removed once all data is written.
+func (s *SpoolWriter) replay() error {
+	s.lastRetry = s.clock.Now()
+	r := bufio.NewReader(io.NewSectionReader(s.spool, s.offset, s.size-s.offset))
+	for s.pending > 0 {
+		var size [4]byte
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			return err
+		}
+		data := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(r, data); err != nil {
+			return err
+		}
+		n, err := s.w.Write(data[s.skip:])
+		if err != nil {
+			// Skip the part which was written on the next attempt
+			s.skip += n
+			return err
+		}
+		s.offset += int64(len(size) + len(data))
+		s.skip = 0
+		s.pending--
+	}
+
+	name := s.spool.Name()
+	s.spool.Close()
+	s.spool = nil
+	return os.Remove(name)
+}
@@ -0,0 +1,73 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+// flakyWriter fails while broken is set, writing only a part of data passed to the failing write.
+type flakyWriter struct {
+	b      bytes.Buffer
+	broken bool
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	if w.broken {
+		n := len(p) / 2
+		w.b.Write(p[:n])
+		w.broken = n == 0
+		if n > 0 {
+			return n, errors.New("broken pipe")
+		}
+		return 0, errors.New("broken pipe")
+	}
+	return w.b.Write(p)
+}
+
+func TestSpoolWriter(t *testing.T) {
+	t.Run("spool data while the output fails", func(t *testing.T) {
+		dir := t.TempDir()
+		w := &flakyWriter{}
+		s := log.NewSpoolWriter(w, dir)
+		l := log.New(s).WithOutputMode(log.PlainMode)
+
+		l.Print("foo")
+		w.broken = true
+		l.Print("bar")
+		l.Print("baz")
+		assert.Equal(t, int64(2), s.Pending())
+		assert.Len(t, listDir(t, dir), 1)
+		assert.Equal(t, int64(0), l.Stats().WriteErrors)
+
+		assert.NoError(t, s.Flush())
+		l.Print("qux")
+		assert.NoError(t, s.Close())
+
+		assert.Equal(t, "[INFO] foo\n[INFO] bar\n[INFO] baz\n[INFO] qux\n", w.b.String())
+		assert.Equal(t, int64(0), s.Pending())
+		assert.Empty(t, listDir(t, dir))
+	})
+
+	t.Run("keep the spool file if the output doesn't recover", func(t *testing.T) {
+		dir := t.TempDir()
+		s := log.NewSpoolWriter(failingWriter{}, dir)
+
+		s.Write([]byte("foo\n"))
+		assert.EqualError(t, s.Flush(), "broken pipe")
+		err := s.Close()
+
+		files := listDir(t, dir)
+		if assert.Len(t, files, 1) {
+			assert.EqualError(t, err, "1 writes left in "+filepath.Join(dir, files[0])+": broken pipe")
+		}
+		_, err = s.Write([]byte("bar\n"))
+		assert.Equal(t, os.ErrClosed, err)
+	})
+}
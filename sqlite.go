@@ -0,0 +1,144 @@
+package logger
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// SQLiteTimeLayout is the layout of times stored by SQLiteSink. Times are stored in UTC, so they can be compared as
+// text, e.g. "WHERE time >= '2021-06-01'".
+const SQLiteTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// SQLitePruneEvery is the number of records inserted by SQLiteSink between applying its retention policy.
+const SQLitePruneEvery = 1000
+
+var sqliteTableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SQLiteSink stores records in a SQLite database, so output of scheduled commands can be queried afterwards, e.g.:
+//
+//	db, err := sql.Open("sqlite3", "klio.db")
+//	sink, err := log.NewSQLiteSink(db, log.WithSQLiteRetention(30*24*time.Hour, 0))
+//	l = l.WithSink(sink)
+//
+// The sink doesn't depend on any particular driver, it uses the database handle it is given. Tags and fields are
+// stored as JSON arrays and objects.
+type SQLiteSink struct {
+	mu       sync.Mutex
+	db       *sql.DB
+	table    string
+	maxAge   time.Duration
+	maxRows  int
+	clock    Clock
+	inserted int
+}
+
+// SQLiteOption configures SQLiteSink, see NewSQLiteSink.
+type SQLiteOption func(*SQLiteSink)
+
+// WithSQLiteTable sets name of the table storing records, "logs" by default.
+func WithSQLiteTable(name string) SQLiteOption {
+	return func(s *SQLiteSink) {
+		s.table = name
+	}
+}
+
+// WithSQLiteRetention makes SQLiteSink delete records older than maxAge and all but the newest maxRows records.
+// Zero disables the limit. The retention policy is applied when the sink is created and every SQLitePruneEvery
+// inserted records.
+func WithSQLiteRetention(maxAge time.Duration, maxRows int) SQLiteOption {
+	return func(s *SQLiteSink) {
+		s.maxAge, s.maxRows = maxAge, maxRows
+	}
+}
+
+// WithSQLiteClock sets clock used to apply the retention policy.
+func WithSQLiteClock(c Clock) SQLiteOption {
+	return func(s *SQLiteSink) {
+		s.clock = c
+	}
+}
+
+// NewSQLiteSink creates the table storing records (unless it exists) and returns SQLiteSink writing to it.
+func NewSQLiteSink(db *sql.DB, opts ...SQLiteOption) (*SQLiteSink, error) {
+	s := &SQLiteSink{db: db, table: "logs", clock: systemClock{}}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if !sqliteTableName.MatchString(s.table) {
+		return nil, fmt.Errorf("invalid SQLite table name %q", s.table)
+	}
+
+	_, err := db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	time TEXT NOT NULL,
+	level TEXT NOT NULL,
+	tags TEXT NOT NULL,
+	message TEXT NOT NULL,
+	fields TEXT NOT NULL,
+	run_id TEXT NOT NULL
+)`, s.table))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS %[1]s_time ON %[1]s (time)", s.table)); err != nil {
+		return nil, err
+	}
+	if err := s.Prune(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// WriteRecord inserts the record, see Sink.
+func (s *SQLiteSink) WriteRecord(t time.Time, r Record) error {
+	tags := r.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	tagsJSON, err := json.Marshal(tags)
+	if err != nil {
+		return err
+	}
+	fieldsJSON, err := marshalFields(r.Fields)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(
+		fmt.Sprintf("INSERT INTO %s (time, level, tags, message, fields, run_id) VALUES (?, ?, ?, ?, ?, ?)", s.table),
+		t.UTC().Format(SQLiteTimeLayout), string(r.Level), string(tagsJSON), r.Message, string(fieldsJSON), runID,
+	)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.inserted++
+	prune := s.inserted%SQLitePruneEvery == 0
+	s.mu.Unlock()
+	if prune {
+		return s.Prune()
+	}
+	return nil
+}
+
+// Prune applies the retention policy, see WithSQLiteRetention.
+func (s *SQLiteSink) Prune() error {
+	if s.maxAge > 0 {
+		before := s.clock.Now().Add(-s.maxAge).UTC().Format(SQLiteTimeLayout)
+		if _, err := s.db.Exec(fmt.Sprintf("DELETE FROM %s WHERE time < ?", s.table), before); err != nil {
+			return err
+		}
+	}
+	if s.maxRows > 0 {
+		query := fmt.Sprintf("DELETE FROM %[1]s WHERE id <= (SELECT id FROM %[1]s ORDER BY id DESC LIMIT 1 OFFSET ?)", s.table)
+		if _, err := s.db.Exec(query, s.maxRows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
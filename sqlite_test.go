@@ -0,0 +1,105 @@
+package logger_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+// execDriver records statements executed using database/sql, it doesn't support queries.
+type execDriver struct {
+	mu    sync.Mutex
+	execs []string
+}
+
+func (d *execDriver) Open(name string) (driver.Conn, error) { return execConn{d}, nil }
+
+func (d *execDriver) Execs() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.execs...)
+}
+
+type execConn struct{ d *execDriver }
+
+func (c execConn) Prepare(query string) (driver.Stmt, error) { return execStmt{c.d, query}, nil }
+func (c execConn) Close() error                              { return nil }
+func (c execConn) Begin() (driver.Tx, error)                 { return nil, fmt.Errorf("not supported") }
+
+type execStmt struct {
+	d     *execDriver
+	query string
+}
+
+func (s execStmt) Close() error  { return nil }
+func (s execStmt) NumInput() int { return -1 }
+
+func (s execStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.execs = append(s.d.execs, strings.Join(strings.Fields(fmt.Sprint(s.query, args)), " "))
+	return driver.RowsAffected(1), nil
+}
+
+func (s execStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+var sqliteDriver = &execDriver{}
+
+func init() {
+	sql.Register("klio-exec", sqliteDriver)
+}
+
+func TestSQLiteSink(t *testing.T) {
+	db, err := sql.Open("klio-exec", "")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	t.Run("insert records", func(t *testing.T) {
+		sqliteDriver.execs = nil
+		sink, err := log.NewSQLiteSink(db, log.WithSQLiteTable("klio_logs"))
+		assert.NoError(t, err)
+
+		clock := &fakeClock{now: time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("CET", 3600))}
+		l := log.New(io.Discard).WithClock(clock).WithSink(sink)
+		l.WithTags("deploy").WithField("release", "api").Print("done")
+
+		assert.Equal(t, []string{
+			"CREATE TABLE IF NOT EXISTS klio_logs ( id INTEGER PRIMARY KEY AUTOINCREMENT, time TEXT NOT NULL, level TEXT NOT NULL, tags TEXT NOT NULL, message TEXT NOT NULL, fields TEXT NOT NULL, run_id TEXT NOT NULL )[]",
+			"CREATE INDEX IF NOT EXISTS klio_logs_time ON klio_logs (time)[]",
+			fmt.Sprintf(`INSERT INTO klio_logs (time, level, tags, message, fields, run_id) VALUES (?, ?, ?, ?, ?, ?)[2021-03-04T04:06:07.000000000Z info ["deploy"] done {"release":"api"} %s]`, log.RunID()),
+		}, sqliteDriver.Execs())
+	})
+
+	t.Run("apply retention", func(t *testing.T) {
+		sqliteDriver.execs = nil
+		clock := &fakeClock{now: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)}
+		sink, err := log.NewSQLiteSink(db, log.WithSQLiteRetention(24*time.Hour, 100), log.WithSQLiteClock(clock))
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"DELETE FROM logs WHERE time < ?[2021-03-03T05:06:07.000000000Z]",
+			"DELETE FROM logs WHERE id <= (SELECT id FROM logs ORDER BY id DESC LIMIT 1 OFFSET ?)[100]",
+		}, sqliteDriver.Execs()[2:])
+
+		for i := 0; i < log.SQLitePruneEvery; i++ {
+			assert.NoError(t, sink.WriteRecord(clock.now, log.Record{Level: log.InfoLevel, Message: "foo"}))
+		}
+		assert.Len(t, sqliteDriver.Execs(), 4+log.SQLitePruneEvery+2)
+	})
+
+	t.Run("reject invalid table names", func(t *testing.T) {
+		_, err := log.NewSQLiteSink(db, log.WithSQLiteTable("logs; DROP TABLE users"))
+		assert.EqualError(t, err, `invalid SQLite table name "logs; DROP TABLE users"`)
+	})
+}
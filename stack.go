@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"encoding/json"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// StackFrame describes a function call in a stack trace.
+type StackFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// StackTrace is a stack trace carried by a logged error. It is written as JSON, unless stack traces are rendered in
+// human readable output (see Logger.WithStackRendering).
+type StackTrace []StackFrame
+
+// String returns the stack trace as JSON.
+func (s StackTrace) String() string {
+	data, _ := json.Marshal([]StackFrame(s))
+	return string(data)
+}
+
+// StackRendering reports whether stack traces are rendered as indented lines in PlainMode and ColorMode.
+func (l *Logger) StackRendering() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.renderStacks
+}
+
+// WithStackRendering creates new logger instance which, in PlainMode and ColorMode, renders stack traces of errors as
+// indented lines below the message instead of JSON fields.
+func (l *Logger) WithStackRendering(enabled bool) *Logger {
+	n := l.clone()
+	n.renderStacks = enabled
+	return n
+}
+
+// errorStack returns stack trace of the error, if it carries one. Errors created by github.com/pkg/errors
+// (StackTrace method returning program counters) and errors with Callers() []uintptr method are supported.
+func errorStack(err error) StackTrace {
+	var pcs []uintptr
+	if c, ok := err.(interface{ Callers() []uintptr }); ok {
+		pcs = c.Callers()
+	} else if m := reflect.ValueOf(err).MethodByName("StackTrace"); m.IsValid() {
+		t := m.Type()
+		if t.NumIn() != 0 || t.NumOut() != 1 || t.Out(0).Kind() != reflect.Slice || t.Out(0).Elem().Kind() != reflect.Uintptr {
+			return nil
+		}
+		v := m.Call(nil)[0]
+		pcs = make([]uintptr, v.Len())
+		for i := range pcs {
+			pcs[i] = uintptr(v.Index(i).Uint())
+		}
+	}
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	var stack StackTrace
+	frames := runtime.CallersFrames(pcs)
+	for {
+		f, more := frames.Next()
+		stack = append(stack, StackFrame{Function: f.Function, File: f.File, Line: f.Line})
+		if !more {
+			return stack
+		}
+	}
+}
+
+// renderStacks moves fields holding stack traces to indented lines, e.g. "\n    at main.main (main.go:12)".
+func renderStacks(fields []Field) (rest []Field, lines string) {
+	var b strings.Builder
+	for _, f := range fields {
+		stack, ok := f.Value.(StackTrace)
+		if !ok {
+			rest = append(rest, f)
+			continue
+		}
+		b.WriteString("\n  " + f.Key + ":")
+		for _, frame := range stack {
+			b.WriteString("\n    at " + frame.Function + " (" + frame.File + ":" + strconv.Itoa(frame.Line) + ")")
+		}
+	}
+	return rest, b.String()
+}
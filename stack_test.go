@@ -0,0 +1,93 @@
+package logger_test
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+// frame mimics github.com/pkg/errors.Frame.
+type frame uintptr
+
+// stackError mimics errors created by github.com/pkg/errors.
+type stackError struct {
+	msg   string
+	stack []uintptr
+}
+
+func newStackError(msg string) error {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	return &stackError{msg: msg, stack: pcs[:n]}
+}
+
+func (e *stackError) Error() string {
+	return e.msg
+}
+
+func (e *stackError) Format(s fmt.State, verb rune) {
+	fmt.Fprint(s, e.msg)
+	if s.Flag('+') {
+		fmt.Fprint(s, "\nnoisy stack trace")
+	}
+}
+
+func (e *stackError) StackTrace() []frame {
+	r := make([]frame, len(e.stack))
+	for i, pc := range e.stack {
+		r[i] = frame(pc)
+	}
+	return r
+}
+
+func TestErrorStack(t *testing.T) {
+	err := fmt.Errorf("deploying: %w", newStackError("boom"))
+
+	t.Run("attach stack as field", func(t *testing.T) {
+		fields := log.New(nil).WithError(err).Fields()
+
+		assert.Len(t, fields, 4)
+		assert.Equal(t, "error_chain", fields[2].Key)
+		assert.Equal(t, log.ErrorChain{
+			{Message: "deploying: boom", Type: "*fmt.wrapError"},
+			{Message: "boom", Type: "*logger_test.stackError"},
+		}, fields[2].Value)
+		assert.Equal(t, "error_stack", fields[3].Key)
+		if stack, ok := fields[3].Value.(log.StackTrace); assert.True(t, ok) {
+			assert.Equal(t, "github.com/g2a-com/klio-logger-go_test.TestErrorStack", stack[0].Function)
+			assert.Regexp(t, regexp.MustCompile(`stack_test\.go$`), stack[0].File)
+		}
+	})
+
+	t.Run("write stack as json", func(t *testing.T) {
+		var b bytes.Buffer
+		log.New(&b).WithOutputMode(log.PlainMode).WithError(err).Print("failed")
+
+		assert.Regexp(t, regexp.MustCompile(`error_stack="\[\{\\"function\\":\\"github.com/g2a-com/klio-logger-go_test.TestErrorStack\\",`), b.String())
+	})
+
+	t.Run("render stack in human output", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.PlainMode).WithStackRendering(true)
+		assert.True(t, l.StackRendering())
+
+		l.WithError(newStackError("boom")).WithField("release", "api").Print("failed")
+
+		assert.Regexp(t, regexp.MustCompile(`^\[INFO\] failed error=boom error_type=\*logger_test.stackError release=api\n`+
+			`  error_stack:\n`+
+			`    at github.com/g2a-com/klio-logger-go_test.TestErrorStack.func3 \(.+/stack_test.go:\d+\)\n`), b.String())
+	})
+
+	t.Run("don't render stack in klio output", func(t *testing.T) {
+		var b bytes.Buffer
+		log.New(&b).WithOutputMode(log.KlioMode).WithStackRendering(true).WithError(err).Print("failed")
+
+		assert.Contains(t, b.String(), `error_stack="[`)
+	})
+}
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+)
+
+// Stats describes health of a logger. Statistics are shared by a logger and all loggers derived from it.
+type Stats struct {
+	// QueueDepth is the number of lines waiting to be written by an asynchronous logger.
+	QueueDepth int `json:"queue_depth"`
+	// Dropped is the number of lines dropped by an asynchronous logger.
+	Dropped int64 `json:"dropped"`
+	// WriteErrors is the number of writes to the output (or sinks) which failed, including lines rejected because of
+	// strict TagLimits.
+	WriteErrors int64 `json:"write_errors"`
+	// BytesWritten is the number of bytes written to the output.
+	BytesWritten int64 `json:"bytes_written"`
+}
+
+type stats struct {
+	dropped      int64
+	writeErrors  int64
+	bytesWritten int64
+	sizes        histogram
+	latency      histogram
+	sinksMu      sync.Mutex
+	sinkLatency  map[string]*histogram
+}
+
+// record updates statistics with result of a write.
+func (s *stats) record(n int, err error) {
+	atomic.AddInt64(&s.bytesWritten, int64(n))
+	if err != nil {
+		atomic.AddInt64(&s.writeErrors, 1)
+	}
+}
+
+// Stats returns statistics of a logger.
+func (l *Logger) Stats() Stats {
+	l.mu.RLock()
+	s, output := l.stats, l.output
+	l.mu.RUnlock()
+
+	r := Stats{
+		Dropped:      atomic.LoadInt64(&s.dropped),
+		WriteErrors:  atomic.LoadInt64(&s.writeErrors),
+		BytesWritten: atomic.LoadInt64(&s.bytesWritten),
+	}
+	if a, ok := output.(*asyncWriter); ok {
+		r.QueueDepth = len(a.queue)
+	}
+	return r
+}
+
+// StatsVar returns variable reporting current statistics of a logger as JSON, which can be published using expvar
+// (the package isn't imported, since it registers HTTP handlers):
+//
+//	expvar.Publish("logger", l.StatsVar())
+func (l *Logger) StatsVar() StatsVar {
+	return StatsVar{l: l}
+}
+
+// StatsVar reports statistics of a logger, it implements expvar.Var.
+type StatsVar struct {
+	l *Logger
+}
+
+// String returns current statistics as JSON.
+func (v StatsVar) String() string {
+	data, _ := json.Marshal(v.l.Stats())
+	return string(data)
+}
@@ -0,0 +1,68 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestStats(t *testing.T) {
+	t.Run("count written bytes", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.PlainMode)
+
+		l.Print("foo")
+		l.WithTags("bar").Print("baz")
+
+		assert.Equal(t, log.Stats{BytesWritten: 27}, l.Stats())
+	})
+
+	t.Run("count write errors", func(t *testing.T) {
+		l := log.New(failingWriter{})
+
+		l.Print("foo")
+
+		assert.Equal(t, log.Stats{WriteErrors: 1}, l.Stats())
+	})
+
+	t.Run("count dropped lines", func(t *testing.T) {
+		b := newBlockingWriter()
+		defer b.unblock()
+		l := log.New(b).WithOutputMode(log.PlainMode).WithNonBlocking(1)
+
+		for i := 0; i < 5; i++ {
+			l.Print("line")
+		}
+		s := l.Stats()
+		assert.Equal(t, 1, s.QueueDepth)
+		assert.GreaterOrEqual(t, s.Dropped, int64(3))
+
+		b.unblock()
+		log.Flush()
+		assert.Equal(t, 0, l.Stats().QueueDepth)
+		assert.Greater(t, l.Stats().BytesWritten, int64(0))
+	})
+
+	t.Run("publish stats", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.PlainMode)
+		expvar.Publish("klio_logger_test", l.StatsVar())
+		l.Print("foo")
+
+		var s log.Stats
+		assert.NoError(t, json.Unmarshal([]byte(expvar.Get("klio_logger_test").String()), &s))
+		assert.Equal(t, log.Stats{BytesWritten: 11}, s)
+	})
+}
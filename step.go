@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Step is a unit of work which start and end are logged, see Logger.Step.
+type Step struct {
+	l       *Logger
+	name    string
+	start   time.Time
+	once    sync.Once
+	restore func()
+}
+
+// Step logs start of a step and returns Step which should be finished using Done or Fail, e.g.:
+//
+//	step := l.Step("Pushing image")
+//	if err := push(); err != nil {
+//		step.Fail(err)
+//		return err
+//	}
+//	step.Done()
+//
+// Start and end of the step are written as regular lines, no group control sequences are emitted, since this package
+// only writes sequences setting level and tags of a line (see README). Use WithTags to group lines of steps.
+func (l *Logger) Step(name string) *Step {
+	s := &Step{l: l, name: name, start: l.Clock().Now()}
+	s.l, s.restore = l.startProfilerStep(name)
+	l.Print(name, "...")
+	return s
+}
+
+// Logger returns logger which should be used to log messages related to the step.
+func (s *Step) Logger() *Logger {
+	return s.l
+}
+
+// Done logs successful end of the step together with its duration, e.g. "✓ Pushing image (1.2s)". Only the first call
+// to Done or Fail has an effect.
+func (s *Step) Done() {
+	s.once.Do(func() {
+		msg := fmt.Sprintf("%s (%s)", s.name, formatDuration(s.l.since(s.start)))
+		s.l.mark(s.l.Level(), SuccessMarker, successColor, msg)
+		s.finish()
+	})
+}
+
+// Fail logs failure of the step at error level together with its duration and the error, e.g.
+// "✗ Pushing image (1.2s): connection refused". Only the first call to Done or Fail has an effect.
+func (s *Step) Fail(err error) {
+	s.once.Do(func() {
+		if err != nil {
+			s.l.Failuref("%s (%s): %v", s.name, formatDuration(s.l.since(s.start)), err)
+		} else {
+			s.l.Failuref("%s (%s)", s.name, formatDuration(s.l.since(s.start)))
+		}
+		s.finish()
+	})
+}
+
+// finish restores pprof labels changed by the step, see WithProfilerLabels.
+func (s *Step) finish() {
+	if s.restore != nil {
+		s.restore()
+	}
+}
+
+// formatDuration rounds duration to make it readable, e.g. "1.2s" or "15ms".
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	case d < time.Minute:
+		return d.Round(100 * time.Millisecond).String()
+	default:
+		return d.Round(time.Second).String()
+	}
+}
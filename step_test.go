@@ -0,0 +1,32 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestStep(t *testing.T) {
+	t.Run("log successful step", func(t *testing.T) {
+		var b bytes.Buffer
+		step := log.New(&b).WithOutputMode(log.PlainMode).Step("Pushing image")
+		step.Done()
+		step.Fail(errors.New("ignored"))
+
+		assert.Regexp(t, regexp.MustCompile(`^\[INFO\] Pushing image\.\.\.\n\[INFO\] ✓ Pushing image \(\d+(\.\d+)?(ms|s)\)\n$`), b.String())
+	})
+
+	t.Run("log failed step", func(t *testing.T) {
+		var b bytes.Buffer
+		step := log.New(&b).WithOutputMode(log.PlainMode).Step("Pushing image")
+		step.Fail(errors.New("boom"))
+		step.Done()
+
+		assert.Regexp(t, regexp.MustCompile(`^\[INFO\] Pushing image\.\.\.\n\[ERROR\] ✗ Pushing image \(\d+(\.\d+)?(ms|s)\): boom\n$`), b.String())
+	})
+}
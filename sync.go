@@ -0,0 +1,58 @@
+package logger
+
+import "io"
+
+// WithSync creates new logger instance which waits until its lines are stored durably before Print returns: queues of
+// asynchronous loggers (see WithAsync) and buffers are flushed, files are synced (fsync) and sinks supporting it are
+// flushed. If levels are specified, only lines at these levels are synced, e.g.:
+//
+//	audit := l.WithSync()                          // sync every line
+//	l = l.WithSync(log.ErrorLevel, log.FatalLevel) // sync only errors
+//
+// Errors of syncing are counted in Stats.WriteErrors.
+func (l *Logger) WithSync(levels ...Level) *Logger {
+	n := l.clone()
+	n.syncAll = len(levels) == 0
+	n.syncLevels = make(map[Level]bool, len(levels))
+	for _, level := range levels {
+		n.syncLevels[level] = true
+	}
+	return n
+}
+
+// Sync waits until lines already written by a logger are stored durably, see WithSync.
+func (l *Logger) Sync() error {
+	l.mu.RLock()
+	output, sinks := l.output, l.sinks
+	l.mu.RUnlock()
+	return syncOutputs(output, sinks)
+}
+
+// syncOutputs flushes and syncs the output and sinks, it returns the first error.
+func syncOutputs(output io.Writer, sinks []Sink) error {
+	err := syncOutput(output)
+	for _, s := range sinks {
+		if e := syncOutput(s); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func syncOutput(w interface{}) error {
+	switch w := w.(type) {
+	case *asyncWriter:
+		w.Flush()
+		return syncOutput(w.w)
+	case *DeadlineWriter:
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		return syncOutput(w.w)
+	case interface{ Sync() error }:
+		return w.Sync()
+	case interface{ Flush() error }:
+		return w.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+type syncedWriter struct {
+	mu     sync.Mutex
+	b      bytes.Buffer
+	synced []string
+	err    error
+}
+
+func (w *syncedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.b.Write(p)
+}
+
+func (w *syncedWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.synced = append(w.synced, w.b.String())
+	return w.err
+}
+
+type flushingSink struct {
+	recordingSink
+	flushed int
+}
+
+func (s *flushingSink) Flush() error {
+	s.flushed++
+	return nil
+}
+
+func TestWithSync(t *testing.T) {
+	t.Run("sync every line", func(t *testing.T) {
+		w := &syncedWriter{}
+		sink := &flushingSink{}
+		l := log.New(w).WithOutputMode(log.PlainMode).WithSink(sink)
+
+		l.Print("foo")
+		l.WithSync().Print("bar")
+
+		assert.Equal(t, []string{"[INFO] foo\n[INFO] bar\n"}, w.synced)
+		assert.Equal(t, 1, sink.flushed)
+	})
+
+	t.Run("sync lines at selected levels", func(t *testing.T) {
+		w := &syncedWriter{}
+		l := log.New(w).WithOutputMode(log.PlainMode).WithSync(log.ErrorLevel)
+
+		l.Print("foo")
+		l.WithLevel(log.ErrorLevel).Print("bar")
+		l.WithLevel(log.WarnLevel).Print("baz")
+
+		assert.Equal(t, []string{"[INFO] foo\n[ERROR] bar\n"}, w.synced)
+	})
+
+	t.Run("flush asynchronous queue", func(t *testing.T) {
+		w := &syncedWriter{}
+		l := log.New(w).WithOutputMode(log.PlainMode).WithAsync(10).WithSync()
+
+		l.Print("foo")
+
+		assert.Equal(t, []string{"[INFO] foo\n"}, w.synced)
+	})
+
+	t.Run("sync files", func(t *testing.T) {
+		f, err := log.OpenFile(filepath.Join(t.TempDir(), "audit.log"))
+		assert.NoError(t, err)
+		l := log.New(f).WithSync()
+
+		l.Print("foo")
+		assert.NoError(t, l.Sync())
+		assert.NoError(t, f.Close())
+		assert.Equal(t, os.ErrClosed, l.Sync())
+	})
+
+	t.Run("count errors", func(t *testing.T) {
+		w := &syncedWriter{err: errors.New("i/o error")}
+		l := log.New(w).WithSync()
+
+		l.Print("foo")
+
+		assert.Equal(t, int64(1), l.Stats().WriteErrors)
+	})
+}
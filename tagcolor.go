@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"hash/fnv"
+	"strings"
+)
+
+// tagPalette lists colors (SGR parameters) assigned to tags. Red is left for errors.
+var tagPalette = []string{"32", "33", "34", "35", "36", "92", "93", "94", "95", "96"}
+
+// TagColors returns colors assigned to tags by WithTagColors.
+func (l *Logger) TagColors() map[string]string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	r := make(map[string]string, len(l.tagColors))
+	for k, v := range l.tagColors {
+		r[k] = v
+	}
+	return r
+}
+
+// WithTagColors creates new logger instance using specified colors for tags in ColorMode. Colors are SGR parameters of
+// ANSI escape sequences, e.g. "35" (magenta) or "1;34" (bold blue), and tags are matched case insensitively. Other tags
+// get colors based on a hash of their names, so they are the same in every run.
+func (l *Logger) WithTagColors(colors map[string]string) *Logger {
+	n := l.clone()
+	n.tagColors = make(map[string]string, len(colors))
+	for k, v := range colors {
+		n.tagColors[strings.ToLower(k)] = v
+	}
+	n.updateLinePrefix()
+	return n
+}
+
+// tagColor returns escape sequence setting color of the tag.
+func tagColor(tag string, overrides map[string]string) string {
+	tag = strings.ToLower(tag)
+	if c, ok := overrides[tag]; ok {
+		return "\033[" + c + "m"
+	}
+	h := fnv.New32a()
+	h.Write([]byte(tag))
+	return "\033[" + tagPalette[h.Sum32()%uint32(len(tagPalette))] + "m"
+}
@@ -0,0 +1,41 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestTagColors(t *testing.T) {
+	t.Run("color tags based on names", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.ColorMode)
+
+		l.WithTags("foo", "bar").Print("baz")
+		l.WithTags("FOO").Print("qux")
+
+		assert.Equal(t, ""+
+			"[INFO]\033[35m[FOO]\033[0m\033[34m[BAR]\033[0m baz\n"+
+			"[INFO]\033[35m[FOO]\033[0m qux\n", b.String())
+	})
+
+	t.Run("override colors", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.ColorMode).WithTagColors(map[string]string{"Foo": "1;34"})
+
+		l.WithTags("foo").Print("bar")
+
+		assert.Equal(t, map[string]string{"foo": "1;34"}, l.TagColors())
+		assert.Equal(t, "[INFO]\033[1;34m[FOO]\033[0m bar\n", b.String())
+	})
+
+	t.Run("don't color tags in plain mode", func(t *testing.T) {
+		var b bytes.Buffer
+		log.New(&b).WithOutputMode(log.PlainMode).WithTags("foo").Print("bar")
+
+		assert.Equal(t, "[INFO][FOO] bar\n", b.String())
+	})
+}
@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// TagHint describes how a tag should be displayed, see WithTagHints.
+type TagHint struct {
+	// Name of the tag, matched case insensitively.
+	Name string `json:"name"`
+	// Color of the tag: black, red, green, yellow, blue, magenta, cyan, white or gray. Empty leaves it to Klio.
+	Color string `json:"color,omitempty"`
+	// Hidden tags are not displayed, but they can still be used for filtering.
+	Hidden bool `json:"hidden"`
+}
+
+// hintColors maps colors of TagHint to SGR parameters used in ColorMode.
+var hintColors = map[string]string{
+	"black":   "30",
+	"red":     "31",
+	"green":   "32",
+	"yellow":  "33",
+	"blue":    "34",
+	"magenta": "35",
+	"cyan":    "36",
+	"white":   "37",
+	"gray":    "90",
+}
+
+// WithProtocolVersion creates new logger instance writing lines using specified version of Klio output protocol. By
+// default loggers use ProtocolVersion. Version 2 allows tags to carry display hints (see WithTagHints), it should be
+// used only if Klio running the command supports it.
+func (l *Logger) WithProtocolVersion(version int) *Logger {
+	n := l.clone()
+	n.protocol = version
+	n.updateLinePrefix()
+	return n
+}
+
+// WithTagHints creates new logger instance with display hints of tags, replacing hints of the same tags:
+//
+//	l = l.WithProtocolVersion(2).WithTagHints(log.TagHint{Name: "helm", Color: "blue"})
+//
+// Using protocol version 2, tags with hints are written as objects, e.g. {"name":"helm","color":"blue","hidden":false},
+// instead of strings. Hints are also applied to lines written in PlainMode and ColorMode (colors set by WithTagColors
+// take precedence).
+func (l *Logger) WithTagHints(hints ...TagHint) *Logger {
+	n := l.clone()
+	n.tagHints = make(map[string]TagHint, len(l.tagHints)+len(hints))
+	for k, v := range l.tagHints {
+		n.tagHints[k] = v
+	}
+	for _, h := range hints {
+		n.tagHints[strings.ToLower(h.Name)] = h
+	}
+	n.updateLinePrefix()
+	return n
+}
+
+// encodeTags returns tags encoded for the tag sequence.
+func (l *Logger) encodeTags(tags []string) ([]byte, error) {
+	if l.protocol < 2 || len(l.tagHints) == 0 {
+		if tags == nil {
+			tags = []string{}
+		}
+		return json.Marshal(tags)
+	}
+	r := make([]interface{}, len(tags))
+	for i, tag := range tags {
+		if h, ok := l.tagHints[strings.ToLower(tag)]; ok {
+			h.Name = tag
+			r[i] = h
+		} else {
+			r[i] = tag
+		}
+	}
+	return json.Marshal(r)
+}
+
+// humanTags returns tags displayed in PlainMode and ColorMode, with colors used for them.
+func (l *Logger) humanTags(tags []string) ([]string, map[string]string) {
+	if len(l.tagHints) == 0 {
+		return tags, l.tagColors
+	}
+	visible := make([]string, 0, len(tags))
+	colors := make(map[string]string, len(l.tagColors))
+	for _, tag := range tags {
+		h, ok := l.tagHints[strings.ToLower(tag)]
+		if ok && h.Hidden {
+			continue
+		}
+		if c, known := hintColors[strings.ToLower(h.Color)]; ok && known {
+			colors[strings.ToLower(tag)] = c
+		}
+		visible = append(visible, tag)
+	}
+	for k, v := range l.tagColors {
+		colors[k] = v
+	}
+	return visible, colors
+}
+
+// decodeTags decodes the argument of the tag sequence, in which tags can be strings or objects with display hints.
+func decodeTags(arg string) ([]string, bool) {
+	var items []json.RawMessage
+	if json.Unmarshal([]byte(arg), &items) != nil || items == nil {
+		return nil, false
+	}
+	tags := make([]string, 0, len(items))
+	for _, item := range items {
+		var tag string
+		if json.Unmarshal(item, &tag) != nil {
+			var h TagHint
+			if json.Unmarshal(item, &h) != nil {
+				return nil, false
+			}
+			tag = h.Name
+		}
+		tags = append(tags, tag)
+	}
+	return tags, true
+}
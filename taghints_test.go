@@ -0,0 +1,60 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestWithTagHints(t *testing.T) {
+	hints := []log.TagHint{{Name: "Helm", Color: "blue"}, {Name: "internal", Hidden: true}}
+
+	t.Run("write hints using protocol version 2", func(t *testing.T) {
+		var b bytes.Buffer
+
+		log.New(&b).WithProtocolVersion(2).WithTagHints(hints...).WithTags("helm", "internal", "eu").Print("foo")
+
+		assert.Equal(t, "\033_klio_log_level \"info\"\033\\\033_klio_tags ["+
+			`{"name":"helm","color":"blue","hidden":false},{"name":"internal","hidden":true},"eu"`+
+			"]\033\\foo\033_klio_reset\033\\\n", b.String())
+	})
+
+	t.Run("write plain tags using protocol version 1", func(t *testing.T) {
+		var b bytes.Buffer
+
+		log.New(&b).WithTagHints(hints...).WithTags("helm", "internal").Print("foo")
+
+		assert.Equal(t, "\033_klio_log_level \"info\"\033\\\033_klio_tags [\"helm\",\"internal\"]\033\\foo\033_klio_reset\033\\\n", b.String())
+	})
+
+	t.Run("apply hints to human readable lines", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithTagHints(hints...).WithTags("helm", "internal", "foo")
+
+		l.WithOutputMode(log.PlainMode).Print("bar")
+		l.WithOutputMode(log.ColorMode).WithTags("helm").Print("baz")
+		l.WithOutputMode(log.ColorMode).WithTagColors(map[string]string{"helm": "35"}).WithTags("helm").Print("qux")
+
+		assert.Equal(t, "[INFO][HELM][FOO] bar\n"+
+			"[INFO]\033[34m[HELM]\033[0m baz\n"+
+			"[INFO]\033[35m[HELM]\033[0m qux\n", b.String())
+	})
+
+	t.Run("decode tags with hints", func(t *testing.T) {
+		var b bytes.Buffer
+		log.New(&b).WithProtocolVersion(2).WithTagHints(hints...).WithTags("helm", "eu").Print("foo")
+
+		r, ok := log.ParseLine(b.String()[:b.Len()-1])
+
+		assert.True(t, ok)
+		assert.Equal(t, []string{"helm", "eu"}, r.Tags)
+		assert.Equal(t, "foo", r.Message)
+	})
+
+	t.Run("pass protocol version to child commands", func(t *testing.T) {
+		assert.Contains(t, log.ChildEnv(log.New(nil).WithProtocolVersion(2)), "KLIO_LOG_PROTOCOL=2")
+	})
+}
@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrTagLimit is wrapped by errors reported for tags exceeding limits, see TagLimits.
+var ErrTagLimit = errors.New("tag limit exceeded")
+
+// TagLimits protects Klio from pathological tags, e.g. built from user input. Zero disables a limit.
+type TagLimits struct {
+	// MaxTags is the maximum number of tags of a line.
+	MaxTags int
+	// MaxLength is the maximum length of a tag in bytes.
+	MaxLength int
+	// Strict makes loggers count lines with tags exceeding limits in Stats.WriteErrors and append the error to their
+	// messages. Tags are truncated in both modes, so no line is lost.
+	Strict bool
+}
+
+// WithTagLimits creates new logger instance enforcing limits of tags of written lines. Excess tags are dropped and long
+// tags are truncated, lines themselves are always written (with a note about exceeded limits in strict mode). Use
+// CheckTags to validate tags before using them.
+func (l *Logger) WithTagLimits(limits TagLimits) *Logger {
+	n := l.clone()
+	n.tagLimits = limits
+	n.updateLinePrefix()
+	return n
+}
+
+// CheckTags returns an error wrapping ErrTagLimit if tags of the logger combined with specified tags exceed limits set
+// by WithTagLimits.
+func (l *Logger) CheckTags(tags ...string) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.tagLimits.check(l.combineTags(tags))
+}
+
+// lineTags returns tags of lines written by a logger, see combineTags, truncated to fit in limits. In strict mode it
+// also returns an error if they had to be truncated.
+func (l *Logger) lineTags(extra []string) ([]string, error) {
+	tags := l.combineTags(extra)
+	var err error
+	if l.tagLimits.Strict {
+		err = l.tagLimits.check(tags)
+	}
+	return l.tagLimits.truncate(tags), err
+}
+
+// check returns an error if tags exceed limits.
+func (t TagLimits) check(tags []string) error {
+	if t.MaxTags > 0 && len(tags) > t.MaxTags {
+		return fmt.Errorf("%w: %d tags, at most %d allowed", ErrTagLimit, len(tags), t.MaxTags)
+	}
+	if t.MaxLength > 0 {
+		for _, tag := range tags {
+			if len(tag) > t.MaxLength {
+				return fmt.Errorf("%w: tag %q is longer than %d bytes", ErrTagLimit, truncateUTF8(tag, t.MaxLength)+truncatedSuffix, t.MaxLength)
+			}
+		}
+	}
+	return nil
+}
+
+// truncate returns tags fitting in limits. Tags are copied only if they need to be changed.
+func (t TagLimits) truncate(tags []string) []string {
+	if t.MaxTags > 0 && len(tags) > t.MaxTags {
+		tags = tags[:t.MaxTags:t.MaxTags]
+	}
+	if t.MaxLength <= 0 {
+		return tags
+	}
+	copied := false
+	for i, tag := range tags {
+		if len(tag) > t.MaxLength {
+			if !copied {
+				tags = append([]string(nil), tags...)
+				copied = true
+			}
+			tags[i] = truncateUTF8(tag, t.MaxLength)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,46 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestWithTagLimits(t *testing.T) {
+	t.Run("truncate tags", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.PlainMode).WithTagLimits(log.TagLimits{MaxTags: 2, MaxLength: 4})
+
+		l.WithTags("a", "bcdefg", "h").Print("foo")
+		l.WithTags("zażółć").PrintT([]string{"i", "j"}, "bar")
+
+		assert.Equal(t, "[INFO][A][BCDE] foo\n[INFO][ZAŻ][I] bar\n", b.String())
+		assert.Zero(t, l.Stats().WriteErrors)
+	})
+
+	t.Run("report exceeded limits in strict mode", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.PlainMode).WithTagLimits(log.TagLimits{MaxTags: 2, MaxLength: 4, Strict: true})
+
+		l.WithTags("a", "b", "c").Print("foo")
+		l.WithTags("bcdefg").WithLevel(log.ErrorLevel).Print("bar")
+		l.WithTags("a").PrintT([]string{"b"}, "baz")
+
+		assert.Equal(t, ""+
+			"[INFO][A][B] foo (tag limit exceeded: 3 tags, at most 2 allowed)\n"+
+			"[ERROR][BCDE] bar (tag limit exceeded: tag \"bcde…\" is longer than 4 bytes)\n"+
+			"[INFO][A][B] baz\n", b.String())
+		assert.Equal(t, int64(2), l.Stats().WriteErrors)
+	})
+
+	t.Run("check tags", func(t *testing.T) {
+		l := log.New(nil).WithTags("a").WithTagLimits(log.TagLimits{MaxTags: 2, MaxLength: 4})
+
+		assert.NoError(t, l.CheckTags("b"))
+		assert.ErrorIs(t, l.CheckTags("b", "c"), log.ErrTagLimit)
+		assert.EqualError(t, l.CheckTags("bcdefg"), "tag limit exceeded: tag \"bcde…\" is longer than 4 bytes")
+	})
+}
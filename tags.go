@@ -0,0 +1,93 @@
+package logger
+
+import "strings"
+
+// WithAppendedTags creates new logger instance with tags added after tags of the current logger. Tags of each line are
+// always combined in the same order:
+//
+//  1. the name of a logger (see WithName),
+//  2. tags of a logger, in the order they were specified (WithTags replaces them, WithAppendedTags adds them after
+//     inherited ones),
+//  3. tags passed to PrintT and PrintfT.
+//
+// Duplicates are kept unless a logger is created using WithTagDedup.
+func (l *Logger) WithAppendedTags(tags ...string) *Logger {
+	n := l.clone()
+	n.tags = append(n.tags[:len(n.tags):len(n.tags)], prefixTags(n.tagPrefix, tags)...)
+	n.updateLinePrefix()
+	return n
+}
+
+// WithTagDedup creates new logger instance removing duplicated tags (compared case-insensitively, since Klio renders
+// tags in uppercase) from written lines. The first occurrence of each tag is kept, so the order described in
+// WithAppendedTags is preserved.
+func (l *Logger) WithTagDedup() *Logger {
+	n := l.clone()
+	n.dedupTags = true
+	n.updateLinePrefix()
+	return n
+}
+
+// WithTagPrefix creates new logger instance adding the prefix to tags specified later (using WithTags,
+// WithAppendedTags, PrintT or PrintfT), so tags added by a subsystem or a library don't collide with tags of others:
+//
+//	l = l.WithTagPrefix("deploy:").WithAppendedTags("eu") // tags: [..., "deploy:eu"]
+//
+// Tags of the current logger are left unchanged. Prefixes are nested, e.g. WithTagPrefix("a:").WithTagPrefix("b:")
+// adds "a:b:".
+func (l *Logger) WithTagPrefix(prefix string) *Logger {
+	n := l.clone()
+	n.tagPrefix += prefix
+	return n
+}
+
+// combineTags returns tags of a logger followed by extra tags, deduplicated if enabled.
+func (l *Logger) combineTags(extra []string) []string {
+	tags := l.tags
+	if l.name != "" {
+		tags = append([]string{l.name}, tags...)
+	}
+	if len(extra) > 0 {
+		tags = append(tags[:len(tags):len(tags)], prefixTags(l.tagPrefix, extra)...)
+	}
+	if l.dedupTags {
+		tags = dedupTags(tags)
+	}
+	return tags
+}
+
+// dedupTags removes case-insensitive duplicates, keeping the first occurrence. It returns tags unchanged if there are
+// no duplicates.
+func dedupTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	var r []string
+	for i, tag := range tags {
+		key := strings.ToLower(tag)
+		if seen[key] {
+			if r == nil {
+				r = append(make([]string, 0, len(tags)), tags[:i]...)
+			}
+			continue
+		}
+		seen[key] = true
+		if r != nil {
+			r = append(r, tag)
+		}
+	}
+	if r == nil {
+		return tags
+	}
+	return r
+}
+
+// prefixTags returns tags with the prefix added.
+func prefixTags(prefix string, tags []string) []string {
+	if prefix == "" {
+		return tags
+	}
+	r := make([]string, len(tags))
+	for i, tag := range tags {
+		r[i] = prefix + tag
+	}
+	return r
+}
@@ -0,0 +1,101 @@
+package logger_test
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestWithAppendedTags(t *testing.T) {
+	t.Run("append tags to inherited ones", func(t *testing.T) {
+		var b bytes.Buffer
+		base := log.New(&b).WithOutputMode(log.PlainMode).WithTags("a")
+
+		l := base.WithAppendedTags("b").WithAppendedTags("c")
+		l.Print("foo")
+		base.Print("bar")
+
+		assert.Equal(t, []string{"a", "b", "c"}, l.Tags())
+		assert.Equal(t, "[INFO][A][B][C] foo\n[INFO][A] bar\n", b.String())
+	})
+
+	t.Run("combine name, logger tags and per-call tags in order", func(t *testing.T) {
+		var b bytes.Buffer
+
+		log.New(&b).WithOutputMode(log.PlainMode).WithName("deploy").WithTags("a").WithAppendedTags("b").PrintT([]string{"c"}, "foo")
+
+		assert.Equal(t, "[INFO][DEPLOY][A][B][C] foo\n", b.String())
+	})
+
+	t.Run("keep duplicates by default", func(t *testing.T) {
+		var b bytes.Buffer
+
+		log.New(&b).WithOutputMode(log.PlainMode).WithTags("a").WithAppendedTags("a").Print("foo")
+
+		assert.Equal(t, "[INFO][A][A] foo\n", b.String())
+	})
+}
+
+func TestWithTagDedup(t *testing.T) {
+	t.Run("remove duplicates keeping first occurrences", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithTagDedup().WithName("deploy").WithTags("Helm", "deploy", "eu").WithAppendedTags("helm")
+
+		l.PrintT([]string{"EU", "api"}, "foo")
+
+		assert.Equal(t, "\033_klio_log_level \"info\"\033\\\033_klio_tags [\"deploy\",\"Helm\",\"eu\",\"api\"]\033\\foo\033_klio_reset\033\\\n", b.String())
+	})
+
+	t.Run("pass deduplicated tags to records", func(t *testing.T) {
+		var records []log.Record
+		l := log.New(nil).WithTagDedup().WithTags("a", "A").WithFilter(func(r log.Record) bool {
+			records = append(records, r)
+			return false
+		})
+
+		l.PrintT([]string{"b", "a"}, "foo")
+
+		if assert.Len(t, records, 1) {
+			assert.Equal(t, []string{"a", "b"}, records[0].Tags)
+		}
+	})
+}
+
+func TestWithTagPrefix(t *testing.T) {
+	t.Run("prefix tags added later", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.PlainMode).WithTags("release").WithTagPrefix("deploy:")
+
+		l.WithAppendedTags("eu").Print("foo")
+		l.WithTags("us").PrintT([]string{"api"}, "bar")
+
+		assert.Equal(t, "[INFO][RELEASE][DEPLOY:EU] foo\n[INFO][DEPLOY:US][DEPLOY:API] bar\n", b.String())
+	})
+
+	t.Run("prefix tags appended by run ID, Retag and Ingester once", func(t *testing.T) {
+		var b bytes.Buffer
+		l := log.New(&b).WithOutputMode(log.PlainMode).WithTagPrefix("d:").WithTags("a")
+
+		l.WithRunID().Print("foo")
+		w := log.Retag(l)
+		w.Write([]byte("\033_klio_log_level \"warn\"\033\\\033_klio_tags [\"b\"]\033\\bar\n"))
+		i := log.NewIngester(l, log.WithRules(log.Rule{Pattern: regexp.MustCompile("baz"), Tags: []string{"c"}}))
+		i.Write([]byte("baz\n"))
+
+		assert.Equal(t, ""+
+			"[INFO][D:A][D:RUN:"+strings.ToUpper(log.RunID())+"] foo\n"+
+			"[WARN][D:A][D:B] bar\n"+
+			"[INFO][D:A][D:C] baz\n", b.String())
+	})
+
+	t.Run("nest prefixes", func(t *testing.T) {
+		l := log.New(nil).WithTagPrefix("deploy:").WithTagPrefix("helm:").WithTags("chart")
+
+		assert.Equal(t, []string{"deploy:helm:chart"}, l.Tags())
+	})
+}
@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// TailPollInterval is the interval in which Tail checks files for new content.
+var TailPollInterval = 250 * time.Millisecond
+
+// Tail follows the file (like "tail -F") and streams new lines through Ingester writing to the logger, until the
+// context is cancelled. If the file exists, only lines appended after calling Tail are processed. Otherwise Tail waits
+// for the file to be created. Rotated (renamed or removed and created again) and truncated files are read from the
+// beginning.
+func Tail(ctx context.Context, path string, l *Logger, opts ...IngestOption) error {
+	ing := NewIngester(l, opts...)
+	defer ing.Close()
+
+	var f *os.File
+	defer func() {
+		if f != nil {
+			f.Close()
+		}
+	}()
+
+	buf := make([]byte, 32*1024)
+	first := true
+	ticker := time.NewTicker(TailPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if f == nil {
+			var err error
+			f, err = os.Open(path)
+			switch {
+			case err == nil && first:
+				if _, err := f.Seek(0, io.SeekEnd); err != nil {
+					return err
+				}
+			case os.IsNotExist(err):
+				f = nil
+			case err != nil:
+				return err
+			}
+			first = false
+		}
+
+		if f != nil {
+			for {
+				n, err := f.Read(buf)
+				ing.Write(buf[:n])
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					return err
+				}
+			}
+
+			pos, err := f.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return err
+			}
+			current, err := f.Stat()
+			if err != nil {
+				return err
+			}
+			if info, err := os.Stat(path); err != nil || !os.SameFile(info, current) {
+				// File was rotated, open the new one during the next iteration
+				f.Close()
+				f = nil
+			} else if info.Size() < pos {
+				// File was truncated
+				if _, err := f.Seek(0, io.SeekStart); err != nil {
+					return err
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package logger_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+type syncBuffer struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.String()
+}
+
+func TestTail(t *testing.T) {
+	log.TailPollInterval = 10 * time.Millisecond
+	defer func() { log.TailPollInterval = 250 * time.Millisecond }()
+
+	path := filepath.Join(t.TempDir(), "daemon.log")
+	assert.NoError(t, os.WriteFile(path, []byte("old\n"), 0o644))
+
+	var b syncBuffer
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- log.Tail(ctx, path, log.New(&b)) }()
+
+	line := func(msg string) string {
+		return "\033_klio_log_level \"info\"\033\\\033_klio_tags []\033\\" + msg + "\033_klio_reset\033\\\n"
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	f.WriteString("foo\n")
+	f.Close()
+	assert.Eventually(t, func() bool { return b.String() == line("foo") }, time.Second, 10*time.Millisecond)
+
+	// Rotate
+	assert.NoError(t, os.Rename(path, path+".1"))
+	assert.NoError(t, os.WriteFile(path, []byte("bar\n"), 0o644))
+	assert.Eventually(t, func() bool { return b.String() == line("foo")+line("bar") }, time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+}
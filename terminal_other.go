@@ -0,0 +1,7 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly && !windows
+
+package logger
+
+func isTerminal(fd uintptr) bool {
+	return false
+}
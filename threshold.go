@@ -0,0 +1,52 @@
+package logger
+
+var levelSeverity = map[Level]int{
+	FatalLevel:   0,
+	ErrorLevel:   1,
+	WarnLevel:    2,
+	InfoLevel:    3,
+	VerboseLevel: 4,
+	DebugLevel:   5,
+	SpamLevel:    6,
+}
+
+// Enabled reports whether lines logged at the level pass the threshold, i.e. level is at least as severe as threshold.
+// Empty threshold and unknown levels always pass.
+func (level Level) Enabled(threshold Level) bool {
+	if threshold == "" {
+		return true
+	}
+	l, ok := levelSeverity[level]
+	if !ok {
+		return true
+	}
+	t, ok := levelSeverity[threshold]
+	if !ok {
+		return true
+	}
+	return l <= t
+}
+
+// Threshold returns the least severe level written by a logger, empty if a logger doesn't filter lines.
+func (l *Logger) Threshold() Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.threshold
+}
+
+// WithThreshold creates new logger instance discarding lines less severe than threshold. By default loggers don't
+// filter lines, leaving it to Klio. Empty threshold disables filtering.
+func (l *Logger) WithThreshold(threshold Level) *Logger {
+	n := l.clone()
+	n.threshold = threshold
+	return n
+}
+
+// enabled reports whether lines logged by a logger should be written. Caller must hold l.mu.
+func (l *Logger) enabled() bool {
+	threshold := l.threshold
+	if t, ok := l.vmodule.threshold(l.name, l.nameParts, l.tags); ok {
+		threshold = t
+	}
+	return l.level.Enabled(threshold)
+}
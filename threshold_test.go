@@ -0,0 +1,29 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestLevelEnabled(t *testing.T) {
+	assert.True(t, log.ErrorLevel.Enabled(log.WarnLevel))
+	assert.True(t, log.WarnLevel.Enabled(log.WarnLevel))
+	assert.False(t, log.InfoLevel.Enabled(log.WarnLevel))
+	assert.True(t, log.SpamLevel.Enabled(""))
+	assert.True(t, log.Level("custom").Enabled(log.FatalLevel))
+}
+
+func TestWithThreshold(t *testing.T) {
+	var b bytes.Buffer
+
+	l := log.New(&b).WithThreshold(log.VerboseLevel)
+	l.WithLevel(log.DebugLevel).Print("foo")
+	l.WithLevel(log.VerboseLevel).Print("bar")
+
+	assert.Equal(t, log.VerboseLevel, l.Threshold())
+	assert.Equal(t, "\033_klio_log_level \"verbose\"\033\\\033_klio_tags []\033\\bar\033_klio_reset\033\\\n", b.String())
+}
@@ -0,0 +1,25 @@
+package logger
+
+// TimeTrack starts measuring time and returns a function logging elapsed time at specified level, e.g.
+// "terraform plan took 1.2s". It is meant to be used with defer:
+//
+//	defer logger.TimeTrack(l, logger.DebugLevel, "terraform plan")()
+func TimeTrack(l *Logger, level Level, name string) func() {
+	start := l.Clock().Now()
+	return func() {
+		l.WithLevel(level).Printf("%s took %s", name, formatDuration(l.since(start)))
+	}
+}
+
+// Timed calls fn and logs its duration, e.g. "terraform plan took 1.2s". If fn returns an error, it is logged at error
+// level, e.g. "terraform plan failed after 1.2s: exit status 1". It returns the error returned by fn.
+func (l *Logger) Timed(name string, fn func() error) error {
+	start := l.Clock().Now()
+	err := fn()
+	if err != nil {
+		l.WithLevel(ErrorLevel).Printf("%s failed after %s: %v", name, formatDuration(l.since(start)), err)
+	} else {
+		l.Printf("%s took %s", name, formatDuration(l.since(start)))
+	}
+	return err
+}
@@ -0,0 +1,40 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestTimeTrack(t *testing.T) {
+	var b bytes.Buffer
+
+	func() {
+		defer log.TimeTrack(log.New(&b).WithOutputMode(log.PlainMode), log.DebugLevel, "terraform plan")()
+	}()
+
+	assert.Regexp(t, regexp.MustCompile(`^\[DEBUG\] terraform plan took \d+(\.\d+)?(ms|s)\n$`), b.String())
+}
+
+func TestTimed(t *testing.T) {
+	t.Run("log duration", func(t *testing.T) {
+		var b bytes.Buffer
+		err := log.New(&b).WithOutputMode(log.PlainMode).Timed("terraform plan", func() error { return nil })
+
+		assert.NoError(t, err)
+		assert.Regexp(t, regexp.MustCompile(`^\[INFO\] terraform plan took \d+(\.\d+)?(ms|s)\n$`), b.String())
+	})
+
+	t.Run("log error", func(t *testing.T) {
+		var b bytes.Buffer
+		err := log.New(&b).WithOutputMode(log.PlainMode).Timed("terraform plan", func() error { return errors.New("boom") })
+
+		assert.EqualError(t, err, "boom")
+		assert.Regexp(t, regexp.MustCompile(`^\[ERROR\] terraform plan failed after \d+(\.\d+)?(ms|s): boom\n$`), b.String())
+	})
+}
@@ -0,0 +1,43 @@
+package logger
+
+// TypedField returns a field holding value of type T. Unlike Field literals, it lets the compiler check the type of
+// values of keys defined using FieldKey.
+func TypedField[T any](key string, value T) Field {
+	return Field{Key: key, Value: value}
+}
+
+// FieldValue returns value of the last field with specified key, and whether it was found and holds a value of type T.
+func FieldValue[T any](fields []Field, key string) (T, bool) {
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i].Key == key {
+			v, ok := fields[i].Value.(T)
+			return v, ok
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// FieldKey is a key of fields holding values of type T. Keys can be declared once and shared, so values of fields are
+// set and read without type assertions:
+//
+//	var UserID log.FieldKey[int] = "user_id"
+//
+//	l = l.WithFields(UserID.Field(42))
+//	id, ok := UserID.Value(l)
+type FieldKey[T any] string
+
+// Field returns a field with the key holding value.
+func (k FieldKey[T]) Field(value T) Field {
+	return TypedField(string(k), value)
+}
+
+// Get returns value of the last field with the key, see FieldValue.
+func (k FieldKey[T]) Get(fields []Field) (T, bool) {
+	return FieldValue[T](fields, string(k))
+}
+
+// Value returns value of the field with the key used by a logger, see FieldValue.
+func (k FieldKey[T]) Value(l *Logger) (T, bool) {
+	return k.Get(l.Fields())
+}
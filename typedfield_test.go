@@ -0,0 +1,54 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestTypedField(t *testing.T) {
+	var b bytes.Buffer
+	log.New(&b).WithOutputMode(log.PlainMode).WithFields(log.TypedField("count", 3)).Print("foo")
+
+	assert.Equal(t, "[INFO] foo count=3\n", b.String())
+}
+
+func TestFieldValue(t *testing.T) {
+	fields := []log.Field{{Key: "a", Value: 1}, {Key: "b", Value: "x"}, {Key: "a", Value: 2}}
+
+	t.Run("return the last field with the key", func(t *testing.T) {
+		v, ok := log.FieldValue[int](fields, "a")
+		assert.True(t, ok)
+		assert.Equal(t, 2, v)
+	})
+
+	t.Run("report value of another type", func(t *testing.T) {
+		v, ok := log.FieldValue[int](fields, "b")
+		assert.False(t, ok)
+		assert.Equal(t, 0, v)
+	})
+
+	t.Run("report missing field", func(t *testing.T) {
+		v, ok := log.FieldValue[string](fields, "c")
+		assert.False(t, ok)
+		assert.Equal(t, "", v)
+	})
+}
+
+func TestFieldKey(t *testing.T) {
+	var userID log.FieldKey[int] = "user_id"
+	var b bytes.Buffer
+	l := log.New(&b).WithOutputMode(log.PlainMode).WithFields(userID.Field(42))
+	l.Print("foo")
+
+	id, ok := userID.Value(l)
+	assert.True(t, ok)
+	assert.Equal(t, 42, id)
+	id, ok = userID.Get(nil)
+	assert.False(t, ok)
+	assert.Equal(t, 0, id)
+	assert.Equal(t, "[INFO] foo user_id=42\n", b.String())
+}
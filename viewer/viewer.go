@@ -0,0 +1,245 @@
+// Package viewer serves a web page showing lines written by a command, so output of long-running commands can be
+// inspected in a browser instead of a terminal. The page shows lines kept in a ring buffer and follows new lines
+// live, with level and tag filters:
+//
+//	l, srv, err := viewer.Start(log.StandardLogger(), "localhost:0")
+//	if err != nil {
+//		return err
+//	}
+//	defer srv.Close()
+//
+// The viewer is meant for local use, it doesn't authenticate requests. Requests addressed to other hosts than
+// localhost, loopback addresses or hosts allowed using WithAllowedHosts are rejected, so web pages can't read lines
+// using DNS rebinding. Lines are shown after scrubbing and redaction configured on the logger (e.g.
+// WithSecretDetection), since the ring buffer only receives redacted lines.
+//
+// Lines are numbered in the order they were added to the ring buffer. The page subscribes to new lines first and then
+// loads the lines kept so far, skipping received lines by their numbers, so no line is lost or shown twice.
+package viewer
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	logger "github.com/g2a-com/klio-logger-go"
+)
+
+// DefaultHistory is the number of lines kept by loggers created using Start.
+const DefaultHistory = 10000
+
+//go:embed viewer.html
+var page []byte
+
+// Viewer is an http.Handler serving the page ("/"), lines kept in the ring buffer ("/records") and lines added to the
+// ring buffer afterwards ("/stream", WebSocket).
+type Viewer struct {
+	ring   *logger.RingBuffer
+	stream *logger.WebSocketSink
+	mux    *http.ServeMux
+	hosts  []string
+	mu     sync.Mutex
+	sent   uint64
+}
+
+// Option configures Viewer, see New.
+type Option func(*Viewer)
+
+// WithAllowedHosts lets requests addressed to the hosts (values of the Host header, e.g. "example.com:8080") use the
+// viewer, in addition to localhost and loopback addresses.
+func WithAllowedHosts(hosts ...string) Option {
+	return func(v *Viewer) {
+		v.hosts = append(v.hosts, hosts...)
+	}
+}
+
+// New creates new Viewer showing lines kept in the ring buffer. New lines are streamed whenever its sink receives a
+// line (see Sink).
+func New(ring *logger.RingBuffer, opts ...Option) *Viewer {
+	v := &Viewer{ring: ring, stream: logger.NewWebSocketSink(), mux: http.NewServeMux()}
+	for _, opt := range opts {
+		opt(v)
+	}
+	v.mux.HandleFunc("/", v.servePage)
+	v.mux.HandleFunc("/records", v.serveRecords)
+	v.mux.Handle("/stream", v.stream)
+	return v
+}
+
+// Sink returns sink which should be added to loggers (see logger.Logger.WithSink), so their lines are shown live. Lines
+// are sent from the ring buffer, lines discarded by the threshold are sent together with the next written line.
+func (v *Viewer) Sink() logger.Sink {
+	return sink{v}
+}
+
+// Connections returns the number of pages receiving new lines.
+func (v *Viewer) Connections() int {
+	return v.stream.Connections()
+}
+
+// ServeHTTP serves the viewer. Requests addressed to hosts which are not allowed are rejected.
+func (v *Viewer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !v.allowsHost(r.Host) {
+		http.Error(w, "Host not allowed", http.StatusForbidden)
+		return
+	}
+	v.mux.ServeHTTP(w, r)
+}
+
+// allowsHost reports whether the value of the Host header refers to localhost, a loopback address or an allowed host.
+func (v *Viewer) allowsHost(host string) bool {
+	for _, h := range v.hosts {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// sendNew streams lines added to the ring buffer since the previous call.
+func (v *Viewer) sendNew() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	records, last := v.ring.RecordsSince(v.sent)
+	first := last - uint64(len(records)) + 1
+	for i, rec := range records {
+		if data, err := json.Marshal(newRecord(first+uint64(i), rec)); err == nil {
+			v.stream.WriteMessage(data)
+		}
+	}
+	v.sent = last
+}
+
+// sink triggers streaming of new lines of the viewer.
+type sink struct {
+	v *Viewer
+}
+
+func (s sink) WriteRecord(t time.Time, r logger.Record) error {
+	s.v.sendNew()
+	return nil
+}
+
+// Close disconnects pages following new lines.
+func (v *Viewer) Close() error {
+	return v.stream.Close()
+}
+
+func (v *Viewer) servePage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(page)
+}
+
+// serveRecords writes lines kept in the ring buffer as a JSON array, with their sequence numbers. Lines can be filtered
+// using "level" (the least severe level) and "tag" query parameters.
+func (v *Viewer) serveRecords(w http.ResponseWriter, r *http.Request) {
+	threshold := logger.Level("")
+	if s := r.URL.Query().Get("level"); s != "" {
+		level, ok := logger.ParseLevel(s)
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown level %q", s), http.StatusBadRequest)
+			return
+		}
+		threshold = level
+	}
+	tag := r.URL.Query().Get("tag")
+
+	records := []record{}
+	stored, last := v.ring.RecordsSince(0)
+	first := last - uint64(len(stored)) + 1
+	for i, rec := range stored {
+		if rec.Level.Enabled(threshold) && (tag == "" || hasTag(rec.Tags, tag)) {
+			records = append(records, newRecord(first+uint64(i), rec))
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(records)
+}
+
+// record is JSON representation of a line, used by "/records" and "/stream".
+type record struct {
+	Seq     uint64                 `json:"seq"`
+	Level   logger.Level           `json:"level"`
+	Tags    []string               `json:"tags"`
+	Message string                 `json:"message"`
+	Fields  map[string]interface{} `json:"fields"`
+}
+
+func newRecord(seq uint64, r logger.Record) record {
+	tags := r.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	fields := make(map[string]interface{}, len(r.Fields))
+	for _, f := range r.Fields {
+		if _, err := json.Marshal(f.Value); err != nil {
+			fields[f.Key] = fmt.Sprint(f.Value)
+		} else {
+			fields[f.Key] = f.Value
+		}
+	}
+	return record{Seq: seq, Level: r.Level, Tags: tags, Message: r.Message, Fields: fields}
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Server is a viewer started using Start.
+type Server struct {
+	*Viewer
+	srv *http.Server
+	url string
+}
+
+// Start serves a viewer on addr (e.g. "localhost:0" to pick a free port) and returns logger derived from l, which lines
+// are shown by the viewer. URL of the viewer is written using l. Requests addressed to the address of the listener are
+// allowed, in addition to hosts allowed by options.
+func Start(l *logger.Logger, addr string, opts ...Option) (*logger.Logger, *Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ring := logger.NewRingBuffer(DefaultHistory)
+	opts = append([]Option{WithAllowedHosts(ln.Addr().String())}, opts...)
+	s := &Server{Viewer: New(ring, opts...), url: "http://" + ln.Addr().String() + "/"}
+	s.srv = &http.Server{Handler: s.Viewer}
+	go s.srv.Serve(ln)
+
+	l.Printf("Log viewer is available at %s", s.url)
+	return l.WithRingBuffer(ring).WithSink(s.Sink()), s, nil
+}
+
+// URL returns address of the viewer.
+func (s *Server) URL() string {
+	return s.url
+}
+
+// Close stops the server.
+func (s *Server) Close() error {
+	s.Viewer.Close()
+	return s.srv.Close()
+}
@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// VModuleRule sets threshold for loggers matching the pattern.
+type VModuleRule struct {
+	// Pattern is matched (using path.Match) against logger name, each dot-separated component of the name and each tag.
+	Pattern string
+	// Threshold used by matching loggers.
+	Threshold Level
+}
+
+// VModule is an ordered list of rules overriding threshold for selected loggers (see Logger.WithThreshold), which
+// allows to debug single subsystem of a command without enabling verbose output globally. The first matching rule
+// wins, loggers not matched by any rule use their own threshold.
+type VModule []VModuleRule
+
+// ParseVModule parses comma-separated list of pattern=level pairs, e.g. "helm=debug,git=spam".
+func ParseVModule(spec string) (VModule, error) {
+	v := VModule{}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		i := strings.LastIndex(item, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid vmodule rule %q: expected pattern=level", item)
+		}
+		pattern, name := strings.TrimSpace(item[:i]), strings.TrimSpace(item[i+1:])
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid vmodule rule %q: %w", item, err)
+		}
+		level, ok := ParseLevel(name)
+		if !ok {
+			return nil, fmt.Errorf("invalid vmodule rule %q: unknown level %q", item, name)
+		}
+		v = append(v, VModuleRule{Pattern: pattern, Threshold: level})
+	}
+	return v, nil
+}
+
+// String returns spec in the format accepted by ParseVModule.
+func (v VModule) String() string {
+	items := make([]string, len(v))
+	for i, r := range v {
+		items[i] = r.Pattern + "=" + string(r.Threshold)
+	}
+	return strings.Join(items, ",")
+}
+
+// VModule returns rules used by a logger.
+func (l *Logger) VModule() VModule {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	r := make(VModule, len(l.vmodule))
+	copy(r, l.vmodule)
+	return r
+}
+
+// WithVModule creates new logger instance using specified rules.
+func (l *Logger) WithVModule(v VModule) *Logger {
+	n := l.clone()
+	n.vmodule = v
+	return n
+}
+
+// threshold returns threshold of the first rule matching the logger name, its dot-separated components or tags.
+func (v VModule) threshold(name string, components, tags []string) (Level, bool) {
+	for _, r := range v {
+		if r.matches(name, components, tags) {
+			return r.Threshold, true
+		}
+	}
+	return "", false
+}
+
+func (r VModuleRule) matches(name string, components, tags []string) bool {
+	if name != "" {
+		if ok, _ := path.Match(r.Pattern, name); ok {
+			return true
+		}
+		for _, component := range components {
+			if ok, _ := path.Match(r.Pattern, component); ok {
+				return true
+			}
+		}
+	}
+	for _, tag := range tags {
+		if ok, _ := path.Match(r.Pattern, tag); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func vmoduleFromEnv() VModule {
+	v, err := ParseVModule(os.Getenv(EnvVModule))
+	if err != nil {
+		return nil
+	}
+	return v
+}
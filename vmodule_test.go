@@ -0,0 +1,65 @@
+package logger_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestParseVModule(t *testing.T) {
+	t.Run("parse valid spec", func(t *testing.T) {
+		v, err := log.ParseVModule("helm=debug, git*=SPAM,")
+		assert.NoError(t, err)
+		assert.Equal(t, log.VModule{{Pattern: "helm", Threshold: log.DebugLevel}, {Pattern: "git*", Threshold: log.SpamLevel}}, v)
+		assert.Equal(t, "helm=debug,git*=spam", v.String())
+	})
+
+	t.Run("parse empty spec", func(t *testing.T) {
+		v, err := log.ParseVModule("")
+		assert.NoError(t, err)
+		assert.Equal(t, log.VModule{}, v)
+	})
+
+	t.Run("return error for invalid spec", func(t *testing.T) {
+		_, err := log.ParseVModule("helm")
+		assert.Error(t, err)
+		_, err = log.ParseVModule("helm=loud")
+		assert.Error(t, err)
+		_, err = log.ParseVModule("[=debug")
+		assert.Error(t, err)
+	})
+}
+
+func TestWithVModule(t *testing.T) {
+	var b bytes.Buffer
+
+	v, _ := log.ParseVModule("helm=debug,git=spam")
+	l := log.New(&b).WithThreshold(log.InfoLevel).WithVModule(v).WithLevel(log.DebugLevel)
+
+	l.WithName("deploy").Print("deploy")
+	l.WithName("deploy").WithName("helm").Print("helm")
+	l.WithName("helm").WithLevel(log.SpamLevel).Print("helm spam")
+	l.WithTags("git").WithLevel(log.SpamLevel).Print("git")
+
+	assert.Equal(t, v, l.VModule())
+	assert.Equal(
+		t,
+		"\033_klio_log_level \"debug\"\033\\\033_klio_tags [\"deploy.helm\"]\033\\helm\033_klio_reset\033\\\n"+
+			"\033_klio_log_level \"spam\"\033\\\033_klio_tags [\"git\"]\033\\git\033_klio_reset\033\\\n",
+		b.String(),
+	)
+}
+
+func BenchmarkVModuleDisabled(b *testing.B) {
+	v, _ := log.ParseVModule("helm=debug,git=spam")
+	l := log.New(io.Discard).WithThreshold(log.InfoLevel).WithVModule(v).WithName("deploy").WithName("kubectl").
+		WithLevel(log.DebugLevel)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Print("foo")
+	}
+}
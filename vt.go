@@ -0,0 +1,29 @@
+package logger
+
+import (
+	"os"
+	"sync/atomic"
+)
+
+// EnableVirtualTerminal enables virtual terminal processing of Windows consoles attached to the stdout and stderr, so
+// they show colors instead of raw escape sequences. Afterwards, the standard and error loggers, as well as loggers
+// created later, use ColorMode when writing to such consoles (see Mode). On other platforms it does nothing.
+func EnableVirtualTerminal() error {
+	for _, f := range []*os.File{os.Stdout, os.Stderr} {
+		if err := enableVirtualTerminal(f.Fd()); err != nil {
+			return err
+		}
+	}
+	standardLogger.refreshOutputMode()
+	errorLogger.refreshOutputMode()
+	return nil
+}
+
+// refreshOutputMode resolves output mode again, since the environment may have changed.
+func (l *Logger) refreshOutputMode() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	atomic.AddUint64(&l.generation, 1)
+	l.format = resolveMode(l.mode, l.output)
+	l.updateLinePrefix()
+}
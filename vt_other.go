@@ -0,0 +1,13 @@
+//go:build !windows
+
+package logger
+
+// supportsANSI reports whether the terminal interprets ANSI escape sequences.
+func supportsANSI(fd uintptr) bool {
+	return true
+}
+
+// enableVirtualTerminal does nothing, terminals interpret ANSI escape sequences on this platform.
+func enableVirtualTerminal(fd uintptr) error {
+	return nil
+}
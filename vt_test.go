@@ -0,0 +1,16 @@
+package logger_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestEnableVirtualTerminal(t *testing.T) {
+	mode := log.StandardLogger().OutputMode()
+
+	assert.NoError(t, log.EnableVirtualTerminal())
+	assert.Equal(t, mode, log.StandardLogger().OutputMode())
+}
@@ -0,0 +1,29 @@
+package logger
+
+import "syscall"
+
+const enableVirtualTerminalProcessing = 0x0004
+
+// supportsANSI reports whether the terminal interprets ANSI escape sequences. Legacy Windows consoles do so only if
+// virtual terminal processing is enabled.
+func supportsANSI(fd uintptr) bool {
+	var mode uint32
+	if err := syscall.GetConsoleMode(syscall.Handle(fd), &mode); err != nil {
+		return false
+	}
+	return mode&enableVirtualTerminalProcessing != 0
+}
+
+var procSetConsoleMode = syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+
+// enableVirtualTerminal enables virtual terminal processing of the console. Handles which are not consoles are ignored.
+func enableVirtualTerminal(fd uintptr) error {
+	var mode uint32
+	if err := syscall.GetConsoleMode(syscall.Handle(fd), &mode); err != nil || mode&enableVirtualTerminalProcessing != 0 {
+		return nil
+	}
+	if r, _, err := procSetConsoleMode.Call(fd, uintptr(mode|enableVirtualTerminalProcessing)); r == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// writes is the number of lines written by all loggers. It is updated only while silenceWatchers is not zero, so
+	// loggers don't pay for it when nothing watches.
+	writes uint64
+	// silenceWatchers is the number of running WatchSilence goroutines.
+	silenceWatchers int32
+)
+
+// WatchSilence writes a warning whenever no logger wrote anything for timeout, which may indicate that a command is
+// deadlocked. Silence is measured using the clock of the logger. If dumpGoroutines is true, the warning contains stack
+// traces of all goroutines. Watching continues until the context is cancelled or the returned function is called.
+func (l *Logger) WatchSilence(ctx context.Context, timeout time.Duration, dumpGoroutines bool) func() {
+	l = l.WithLevel(WarnLevel)
+	atomic.AddInt32(&silenceWatchers, 1)
+	seen, lastWrite := atomic.LoadUint64(&writes), l.Clock().Now()
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		defer atomic.AddInt32(&silenceWatchers, -1)
+		ticker := time.NewTicker(timeout / 4)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if n := atomic.LoadUint64(&writes); n != seen {
+					seen, lastWrite = n, l.Clock().Now()
+					continue
+				}
+				silence := l.since(lastWrite)
+				if silence < timeout {
+					continue
+				}
+				msg := "No output for " + formatDuration(silence)
+				if dumpGoroutines {
+					msg += ", goroutines:\n" + goroutineDump()
+				}
+				l.Print(msg)
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// noteWrite remembers that a line was written, if any WatchSilence goroutine is running.
+func noteWrite() {
+	if atomic.LoadInt32(&silenceWatchers) == 0 {
+		return
+	}
+	atomic.AddUint64(&writes, 1)
+}
+
+// goroutineDump returns stack traces of all goroutines.
+func goroutineDump() string {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -0,0 +1,60 @@
+package logger_test
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestWatchSilence(t *testing.T) {
+	t.Run("warn about silence", func(t *testing.T) {
+		var b syncBuffer
+		stop := log.New(&b).WithOutputMode(log.PlainMode).WatchSilence(context.Background(), 40*time.Millisecond, false)
+		defer stop()
+
+		assert.Eventually(t, func() bool { return b.String() != "" }, time.Second, 5*time.Millisecond)
+		assert.Regexp(t, regexp.MustCompile(`^\[WARN\] No output for \d+ms\n`), b.String())
+	})
+
+	t.Run("dump goroutines", func(t *testing.T) {
+		var b syncBuffer
+		stop := log.New(&b).WithOutputMode(log.PlainMode).WatchSilence(context.Background(), 40*time.Millisecond, true)
+		defer stop()
+
+		assert.Eventually(t, func() bool { return b.String() != "" }, time.Second, 5*time.Millisecond)
+		stop()
+		assert.True(t, strings.HasPrefix(b.String(), "[WARN] No output for "))
+		assert.Contains(t, b.String(), ", goroutines:\ngoroutine ")
+	})
+
+	t.Run("measure silence using clock of the logger", func(t *testing.T) {
+		var b syncBuffer
+		clock := &fakeClock{now: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)}
+		stop := log.New(&b).WithOutputMode(log.PlainMode).WithClock(clock).WatchSilence(context.Background(), 20*time.Millisecond, false)
+
+		time.Sleep(100 * time.Millisecond)
+		stop()
+
+		assert.Empty(t, b.String())
+	})
+
+	t.Run("don't warn while logging", func(t *testing.T) {
+		var b syncBuffer
+		l := log.New(&b).WithOutputMode(log.PlainMode)
+		stop := l.WatchSilence(context.Background(), 200*time.Millisecond, false)
+
+		for i := 0; i < 10; i++ {
+			l.Print("working")
+			time.Sleep(10 * time.Millisecond)
+		}
+		stop()
+
+		assert.Equal(t, strings.Repeat("[INFO] working\n", 10), b.String())
+	})
+}
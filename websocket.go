@@ -0,0 +1,425 @@
+package logger
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebSocketQueueSize is the number of records queued for each WebSocket connection. Records sent to connections which
+// can't keep up are dropped.
+const WebSocketQueueSize = 256
+
+// WebSocketWriteTimeout limits time of writing a single message, connections which exceed it are closed.
+const WebSocketWriteTimeout = 10 * time.Second
+
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket opcodes, see https://datatracker.ietf.org/doc/html/rfc6455#section-5.2.
+const (
+	wsText  = 0x1
+	wsClose = 0x8
+	wsPing  = 0x9
+	wsPong  = 0xA
+)
+
+// WebSocketSink streams records as JSON text messages over WebSocket connections, so viewers (e.g. in a browser) can
+// follow a command in real time. It either serves viewers, as an http.Handler:
+//
+//	sink := log.NewWebSocketSink()
+//	go http.ListenAndServe("localhost:8080", sink)
+//	l = l.WithSink(sink)
+//
+// or connects to a server accepting records, see DialWebSocketSink. Each message is an object with "time", "level",
+// "tags", "message", "fields" and "run_id" keys.
+type WebSocketSink struct {
+	mu      sync.Mutex
+	conns   map[*wsConn]struct{}
+	origins []string
+	dropped int64
+	closed  bool
+}
+
+// WebSocketOption configures WebSocketSink, see NewWebSocketSink.
+type WebSocketOption func(*WebSocketSink)
+
+// WithAllowedOrigins lets web pages served from the origins (e.g. "https://example.com", or "*" for any origin) connect
+// to WebSocketSink. By default, browsers may only connect from pages served by the same host as the sink, so other
+// websites visited by the user can't read records.
+func WithAllowedOrigins(origins ...string) WebSocketOption {
+	return func(s *WebSocketSink) {
+		s.origins = append(s.origins, origins...)
+	}
+}
+
+// NewWebSocketSink creates new WebSocketSink streaming records to connected viewers. It should be closed when no
+// longer needed.
+func NewWebSocketSink(opts ...WebSocketOption) *WebSocketSink {
+	s := &WebSocketSink{conns: map[*wsConn]struct{}{}}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// DialWebSocketSink connects to the WebSocket server (ws:// or wss:// URL) and returns WebSocketSink streaming records
+// to it. Records written after the connection is closed by the server are dropped.
+func DialWebSocketSink(rawURL string, header http.Header) (*WebSocketSink, error) {
+	c, err := dialWebSocket(rawURL, header)
+	if err != nil {
+		return nil, err
+	}
+	s := NewWebSocketSink()
+	s.add(c)
+	return s, nil
+}
+
+// ServeHTTP accepts WebSocket connections of viewers.
+func (s *WebSocketSink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if r.Method != http.MethodGet || key == "" || !headerContains(r.Header, "Connection", "upgrade") ||
+		!headerContains(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "WebSocket connection expected", http.StatusBadRequest)
+		return
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "Unsupported WebSocket version", http.StatusUpgradeRequired)
+		return
+	}
+	if !s.allowsOrigin(r) {
+		http.Error(w, "Origin not allowed", http.StatusForbidden)
+		return
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "WebSocket connections are not supported", http.StatusInternalServerError)
+		return
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Accept: %s\r\n\r\n", websocketAccept(key))
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return
+	}
+	s.add(newWSConn(conn, rw.Reader, false))
+}
+
+// allowsOrigin reports whether the request comes from an allowed origin. Requests without Origin header are not sent by
+// browsers, so they are allowed.
+func (s *WebSocketSink) allowsOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, o := range s.origins {
+		if o == "*" || strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// WriteRecord queues the record for all connections, see Sink.
+func (s *WebSocketSink) WriteRecord(t time.Time, r Record) error {
+	data, err := encodeRecord(t, r)
+	if err != nil {
+		return err
+	}
+	return s.WriteMessage(data)
+}
+
+// WriteMessage queues the text message (e.g. JSON encoded in another format than records) for all connections.
+func (s *WebSocketSink) WriteMessage(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return io.ErrClosedPipe
+	}
+	for c := range s.conns {
+		select {
+		case c.queue <- data:
+		default:
+			s.dropped++
+		}
+	}
+	return nil
+}
+
+// Connections returns the number of open connections.
+func (s *WebSocketSink) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// Dropped returns the number of records dropped, because connections couldn't keep up.
+func (s *WebSocketSink) Dropped() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// Close sends queued records and closes all connections.
+func (s *WebSocketSink) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	conns := s.conns
+	s.conns = map[*wsConn]struct{}{}
+	s.mu.Unlock()
+
+	for c := range conns {
+		close(c.queue)
+		<-c.done
+	}
+	return nil
+}
+
+func (s *WebSocketSink) add(c *wsConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		c.conn.Close()
+		return
+	}
+	s.conns[c] = struct{}{}
+	go func() {
+		c.run()
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+	}()
+}
+
+// wsConn is a WebSocket connection sending queued messages.
+type wsConn struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	r      *bufio.Reader
+	masked bool
+	queue  chan []byte
+	done   chan struct{}
+}
+
+func newWSConn(conn net.Conn, r *bufio.Reader, masked bool) *wsConn {
+	return &wsConn{
+		conn:   conn,
+		r:      r,
+		masked: masked,
+		queue:  make(chan []byte, WebSocketQueueSize),
+		done:   make(chan struct{}),
+	}
+}
+
+// run writes queued messages until the queue is closed or the peer closes the connection.
+func (c *wsConn) run() {
+	defer close(c.done)
+	defer c.conn.Close()
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		c.readFrames()
+	}()
+
+	for {
+		select {
+		case data, ok := <-c.queue:
+			if !ok {
+				c.writeFrame(wsClose, []byte{0x03, 0xE8}) // normal closure
+				return
+			}
+			if c.writeFrame(wsText, data) != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+// readFrames reads frames sent by the peer, answering pings, until the connection is closed.
+func (c *wsConn) readFrames() {
+	for {
+		opcode, payload, err := readWSFrame(c.r)
+		if err != nil {
+			return
+		}
+		switch opcode {
+		case wsPing:
+			c.writeFrame(wsPong, payload)
+		case wsClose:
+			c.writeFrame(wsClose, payload)
+			return
+		}
+	}
+}
+
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	header := make([]byte, 2, 14)
+	header[0] = 0x80 | opcode // FIN
+	switch n := len(payload); {
+	case n < 126:
+		header[1] = byte(n)
+	case n <= 0xFFFF:
+		header[1] = 126
+		header = header[:4]
+		binary.BigEndian.PutUint16(header[2:], uint16(n))
+	default:
+		header[1] = 127
+		header = header[:10]
+		binary.BigEndian.PutUint64(header[2:], uint64(n))
+	}
+	if c.masked {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		header[1] |= 0x80
+		header = append(header, mask[:]...)
+		payload = maskWSPayload(append([]byte(nil), payload...), mask)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(WebSocketWriteTimeout))
+	_, err := c.conn.Write(append(header, payload...))
+	return err
+}
+
+// readWSFrame reads a single frame, unmasking its payload. Fragmented messages are not reassembled.
+func readWSFrame(r *bufio.Reader) (byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	opcode, masked, size := header[0]&0x0F, header[1]&0x80 != 0, uint64(header[1]&0x7F)
+	switch size {
+	case 126:
+		var b [2]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return 0, nil, err
+		}
+		size = uint64(binary.BigEndian.Uint16(b[:]))
+	case 127:
+		var b [8]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return 0, nil, err
+		}
+		size = binary.BigEndian.Uint64(b[:])
+	}
+	if size > 1<<20 {
+		return 0, nil, errors.New("websocket frame too large")
+	}
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(r, mask[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		maskWSPayload(payload, mask)
+	}
+	return opcode, payload, nil
+}
+
+func maskWSPayload(payload []byte, mask [4]byte) []byte {
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return payload
+}
+
+// dialWebSocket performs the opening handshake as a client.
+func dialWebSocket(rawURL string, header http.Header) (*wsConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), map[string]string{"ws": "80", "wss": "443"}[u.Scheme])
+	}
+
+	var conn net.Conn
+	switch u.Scheme {
+	case "ws":
+		conn, err = net.Dial("tcp", host)
+	case "wss":
+		conn, err = tls.Dial("tcp", host, &tls.Config{ServerName: u.Hostname()})
+	default:
+		return nil, fmt.Errorf("unsupported WebSocket URL scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce [16]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce[:])
+	req := &http.Request{Method: http.MethodGet, URL: u, Host: u.Host, Header: http.Header{}}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != websocketAccept(key) {
+		conn.Close()
+		return nil, fmt.Errorf("WebSocket handshake failed: %s", resp.Status)
+	}
+	return newWSConn(conn, r, true), nil
+}
+
+func websocketAccept(key string) string {
+	h := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// headerContains reports whether comma-separated values of the header contain the token (case-insensitive).
+func headerContains(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+package logger_test
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+// readFrame reads a single unfragmented WebSocket frame.
+func readFrame(t *testing.T, r *bufio.Reader) (byte, string) {
+	header := make([]byte, 2)
+	_, err := io.ReadFull(r, header)
+	assert.NoError(t, err)
+	size := int(header[1] & 0x7F)
+	if size == 126 {
+		b := make([]byte, 2)
+		io.ReadFull(r, b)
+		size = int(binary.BigEndian.Uint16(b))
+	}
+	var mask []byte
+	if header[1]&0x80 != 0 {
+		mask = make([]byte, 4)
+		io.ReadFull(r, mask)
+	}
+	payload := make([]byte, size)
+	_, err = io.ReadFull(r, payload)
+	assert.NoError(t, err)
+	for i := range mask {
+		for j := i; j < len(payload); j += 4 {
+			payload[j] ^= mask[i]
+		}
+	}
+	return header[0] & 0x0F, string(payload)
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWebSocketSink(t *testing.T) {
+	start := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	t.Run("stream records to viewers", func(t *testing.T) {
+		sink := log.NewWebSocketSink()
+		server := httptest.NewServer(sink)
+		defer server.Close()
+
+		conn, err := net.Dial("tcp", server.Listener.Addr().String())
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+		io.WriteString(conn, "GET / HTTP/1.1\r\nHost: localhost\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+			"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n")
+		r := bufio.NewReader(conn)
+		resp, err := http.ReadResponse(r, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+		assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", resp.Header.Get("Sec-WebSocket-Accept"))
+		waitFor(t, func() bool { return sink.Connections() == 1 })
+
+		l := log.New(io.Discard).WithClock(&fakeClock{now: start}).WithSink(sink)
+		l.WithTags("deploy").WithField("release", "api").Print("foo")
+		l.Print(strings.Repeat("x", 200))
+
+		opcode, payload := readFrame(t, r)
+		assert.Equal(t, byte(1), opcode)
+		assert.JSONEq(t, `{"time":"2021-03-04T05:06:07Z","level":"info","tags":["deploy"],"message":"foo","fields":{"release":"api"},"run_id":"`+log.RunID()+`"}`, payload)
+		_, payload = readFrame(t, r)
+		assert.Contains(t, payload, strings.Repeat("x", 200))
+
+		assert.NoError(t, sink.Close())
+		opcode, _ = readFrame(t, r)
+		assert.Equal(t, byte(8), opcode)
+		assert.Equal(t, io.ErrClosedPipe, sink.WriteRecord(start, log.Record{}))
+	})
+
+	t.Run("reject other requests", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		log.NewWebSocketSink().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("reject unsupported versions", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		log.NewWebSocketSink().ServeHTTP(w, upgradeRequest("8", ""))
+		assert.Equal(t, http.StatusUpgradeRequired, w.Code)
+		assert.Equal(t, "13", w.Header().Get("Sec-WebSocket-Version"))
+	})
+
+	t.Run("reject other origins", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		log.NewWebSocketSink().ServeHTTP(w, upgradeRequest("13", "https://evil.example"))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w = httptest.NewRecorder()
+		log.NewWebSocketSink(log.WithAllowedOrigins("https://other.example")).ServeHTTP(w, upgradeRequest("13", "https://evil.example"))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("accept allowed origins", func(t *testing.T) {
+		for _, tc := range []struct {
+			origin  string
+			allowed []string
+		}{
+			{origin: "http://localhost:8080"},
+			{origin: "https://viewer.example", allowed: []string{"https://viewer.example"}},
+			{origin: "https://viewer.example", allowed: []string{"*"}},
+		} {
+			// The recorder can't be hijacked, so accepted requests fail at this point
+			w := httptest.NewRecorder()
+			log.NewWebSocketSink(log.WithAllowedOrigins(tc.allowed...)).ServeHTTP(w, upgradeRequest("13", tc.origin))
+			assert.Equal(t, http.StatusInternalServerError, w.Code, tc.origin)
+		}
+	})
+
+	t.Run("connect to a server", func(t *testing.T) {
+		received := make(chan string, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer abc", r.Header.Get("Authorization"))
+			conn, rw, _ := w.(http.Hijacker).Hijack()
+			defer conn.Close()
+			accept := acceptKey(r.Header.Get("Sec-WebSocket-Key"))
+			rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: " + accept + "\r\n\r\n")
+			rw.Flush()
+			_, payload := readFrame(t, rw.Reader)
+			received <- payload
+		}))
+		defer server.Close()
+
+		sink, err := log.DialWebSocketSink("ws"+strings.TrimPrefix(server.URL, "http"), http.Header{"Authorization": {"Bearer abc"}})
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer sink.Close()
+		assert.NoError(t, sink.WriteRecord(start, log.Record{Level: log.InfoLevel, Message: "foo"}))
+
+		select {
+		case payload := <-received:
+			assert.Contains(t, payload, `"message":"foo"`)
+		case <-time.After(5 * time.Second):
+			t.Fatal("record not received")
+		}
+	})
+
+	t.Run("fail on invalid handshake", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		_, err := log.DialWebSocketSink("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+		assert.EqualError(t, err, "WebSocket handshake failed: 404 Not Found")
+	})
+}
+
+// upgradeRequest returns WebSocket handshake request sent to localhost:8080.
+func upgradeRequest(version, origin string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "http://localhost:8080/", nil)
+	r.Header.Set("Connection", "Upgrade")
+	r.Header.Set("Upgrade", "websocket")
+	r.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	r.Header.Set("Sec-WebSocket-Version", version)
+	if origin != "" {
+		r.Header.Set("Origin", origin)
+	}
+	return r
+}
+
+func acceptKey(key string) string {
+	h := sha1.Sum([]byte(key + "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
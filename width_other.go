@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly && !windows
+
+package logger
+
+// terminalWidth returns 0, terminal width cannot be detected on this platform.
+func terminalWidth(fd uintptr) int {
+	return 0
+}
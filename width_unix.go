@@ -0,0 +1,47 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	widthMu      sync.Mutex
+	widthCache   = map[uintptr]int{}
+	widthWatcher sync.Once
+)
+
+// terminalWidth returns number of columns of the terminal or 0 if fd is not a terminal. Widths are cached until the
+// process receives SIGWINCH.
+func terminalWidth(fd uintptr) int {
+	widthWatcher.Do(func() {
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, syscall.SIGWINCH)
+		go func() {
+			for range ch {
+				widthMu.Lock()
+				widthCache = map[uintptr]int{}
+				widthMu.Unlock()
+			}
+		}()
+	})
+
+	widthMu.Lock()
+	defer widthMu.Unlock()
+	if w, ok := widthCache[fd]; ok {
+		return w
+	}
+
+	var ws struct{ Row, Col, Xpixel, Ypixel uint16 }
+	w := 0
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&ws))); errno == 0 {
+		w = int(ws.Col)
+	}
+	widthCache[fd] = w
+	return w
+}
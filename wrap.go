@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// minWrapWidth is the least width available for a message which makes wrapping worth it.
+const minWrapWidth = 20
+
+// WrapWidth returns width to which long messages are wrapped in PlainMode and ColorMode, 0 means terminal width and
+// negative value disables wrapping.
+func (l *Logger) WrapWidth() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.wrap
+}
+
+// WithWrapWidth creates new logger instance wrapping long messages to specified width, instead of width of the
+// terminal. Negative width disables wrapping. Messages are wrapped only in PlainMode and ColorMode.
+func (l *Logger) WithWrapWidth(width int) *Logger {
+	n := l.clone()
+	n.wrap = width
+	return n
+}
+
+// lineWidth returns width to which messages should be wrapped or 0 if they shouldn't be.
+func (o *options) lineWidth() int {
+	if o.wrap < 0 || (o.format != PlainMode && o.format != ColorMode) {
+		return 0
+	}
+	if o.wrap > 0 {
+		return o.wrap
+	}
+	if f, ok := o.output.(interface{ Fd() uintptr }); ok {
+		return terminalWidth(f.Fd())
+	}
+	return 0
+}
+
+// wrapMessage breaks lines of the message at spaces, so they fit in width. Continuation lines are indented, so they are
+// aligned with the first line written after the prefix. Words longer than the line are not broken.
+func wrapMessage(msg string, width, indent int) string {
+	available := width - indent
+	if available < minWrapWidth {
+		return msg
+	}
+
+	pad := "\n" + strings.Repeat(" ", indent)
+	var b strings.Builder
+	for i, line := range strings.Split(msg, "\n") {
+		if i > 0 {
+			b.WriteString(pad)
+		}
+		col := 0
+		for j, word := range strings.Split(line, " ") {
+			w := visibleWidth(word)
+			if j > 0 {
+				if col > 0 && col+1+w > available {
+					b.WriteString(pad)
+					col = 0
+				} else {
+					b.WriteByte(' ')
+					col++
+				}
+			}
+			b.WriteString(word)
+			col += w
+		}
+	}
+	return b.String()
+}
+
+// visibleWidth returns number of characters in s, not counting ANSI escape sequences.
+func visibleWidth(s string) int {
+	n := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\033' && i+1 < len(s) && s[i+1] == '[' {
+			i += 2
+			for i < len(s) && (s[i] < 0x40 || s[i] > 0x7e) {
+				i++
+			}
+			i++
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		n++
+	}
+	return n
+}
@@ -0,0 +1,86 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	log "github.com/g2a-com/klio-logger-go"
+)
+
+func TestWrapWidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     log.OutputMode
+		width    int
+		msg      string
+		expected string
+	}{
+		{
+			name:  "wrap with hanging indentation",
+			mode:  log.PlainMode,
+			width: 42,
+			msg:   "the quick brown fox jumps over the lazy dog and keeps running",
+			expected: "" +
+				"[INFO][FOO] the quick brown fox jumps over\n" +
+				"            the lazy dog and keeps running\n",
+		},
+		{
+			name:  "indent existing lines",
+			mode:  log.PlainMode,
+			width: 40,
+			msg:   "first\nsecond",
+			expected: "" +
+				"[INFO][FOO] first\n" +
+				"            second\n",
+		},
+		{
+			name:     "keep long words",
+			mode:     log.PlainMode,
+			width:    40,
+			msg:      "see https://example.com/a/very/long/path/to/some/page",
+			expected: "[INFO][FOO] see\n            https://example.com/a/very/long/path/to/some/page\n",
+		},
+		{
+			name:  "ignore colors",
+			mode:  log.ColorMode,
+			width: 42,
+			msg:   "the \033[33mquick\033[0m brown fox jumps over the lazy",
+			expected: "" +
+				"[INFO]\033[35m[FOO]\033[0m the \033[33mquick\033[0m brown fox jumps over\n" +
+				"            the lazy\n",
+		},
+		{
+			name:     "disable wrapping",
+			mode:     log.PlainMode,
+			width:    -1,
+			msg:      "the quick brown fox jumps over the lazy dog and keeps running",
+			expected: "[INFO][FOO] the quick brown fox jumps over the lazy dog and keeps running\n",
+		},
+		{
+			name:     "skip too narrow lines",
+			mode:     log.PlainMode,
+			width:    25,
+			msg:      "the quick brown fox jumps over the lazy dog",
+			expected: "[INFO][FOO] the quick brown fox jumps over the lazy dog\n",
+		},
+		{
+			name:     "don't wrap klio output",
+			mode:     log.KlioMode,
+			width:    40,
+			msg:      "the quick brown fox jumps over the lazy dog and keeps running",
+			expected: "\033_klio_log_level \"info\"\033\\\033_klio_tags [\"foo\"]\033\\the quick brown fox jumps over the lazy dog and keeps running\033_klio_reset\033\\\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var b bytes.Buffer
+			l := log.New(&b).WithOutputMode(test.mode).WithTags("foo").WithWrapWidth(test.width)
+			l.Print(test.msg)
+			assert.Equal(t, test.width, l.WrapWidth())
+			assert.Equal(t, test.expected, b.String())
+		})
+	}
+}